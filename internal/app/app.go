@@ -1,12 +1,21 @@
 package app
 
 import (
+	"context"
 	"fmt"
+	"time"
 
+	"github.com/liuchen/gin-craft/internal/model"
+	"github.com/liuchen/gin-craft/internal/pkg/audit"
 	"github.com/liuchen/gin-craft/internal/pkg/config"
 	"github.com/liuchen/gin-craft/internal/pkg/cron"
 	"github.com/liuchen/gin-craft/internal/pkg/database"
+	"github.com/liuchen/gin-craft/internal/pkg/lockout"
+	"github.com/liuchen/gin-craft/internal/pkg/ratelimit"
 	"github.com/liuchen/gin-craft/internal/pkg/redis"
+	"github.com/liuchen/gin-craft/internal/pkg/search"
+	"github.com/liuchen/gin-craft/internal/pkg/token"
+	"github.com/liuchen/gin-craft/internal/service"
 	"github.com/liuchen/gin-craft/pkg/logger"
 	"go.uber.org/zap"
 )
@@ -28,11 +37,34 @@ func Init(configPath string) error {
 		return fmt.Errorf("failed to initialize logger: %w", err)
 	}
 
+	if err := token.InitToken(); err != nil {
+		logger.Error("Failed to initialize token manager", zap.Error(err))
+		return fmt.Errorf("failed to initialize token manager: %w", err)
+	}
+
 	if err := database.InitDatabase(); err != nil {
 		logger.Error("Failed to initialize database", zap.Error(err))
 		return fmt.Errorf("failed to initialize database: %w", err)
 	}
 
+	if config.Config.MySQL.AutoMigrate {
+		if err := migrate(); err != nil {
+			logger.Error("Failed to migrate database", zap.Error(err))
+			if closeErr := database.Close(); closeErr != nil {
+				logger.Error("close database on rollback", zap.Error(closeErr))
+			}
+			return fmt.Errorf("failed to migrate database: %w", err)
+		}
+	}
+
+	if err := search.InitSearch(context.Background()); err != nil {
+		logger.Error("Failed to initialize user search", zap.Error(err))
+		if closeErr := database.Close(); closeErr != nil {
+			logger.Error("close database on rollback", zap.Error(closeErr))
+		}
+		return fmt.Errorf("failed to initialize user search: %w", err)
+	}
+
 	if err := redis.InitRedis(); err != nil {
 		logger.Error("Failed to initialize Redis", zap.Error(err))
 		// Redis 失败时回收已建立的 MySQL 连接，避免连接泄漏
@@ -42,14 +74,51 @@ func Init(configPath string) error {
 		return fmt.Errorf("failed to initialize Redis: %w", err)
 	}
 
+	if err := ratelimit.InitRateLimiter(); err != nil {
+		logger.Error("Failed to initialize rate limiter", zap.Error(err))
+		if closeErr := database.Close(); closeErr != nil {
+			logger.Error("close database on rollback", zap.Error(closeErr))
+		}
+		redis.Close()
+		return fmt.Errorf("failed to initialize rate limiter: %w", err)
+	}
+
+	if err := lockout.InitLockout(); err != nil {
+		logger.Error("Failed to initialize login lockout", zap.Error(err))
+		if closeErr := database.Close(); closeErr != nil {
+			logger.Error("close database on rollback", zap.Error(closeErr))
+		}
+		redis.Close()
+		return fmt.Errorf("failed to initialize login lockout: %w", err)
+	}
+
+	audit.InitAudit()
 	cron.InitCron()
 
+	config.Watch(func(err error) {
+		logger.Error("Failed to reload config", zap.Error(err))
+	})
+
 	logger.Info("Application initialized successfully")
 	return nil
 }
 
+// migrate 自动迁移表结构并写入内置角色与权限
+func migrate() error {
+	if err := database.Migrate(model.Models()...); err != nil {
+		return err
+	}
+	return service.RBACService.EnsureDefaults(context.Background())
+}
+
 // Close 关闭应用
 func Close() {
+	// 先写完审计队列再关闭数据库
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := audit.Close(ctx); err != nil {
+		logger.Error("flush audit logs", zap.Error(err))
+	}
 	if err := database.Close(); err != nil {
 		logger.Error("close database", zap.Error(err))
 	}
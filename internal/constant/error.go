@@ -28,6 +28,29 @@ const (
 	UserCreateFailed     = 20008
 	UserUpdateFailed     = 20009
 	UserDeleteFailed     = 20010
+	OIDCProviderNotExist = 20011
+	OIDCStateInvalid     = 20012
+	OIDCLoginFailed      = 20013
+	MFAAlreadyEnabled    = 20014
+	MFANotEnabled        = 20015
+	MFACodeInvalid       = 20016
+	MFATokenInvalid      = 20017
+	AccountTokenInvalid  = 20018
+	EmailAlreadyVerified = 20019
+	PasswordPolicyFailed = 20020
+	InvalidCredentials   = 20021
+	AccountLocked        = 20022
+	UserSuspended        = 20023
+	UserNotDeleted       = 20024
+	UserPending          = 20025
+	UserStatusInvalid    = 20026
+
+	// 权限相关错误码 (201xx)
+	RoleNotExist           = 20101
+	RoleAlreadyExist       = 20102
+	PermissionNotExist     = 20103
+	PermissionAlreadyExist = 20104
+	APIKeyNotExist         = 20105
 
 	// 数据库连接错误码 (202xx)
 	DBConnectionFailed  = 20201
@@ -59,6 +82,29 @@ var ErrorMsg = map[int]string{
 	UserCreateFailed:     "用户创建失败",
 	UserUpdateFailed:     "用户更新失败",
 	UserDeleteFailed:     "用户删除失败",
+	OIDCProviderNotExist: "登录提供方不存在",
+	OIDCStateInvalid:     "登录请求无效或已过期",
+	OIDCLoginFailed:      "第三方登录失败",
+	MFAAlreadyEnabled:    "两步验证已开启",
+	MFANotEnabled:        "两步验证未开启",
+	MFACodeInvalid:       "验证码错误",
+	MFATokenInvalid:      "两步验证已过期，请重新登录",
+	AccountTokenInvalid:  "链接无效或已过期",
+	EmailAlreadyVerified: "邮箱已验证",
+	PasswordPolicyFailed: "密码不符合安全要求",
+	InvalidCredentials:   "用户名或密码错误",
+	AccountLocked:        "登录失败次数过多，请稍后再试",
+	UserSuspended:        "账号已被停用",
+	UserNotDeleted:       "用户未被删除",
+	UserPending:          "账号未激活，请先验证邮箱",
+	UserStatusInvalid:    "不允许的账号状态变更",
+
+	// 权限相关错误信息
+	RoleNotExist:           "角色不存在",
+	RoleAlreadyExist:       "角色已存在",
+	PermissionNotExist:     "权限不存在",
+	PermissionAlreadyExist: "权限已存在",
+	APIKeyNotExist:         "API密钥不存在",
 
 	// 数据库连接错误信息
 	DBConnectionFailed:  "数据库连接失败",
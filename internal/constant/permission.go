@@ -0,0 +1,15 @@
+package constant
+
+// 权限码，格式为 "资源:操作"；"*" 匹配全部权限，"资源:*" 匹配该资源下全部操作
+const (
+	PermAll = "*"
+
+	PermAdminAccess   = "admin:access"   // 访问管理后台
+	PermUserAdmin     = "user:admin"     // 管理任意用户
+	PermUserDelete    = "user:delete"    // 删除用户
+	PermSessionRevoke = "session:revoke" // 吊销他人会话
+	PermRoleManage    = "role:manage"    // 管理角色与权限
+	PermAPIKeyManage  = "apikey:manage"  // 管理 API 密钥
+	PermLockoutManage = "lockout:manage" // 查看与解除登录锁定
+	PermAuditRead     = "audit:read"     // 查看审计日志
+)
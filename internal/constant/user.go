@@ -0,0 +1,15 @@
+package constant
+
+// 用户角色
+const (
+	RoleUser  = "user"
+	RoleAdmin = "admin"
+)
+
+// 用户账号状态，允许的状态迁移见 model.User.CanTransitionTo
+const (
+	UserStatusPending   = "pending"   // 待激活：开启 account.require_verification 时注册后处于该状态，验证邮箱后激活
+	UserStatusActive    = "active"    // 正常
+	UserStatusSuspended = "suspended" // 已停用：禁止登录，已签发的令牌立即失效
+	UserStatusDeleted   = "deleted"   // 已删除：与软删除同时设置，可由管理员恢复
+)
@@ -0,0 +1,241 @@
+package controller
+
+import (
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/liuchen/gin-craft/internal/constant"
+	"github.com/liuchen/gin-craft/internal/dto/user"
+	pkgCtx "github.com/liuchen/gin-craft/internal/pkg/context"
+	"github.com/liuchen/gin-craft/internal/pkg/errors"
+	"github.com/liuchen/gin-craft/internal/pkg/response"
+	"github.com/liuchen/gin-craft/internal/service"
+	"github.com/liuchen/gin-craft/pkg/bulk"
+	"go.uber.org/zap"
+)
+
+// AdminUserController 管理后台用户管理控制器
+type AdminUserController struct{}
+
+// NewAdminUserController 创建管理后台用户管理控制器实例
+func NewAdminUserController() *AdminUserController {
+	return &AdminUserController{}
+}
+
+// List 用户列表
+// @Summary 用户列表（管理员）
+// @Description 按关键字、角色、状态、注册时间筛选用户，可包含已删除用户；sort 为逗号分隔的字段，- 前缀表示倒序
+// @Tags 管理员
+// @Produce json
+// @Security BearerAuth
+// @Param keyword query string false "用户名或邮箱关键字"
+// @Param role query string false "角色"
+// @Param status query string false "状态" Enums(pending, active, suspended, deleted)
+// @Param trashed query string false "with 包含已删除，only 仅已删除" Enums(with, only)
+// @Param created_from query string false "注册时间起，格式 2006-01-02"
+// @Param created_to query string false "注册时间止，格式 2006-01-02"
+// @Param sort query string false "排序，如 -created_at,username"
+// @Param now_page query int false "页码"
+// @Param per_page query int false "每页数量"
+// @Success 200 {object} user.AdminListResponse "用户列表"
+// @Router /api/v1/admin/users [get]
+func (ac *AdminUserController) List(c *gin.Context, req *user.AdminListRequest) (interface{}, error) {
+	return service.AdminUserService.List(c.Request.Context(), req)
+}
+
+// Detail 用户详情
+// @Summary 用户详情（管理员）
+// @Description 获取用户详情，包含已删除用户
+// @Tags 管理员
+// @Produce json
+// @Security BearerAuth
+// @Param id query int true "用户ID"
+// @Success 200 {object} user.AdminUser "用户详情"
+// @Router /api/v1/admin/users/detail [get]
+func (ac *AdminUserController) Detail(c *gin.Context, req *user.AdminIDRequest) (interface{}, error) {
+	return service.AdminUserService.Detail(c.Request.Context(), req)
+}
+
+// Restore 恢复已删除用户
+// @Summary 恢复已删除用户
+// @Description 恢复被删除的用户，账号状态迁移回 active
+// @Tags 管理员
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body user.AdminStatusRequest true "用户ID与原因"
+// @Success 200 {object} response.Response "恢复成功"
+// @Router /api/v1/admin/users/restore [post]
+func (ac *AdminUserController) Restore(c *gin.Context, req *user.AdminStatusRequest) (interface{}, error) {
+	return nil, service.AdminUserService.Restore(c.Request.Context(), req)
+}
+
+// HardDelete 彻底删除用户
+// @Summary 彻底删除用户
+// @Description 永久删除用户及其外部身份、两步验证与 API 密钥，并吊销全部会话，不可恢复
+// @Tags 管理员
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body user.AdminIDRequest true "用户ID"
+// @Success 200 {object} response.Response "删除成功"
+// @Router /api/v1/admin/users/hard-delete [post]
+func (ac *AdminUserController) HardDelete(c *gin.Context, req *user.AdminIDRequest) (interface{}, error) {
+	return nil, service.AdminUserService.HardDelete(c.Request.Context(), req)
+}
+
+// Suspend 停用用户
+// @Summary 停用用户
+// @Description 账号状态迁移到 suspended 并吊销其全部会话，停用后无法登录，未过期的令牌也会被拒绝
+// @Tags 管理员
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body user.AdminStatusRequest true "用户ID与停用原因"
+// @Success 200 {object} response.Response "停用成功"
+// @Router /api/v1/admin/users/suspend [post]
+func (ac *AdminUserController) Suspend(c *gin.Context, req *user.AdminStatusRequest) (interface{}, error) {
+	return nil, service.AdminUserService.Suspend(c.Request.Context(), req)
+}
+
+// Activate 激活用户
+// @Summary 激活用户
+// @Description 将待激活或已停用的用户迁移到 active
+// @Tags 管理员
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body user.AdminStatusRequest true "用户ID与原因"
+// @Success 200 {object} response.Response "激活成功"
+// @Router /api/v1/admin/users/activate [post]
+func (ac *AdminUserController) Activate(c *gin.Context, req *user.AdminStatusRequest) (interface{}, error) {
+	return nil, service.AdminUserService.Activate(c.Request.Context(), req)
+}
+
+// StatusLogs 账号状态变更记录
+// @Summary 账号状态变更记录
+// @Description 按时间倒序列出账号的每次状态迁移及原因
+// @Tags 管理员
+// @Produce json
+// @Security BearerAuth
+// @Param id query int true "用户ID"
+// @Success 200 {array} user.StatusLog "变更记录"
+// @Router /api/v1/admin/users/status-logs [get]
+func (ac *AdminUserController) StatusLogs(c *gin.Context, req *user.AdminIDRequest) (interface{}, error) {
+	return service.AdminUserService.StatusLogs(c.Request.Context(), req)
+}
+
+// AssignRole 分配角色
+// @Summary 分配角色
+// @Description 修改用户角色并吊销其会话，重新登录后新角色生效
+// @Tags 管理员
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body user.AdminAssignRoleRequest true "用户ID与角色"
+// @Success 200 {object} response.Response "分配成功"
+// @Router /api/v1/admin/users/role [post]
+func (ac *AdminUserController) AssignRole(c *gin.Context, req *user.AdminAssignRoleRequest) (interface{}, error) {
+	return nil, service.AdminUserService.AssignRole(c.Request.Context(), req)
+}
+
+// Import 批量导入用户
+// @Summary 批量导入用户（管理员）
+// @Description 流式读取 CSV（首行为表头）或 JSONL，字段 username、email、password，按注册规则逐行校验后分批写入，返回逐行错误报告。
+// @Description 请求体可直接为文件内容（Content-Type: text/csv 或 application/x-ndjson），也可为 multipart 表单的 file 字段
+// @Tags 管理员
+// @Accept plain
+// @Accept mpfd
+// @Produce json
+// @Security BearerAuth
+// @Param format query string false "文件格式，缺省时按 Content-Type 或文件扩展名判断" Enums(csv, jsonl)
+// @Param file formData file false "导入文件"
+// @Success 200 {object} user.ImportResponse "导入结果"
+// @Router /api/v1/admin/users/import [post]
+func (ac *AdminUserController) Import(c *gin.Context) (interface{}, error) {
+	body, format, err := importSource(c)
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+	return service.AdminUserService.Import(c.Request.Context(), body, format)
+}
+
+// Export 导出用户
+// @Summary 导出用户（管理员）
+// @Description 按用户列表的筛选条件（keyword、filter 等）流式导出全部结果，按 id 升序；fields 指定导出的列
+// @Tags 管理员
+// @Produce plain
+// @Security BearerAuth
+// @Param format query string false "导出格式，默认 csv" Enums(csv, jsonl)
+// @Param keyword query string false "用户名或邮箱全文检索"
+// @Param filter query string false "过滤条件，如 created_at:gte:2024-01-01"
+// @Param fields query string false "导出的列，如 id,username,email"
+// @Success 200 {file} file "CSV 或 JSONL 文件"
+// @Router /api/v1/admin/users/export [get]
+func (ac *AdminUserController) Export(c *gin.Context) {
+	var req user.ExportRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		response.Error(c, errors.New(constant.ParamError, err.Error()))
+		return
+	}
+	write, err := service.AdminUserService.Export(c.Request.Context(), &req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	format := req.Format
+	if format == "" {
+		format = bulk.CSV
+	}
+	contentType := "text/csv; charset=utf-8"
+	if format == bulk.JSONL {
+		contentType = "application/x-ndjson"
+	}
+	c.Header("Content-Type", contentType)
+	c.Header("Content-Disposition", `attachment; filename="users.`+format+`"`)
+	c.Status(200)
+	if err := write(c.Writer); err != nil {
+		// 响应头已发出，只能中断输出并记录日志
+		pkgCtx.MustGetContext(c.Request.Context()).LogError("用户导出中断", zap.Error(err))
+		c.Abort()
+	}
+}
+
+// importSource 导入数据来源与格式：multipart 表单取 file 字段，否则直接读取请求体。
+// 格式优先取 format 参数，其次为文件扩展名或 Content-Type
+func importSource(c *gin.Context) (io.ReadCloser, string, error) {
+	format := c.Query("format")
+	if strings.HasPrefix(c.ContentType(), "multipart/") {
+		fh, err := c.FormFile("file")
+		if err != nil {
+			return nil, "", errors.New(constant.ParamError, err.Error())
+		}
+		if format == "" {
+			format = formatOf(strings.TrimPrefix(filepath.Ext(fh.Filename), "."))
+		}
+		f, err := fh.Open()
+		if err != nil {
+			return nil, "", errors.New(constant.ParamError, err.Error())
+		}
+		return f, format, nil
+	}
+	if format == "" {
+		format = formatOf(c.ContentType())
+	}
+	return c.Request.Body, format, nil
+}
+
+// formatOf 由扩展名或 Content-Type 推断导入格式，无法识别时返回空串（由 bulk 报告不支持的格式）
+func formatOf(s string) string {
+	switch strings.ToLower(s) {
+	case "csv", "text/csv":
+		return bulk.CSV
+	case "jsonl", "ndjson", "application/x-ndjson", "application/jsonl", "application/x-jsonlines":
+		return bulk.JSONL
+	}
+	return ""
+}
@@ -0,0 +1,72 @@
+package controller
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/liuchen/gin-craft/internal/dto/apikey"
+	"github.com/liuchen/gin-craft/internal/service"
+)
+
+// APIKeyController API 密钥控制器
+type APIKeyController struct{}
+
+// NewAPIKeyController 创建 API 密钥控制器实例
+func NewAPIKeyController() *APIKeyController {
+	return &APIKeyController{}
+}
+
+// Create 创建 API 密钥
+// @Summary 创建API密钥
+// @Description 为指定用户创建 API 密钥，完整密钥仅在本次响应中返回，服务端只保存摘要
+// @Tags 管理员
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body apikey.CreateRequest true "密钥信息"
+// @Success 200 {object} apikey.CreateResponse "创建成功"
+// @Router /api/v1/admin/api-keys [post]
+func (ac *APIKeyController) Create(c *gin.Context, req *apikey.CreateRequest) (interface{}, error) {
+	return service.APIKeyService.Create(c.Request.Context(), req)
+}
+
+// List API 密钥列表
+// @Summary API密钥列表
+// @Description 分页获取 API 密钥，可按归属用户筛选；不返回密钥明文
+// @Tags 管理员
+// @Produce json
+// @Security BearerAuth
+// @Param owner_id query int false "归属用户ID"
+// @Param now_page query int false "页码"
+// @Param per_page query int false "每页数量"
+// @Success 200 {object} apikey.ListResponse "获取成功"
+// @Router /api/v1/admin/api-keys [get]
+func (ac *APIKeyController) List(c *gin.Context, req *apikey.ListRequest) (interface{}, error) {
+	return service.APIKeyService.List(c.Request.Context(), req)
+}
+
+// Update 更新 API 密钥
+// @Summary 更新API密钥
+// @Description 修改 API 密钥的名称、授权范围与过期时间
+// @Tags 管理员
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body apikey.UpdateRequest true "更新信息"
+// @Success 200 {object} response.Response "更新成功"
+// @Router /api/v1/admin/api-keys/edit [post]
+func (ac *APIKeyController) Update(c *gin.Context, req *apikey.UpdateRequest) (interface{}, error) {
+	return nil, service.APIKeyService.Update(c.Request.Context(), req)
+}
+
+// Delete 吊销 API 密钥
+// @Summary 吊销API密钥
+// @Description 吊销 API 密钥，立即生效
+// @Tags 管理员
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body apikey.DeleteRequest true "密钥ID"
+// @Success 200 {object} response.Response "吊销成功"
+// @Router /api/v1/admin/api-keys/delete [post]
+func (ac *APIKeyController) Delete(c *gin.Context, req *apikey.DeleteRequest) (interface{}, error) {
+	return nil, service.APIKeyService.Delete(c.Request.Context(), req)
+}
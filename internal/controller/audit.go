@@ -0,0 +1,36 @@
+package controller
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/liuchen/gin-craft/internal/dto/audit"
+	"github.com/liuchen/gin-craft/internal/service"
+)
+
+// AuditController 审计日志控制器
+type AuditController struct{}
+
+// NewAuditController 创建审计日志控制器实例
+func NewAuditController() *AuditController {
+	return &AuditController{}
+}
+
+// List 审计日志列表
+// @Summary 审计日志列表
+// @Description 分页查询审计日志，默认按时间倒序；可按操作人、动作、目标、链路ID与日期筛选，sort 为逗号分隔的字段，- 前缀表示倒序
+// @Tags 管理员
+// @Produce json
+// @Security BearerAuth
+// @Param actor_id query string false "操作人ID"
+// @Param action query string false "动作，如 user.disable"
+// @Param target query string false "目标，如 user:42"
+// @Param trace_id query string false "请求链路ID"
+// @Param from query string false "起始日期，格式 2006-01-02"
+// @Param to query string false "截止日期，格式 2006-01-02"
+// @Param sort query string false "排序，如 -created_at,action"
+// @Param now_page query int false "页码"
+// @Param per_page query int false "每页数量"
+// @Success 200 {object} audit.ListResponse "获取成功"
+// @Router /api/v1/admin/audit-logs [get]
+func (ac *AuditController) List(c *gin.Context, req *audit.ListRequest) (interface{}, error) {
+	return service.AuditService.List(c.Request.Context(), req)
+}
@@ -0,0 +1,43 @@
+package controller
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/liuchen/gin-craft/internal/dto/lockout"
+	"github.com/liuchen/gin-craft/internal/service"
+)
+
+// LockoutController 登录锁定管理控制器
+type LockoutController struct{}
+
+// NewLockoutController 创建登录锁定管理控制器实例
+func NewLockoutController() *LockoutController {
+	return &LockoutController{}
+}
+
+// List 登录锁定列表
+// @Summary 登录锁定列表
+// @Description 按用户名或 IP 查询失败次数与锁定状态；均不填写时列出全部锁定中的条目
+// @Tags 管理员
+// @Produce json
+// @Security BearerAuth
+// @Param username query string false "用户名"
+// @Param ip query string false "客户端 IP"
+// @Success 200 {object} lockout.ListResponse "锁定列表"
+// @Router /api/v1/admin/lockouts [get]
+func (lc *LockoutController) List(c *gin.Context, req *lockout.ListRequest) (interface{}, error) {
+	return service.LockoutService.List(c.Request.Context(), req)
+}
+
+// Clear 解除登录锁定
+// @Summary 解除登录锁定
+// @Description 清除指定用户名和/或 IP 的失败计数与锁定
+// @Tags 管理员
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body lockout.ClearRequest true "用户名或 IP"
+// @Success 200 {object} response.Response "解除成功"
+// @Router /api/v1/admin/lockouts/clear [post]
+func (lc *LockoutController) Clear(c *gin.Context, req *lockout.ClearRequest) (interface{}, error) {
+	return nil, service.LockoutService.Clear(c.Request.Context(), req)
+}
@@ -0,0 +1,95 @@
+package controller
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/liuchen/gin-craft/internal/dto/rbac"
+	"github.com/liuchen/gin-craft/internal/service"
+)
+
+// RBACController 角色权限控制器
+type RBACController struct{}
+
+// NewRBACController 创建角色权限控制器实例
+func NewRBACController() *RBACController {
+	return &RBACController{}
+}
+
+// ListRoles 角色列表
+// @Summary 角色列表
+// @Description 获取全部角色及其绑定的权限码
+// @Tags 管理员
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} rbac.Role "角色列表"
+// @Router /api/v1/admin/roles [get]
+func (rc *RBACController) ListRoles(c *gin.Context) (interface{}, error) {
+	return service.RBACService.ListRoles(c.Request.Context())
+}
+
+// CreateRole 创建角色
+// @Summary 创建角色
+// @Description 创建新角色，创建后通过授权接口绑定权限
+// @Tags 管理员
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body rbac.CreateRoleRequest true "角色信息"
+// @Success 200 {object} rbac.Role "创建成功"
+// @Router /api/v1/admin/roles [post]
+func (rc *RBACController) CreateRole(c *gin.Context, req *rbac.CreateRoleRequest) (interface{}, error) {
+	return service.RBACService.CreateRole(c.Request.Context(), req)
+}
+
+// Grant 角色授权
+// @Summary 角色授权
+// @Description 为角色绑定权限，已绑定的权限忽略；变更立即对所有实例生效
+// @Tags 管理员
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body rbac.BindRequest true "授权信息"
+// @Success 200 {object} response.Response "授权成功"
+// @Router /api/v1/admin/roles/grant [post]
+func (rc *RBACController) Grant(c *gin.Context, req *rbac.BindRequest) (interface{}, error) {
+	return nil, service.RBACService.Grant(c.Request.Context(), req)
+}
+
+// Revoke 角色撤权
+// @Summary 角色撤权
+// @Description 解除角色与权限的绑定；变更立即对所有实例生效
+// @Tags 管理员
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body rbac.BindRequest true "撤权信息"
+// @Success 200 {object} response.Response "撤权成功"
+// @Router /api/v1/admin/roles/revoke [post]
+func (rc *RBACController) Revoke(c *gin.Context, req *rbac.BindRequest) (interface{}, error) {
+	return nil, service.RBACService.Revoke(c.Request.Context(), req)
+}
+
+// ListPermissions 权限列表
+// @Summary 权限列表
+// @Description 获取全部权限码
+// @Tags 管理员
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} rbac.Permission "权限列表"
+// @Router /api/v1/admin/permissions [get]
+func (rc *RBACController) ListPermissions(c *gin.Context) (interface{}, error) {
+	return service.RBACService.ListPermissions(c.Request.Context())
+}
+
+// CreatePermission 创建权限
+// @Summary 创建权限
+// @Description 创建新权限码，格式为 资源:操作
+// @Tags 管理员
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body rbac.CreatePermissionRequest true "权限信息"
+// @Success 200 {object} rbac.Permission "创建成功"
+// @Router /api/v1/admin/permissions [post]
+func (rc *RBACController) CreatePermission(c *gin.Context, req *rbac.CreatePermissionRequest) (interface{}, error) {
+	return service.RBACService.CreatePermission(c.Request.Context(), req)
+}
@@ -1,8 +1,11 @@
 package controller
 
 import (
+	"net/http"
+
 	"github.com/gin-gonic/gin"
 	"github.com/liuchen/gin-craft/internal/dto/user"
+	"github.com/liuchen/gin-craft/internal/pkg/config"
 	"github.com/liuchen/gin-craft/internal/service"
 )
 
@@ -24,7 +27,7 @@ func NewUserController() *UserController {
 // @Success 200 {object} response.Response "注册成功"
 // @Router /api/v1/user/register [post]
 func (uc *UserController) Register(c *gin.Context, req *user.RegisterRequest) (interface{}, error) {
-	return nil, service.UserService.Register(req)
+	return nil, service.UserService.Register(c.Request.Context(), req)
 }
 
 // Login 用户登录
@@ -40,9 +43,216 @@ func (uc *UserController) Login(c *gin.Context, req *user.LoginRequest) (interfa
 	return service.UserService.Login(c.Request.Context(), req)
 }
 
+// ForgotPassword 忘记密码
+// @Summary 忘记密码
+// @Description 向注册邮箱发送重置密码链接；为避免探测账号，邮箱未注册时同样返回成功
+// @Tags 用户管理
+// @Accept json
+// @Produce json
+// @Param request body user.ForgotPasswordRequest true "注册邮箱"
+// @Success 200 {object} response.Response "已受理"
+// @Router /api/v1/user/forgot-password [post]
+func (uc *UserController) ForgotPassword(c *gin.Context, req *user.ForgotPasswordRequest) (interface{}, error) {
+	return nil, service.AccountService.ForgotPassword(c.Request.Context(), req)
+}
+
+// ResetPassword 重置密码
+// @Summary 重置密码
+// @Description 使用重置邮件中的一次性令牌设置新密码，成功后该用户所有会话失效
+// @Tags 用户管理
+// @Accept json
+// @Produce json
+// @Param request body user.ResetPasswordRequest true "令牌与新密码"
+// @Success 200 {object} response.Response "重置成功"
+// @Router /api/v1/user/reset-password [post]
+func (uc *UserController) ResetPassword(c *gin.Context, req *user.ResetPasswordRequest) (interface{}, error) {
+	return nil, service.AccountService.ResetPassword(c.Request.Context(), req)
+}
+
+// VerifyEmail 验证邮箱
+// @Summary 验证邮箱
+// @Description 使用验证邮件中的一次性令牌完成邮箱验证
+// @Tags 用户管理
+// @Accept json
+// @Produce json
+// @Param request body user.VerifyEmailRequest true "验证令牌"
+// @Success 200 {object} response.Response "验证成功"
+// @Router /api/v1/user/verify-email [post]
+func (uc *UserController) VerifyEmail(c *gin.Context, req *user.VerifyEmailRequest) (interface{}, error) {
+	return nil, service.AccountService.VerifyEmail(c.Request.Context(), req)
+}
+
+// SendVerification 重新发送邮箱验证邮件
+// @Summary 重新发送邮箱验证邮件
+// @Description 向当前用户的邮箱重新发送验证链接，之前的链接随之失效
+// @Tags 用户管理
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} response.Response "发送成功"
+// @Router /api/v1/user/verify-email/send [post]
+func (uc *UserController) SendVerification(c *gin.Context) (interface{}, error) {
+	return nil, service.AccountService.SendVerification(c.Request.Context())
+}
+
+// ChangePassword 修改密码
+// @Summary 修改密码
+// @Description 校验当前密码与密码策略后修改密码；其他设备上的会话全部失效，响应中返回当前客户端的新令牌
+// @Tags 用户管理
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body user.PasswordUpdateRequest true "当前密码与新密码"
+// @Success 200 {object} user.LoginResponse "修改成功"
+// @Router /api/v1/user/change-password [post]
+func (uc *UserController) ChangePassword(c *gin.Context, req *user.PasswordUpdateRequest) (interface{}, error) {
+	return service.UserService.ChangePassword(c.Request.Context(), req)
+}
+
+// MFALogin 两步验证登录
+// @Summary 两步验证登录
+// @Description 两阶段登录第二步：使用登录返回的 mfa_token 与验证码（或恢复码）换取访问令牌
+// @Tags 用户管理
+// @Accept json
+// @Produce json
+// @Param request body user.MFALoginRequest true "两步验证信息"
+// @Success 200 {object} user.LoginResponse "登录成功"
+// @Router /api/v1/user/login/mfa [post]
+func (uc *UserController) MFALogin(c *gin.Context, req *user.MFALoginRequest) (interface{}, error) {
+	return service.MFAService.Login(c.Request.Context(), req)
+}
+
+// MFAEnroll 生成两步验证密钥
+// @Summary 生成两步验证密钥
+// @Description 校验当前密码后为当前用户生成 TOTP 密钥与 otpauth:// 地址，需调用开启接口确认后才生效
+// @Tags 用户管理
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body user.MFAEnrollRequest true "当前密码"
+// @Success 200 {object} user.MFAEnrollResponse "密钥信息"
+// @Router /api/v1/user/mfa/enroll [post]
+func (uc *UserController) MFAEnroll(c *gin.Context, req *user.MFAEnrollRequest) (interface{}, error) {
+	return service.MFAService.Enroll(c.Request.Context(), req)
+}
+
+// MFAEnable 开启两步验证
+// @Summary 开启两步验证
+// @Description 校验当前密码与验证器 App 生成的验证码后开启两步验证，返回一次性恢复码（仅展示一次）
+// @Tags 用户管理
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body user.MFACodeRequest true "当前密码与验证码"
+// @Success 200 {object} user.MFARecoveryCodesResponse "恢复码"
+// @Router /api/v1/user/mfa/enable [post]
+func (uc *UserController) MFAEnable(c *gin.Context, req *user.MFACodeRequest) (interface{}, error) {
+	return service.MFAService.Enable(c.Request.Context(), req)
+}
+
+// MFADisable 关闭两步验证
+// @Summary 关闭两步验证
+// @Description 校验当前密码与验证码（或恢复码）后关闭两步验证并清除恢复码
+// @Tags 用户管理
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body user.MFACodeRequest true "当前密码与验证码"
+// @Success 200 {object} response.Response "关闭成功"
+// @Router /api/v1/user/mfa/disable [post]
+func (uc *UserController) MFADisable(c *gin.Context, req *user.MFACodeRequest) (interface{}, error) {
+	return nil, service.MFAService.Disable(c.Request.Context(), req)
+}
+
+// OIDCLogin 发起 OIDC 登录
+// @Summary 发起 OIDC 登录
+// @Description 返回企业 SSO 授权地址（authorization code + PKCE），并写入绑定当前浏览器的 HttpOnly Cookie；前端跳转后由提供方回调
+// @Tags 用户管理
+// @Produce json
+// @Param provider query string true "登录提供方"
+// @Success 200 {object} user.OIDCLoginResponse "授权地址"
+// @Router /api/v1/user/oidc/login [get]
+func (uc *UserController) OIDCLogin(c *gin.Context, req *user.OIDCLoginRequest) (interface{}, error) {
+	resp, err := service.OIDCService.AuthURL(c.Request.Context(), req)
+	if err != nil {
+		return nil, err
+	}
+	setOIDCBindingCookie(c, resp.Binding, config.Config.OIDC.StateTTL)
+	return resp, nil
+}
+
+// OIDCCallback OIDC 登录回调
+// @Summary OIDC 登录回调
+// @Description 校验 state（须与发起登录时的 Cookie 匹配）与 ID Token，首次登录自动创建用户，返回与用户登录相同的结果（已开启两步验证时为 mfa_token）
+// @Tags 用户管理
+// @Produce json
+// @Param code query string true "授权码"
+// @Param state query string true "state"
+// @Success 200 {object} user.LoginResponse "登录成功"
+// @Router /api/v1/user/oidc/callback [get]
+func (uc *UserController) OIDCCallback(c *gin.Context, req *user.OIDCCallbackRequest) (interface{}, error) {
+	req.Binding, _ = c.Cookie(oidcBindingCookie)
+	setOIDCBindingCookie(c, "", -1)
+	return service.OIDCService.Callback(c.Request.Context(), req)
+}
+
+// oidcBindingCookie 将 OIDC 授权请求绑定到发起登录的浏览器。
+// SameSite=Lax：提供方回调是跨站的顶层 GET 跳转，Strict 会导致 Cookie 不被携带
+const (
+	oidcBindingCookie = "oidc_binding"
+	oidcBindingPath   = "/api/v1/user/oidc"
+)
+
+// setOIDCBindingCookie 写入绑定 Cookie；maxAge < 0 时删除
+func setOIDCBindingCookie(c *gin.Context, value string, maxAge int) {
+	secure := c.Request.TLS != nil || c.GetHeader("X-Forwarded-Proto") == "https"
+	c.SetSameSite(http.SameSiteLaxMode)
+	c.SetCookie(oidcBindingCookie, value, maxAge, oidcBindingPath, "", secure, true)
+}
+
+// Refresh 刷新令牌
+// @Summary 刷新令牌
+// @Description 使用刷新令牌换取新的访问令牌；刷新令牌每次使用后轮换，旧令牌被重放时整个会话吊销
+// @Tags 用户管理
+// @Accept json
+// @Produce json
+// @Param request body user.RefreshRequest true "刷新令牌"
+// @Success 200 {object} user.LoginResponse "刷新成功"
+// @Router /api/v1/user/refresh [post]
+func (uc *UserController) Refresh(c *gin.Context, req *user.RefreshRequest) (interface{}, error) {
+	return service.UserService.Refresh(c.Request.Context(), req)
+}
+
+// Logout 用户注销
+// @Summary 用户注销
+// @Description 注销当前访问令牌，可选同时吊销刷新令牌；吊销对所有实例立即生效
+// @Tags 用户管理
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body user.LogoutRequest true "注销信息"
+// @Success 200 {object} response.Response "注销成功"
+// @Router /api/v1/user/logout [post]
+func (uc *UserController) Logout(c *gin.Context, req *user.LogoutRequest) (interface{}, error) {
+	return nil, service.UserService.Logout(c.Request.Context(), req)
+}
+
+// RevokeSessions 吊销用户全部会话
+// @Summary 吊销用户全部会话
+// @Description 管理员操作：使指定用户的所有访问令牌与刷新令牌立即失效
+// @Tags 管理员
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body user.RevokeSessionsRequest true "目标用户"
+// @Success 200 {object} response.Response "吊销成功"
+// @Router /api/v1/admin/users/revoke-sessions [post]
+func (uc *UserController) RevokeSessions(c *gin.Context, req *user.RevokeSessionsRequest) (interface{}, error) {
+	return nil, service.UserService.RevokeSessions(c.Request.Context(), req)
+}
+
 // List 获取用户列表
 // @Summary 获取用户列表
-// @Description 根据请求条件获取用户列表信息
+// @Description 根据请求条件获取用户列表信息；keyword 全文检索用户名与邮箱，未指定 sort 时按相关度排序；filter 可用字段 id/username/email/created_at/updated_at，sort 同 filter，fields 另可选 email_verified
 // @Tags 用户管理
 // @Accept json
 // @Produce json
@@ -50,7 +260,20 @@ func (uc *UserController) Login(c *gin.Context, req *user.LoginRequest) (interfa
 // @Success 200 {object} user.ListResponse "获取成功"
 // @Router /api/v1/user/list [post]
 func (uc *UserController) List(c *gin.Context, req *user.ListRequest) (interface{}, error) {
-	return service.UserService.GetUserList(req)
+	return service.UserService.GetUserList(c.Request.Context(), req)
+}
+
+// ListByCursor 游标分页获取用户列表
+// @Summary 游标分页获取用户列表
+// @Description 按 id 或注册时间排序的 keyset 分页，翻页时回传上一页的 next_cursor；默认不统计总数
+// @Tags 用户管理
+// @Accept json
+// @Produce json
+// @Param request body user.CursorListRequest true "用户列表信息"
+// @Success 200 {object} user.CursorListResponse "获取成功"
+// @Router /api/v1/user/list/cursor [post]
+func (uc *UserController) ListByCursor(c *gin.Context, req *user.CursorListRequest) (interface{}, error) {
+	return service.UserService.GetUserListByCursor(c.Request.Context(), req)
 }
 
 // Info 获取用户信息
@@ -63,15 +286,16 @@ func (uc *UserController) List(c *gin.Context, req *user.ListRequest) (interface
 // @Success 200 {object} user.User "获取成功"
 // @Router /api/v1/user/info [get]
 func (uc *UserController) Info(c *gin.Context, req *user.InfoRequest) (interface{}, error) {
-	return service.UserService.GetUserInfo(req)
+	return service.UserService.GetUserInfo(c.Request.Context(), req)
 }
 
 // Update 更新用户
 // @Summary 更新用户
-// @Description 根据用户ID更新用户信息
+// @Description 根据用户ID更新用户信息；ID 为空时更新当前用户，仅本人或拥有 user:admin 权限者可操作
 // @Tags 用户管理
 // @Accept json
 // @Produce json
+// @Security BearerAuth
 // @Param request body user.UpdateRequest true "更新信息"
 // @Success 200 {object} response.Response "更新成功"
 // @Router /api/v1/user/edit [post]
@@ -81,10 +305,11 @@ func (uc *UserController) Update(c *gin.Context, req *user.UpdateRequest) (inter
 
 // Delete 删除用户
 // @Summary 删除用户
-// @Description 根据用户ID删除用户信息
+// @Description 根据用户ID删除用户信息，仅本人或拥有 user:admin / user:delete 权限者可操作
 // @Tags 用户管理
 // @Accept json
 // @Produce json
+// @Security BearerAuth
 // @Param request body user.InfoRequest true "删除信息"
 // @Success 200 {object} response.Response "删除成功"
 // @Router /api/v1/user/delete [post]
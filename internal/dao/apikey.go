@@ -0,0 +1,99 @@
+package dao
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	dtoAPIKey "github.com/liuchen/gin-craft/internal/dto/apikey"
+	"github.com/liuchen/gin-craft/internal/model"
+	"gorm.io/gorm"
+)
+
+// APIKeyDAO API 密钥数据访问对象
+type APIKeyDAO struct{}
+
+var (
+	apiKeyDAO     *APIKeyDAO
+	apiKeyDAOOnce sync.Once
+)
+
+// GetAPIKeyDAO 获取 APIKeyDAO 单例实例
+func GetAPIKeyDAO() *APIKeyDAO {
+	apiKeyDAOOnce.Do(func() {
+		apiKeyDAO = &APIKeyDAO{}
+	})
+	return apiKeyDAO
+}
+
+// GetByID 根据 ID 获取密钥；找不到返回 gorm.ErrRecordNotFound
+func (d *APIKeyDAO) GetByID(ctx context.Context, id uint) (*model.APIKey, error) {
+	var k model.APIKey
+	if err := getDB(ctx).First(&k, id).Error; err != nil {
+		return nil, err
+	}
+	return &k, nil
+}
+
+// GetByKeyID 根据公开 ID 获取密钥
+func (d *APIKeyDAO) GetByKeyID(ctx context.Context, keyID string) (*model.APIKey, error) {
+	var k model.APIKey
+	if err := getDB(ctx).Where("key_id = ?", keyID).First(&k).Error; err != nil {
+		return nil, err
+	}
+	return &k, nil
+}
+
+// Create 创建密钥
+func (d *APIKeyDAO) Create(ctx context.Context, k *model.APIKey) error {
+	return getDB(ctx).Create(k).Error
+}
+
+// Update 白名单字段更新；updates 为空则直接返回 nil
+func (d *APIKeyDAO) Update(ctx context.Context, id uint, updates map[string]interface{}) error {
+	if len(updates) == 0 {
+		return nil
+	}
+	res := getDB(ctx).Model(&model.APIKey{}).Where("id = ?", id).Updates(updates)
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// TouchLastUsed 记录最近使用时间，不更新 updated_at
+func (d *APIKeyDAO) TouchLastUsed(ctx context.Context, id uint, at time.Time) error {
+	return getDB(ctx).Model(&model.APIKey{}).Where("id = ?", id).UpdateColumn("last_used_at", at).Error
+}
+
+// Delete 软删除（吊销）密钥
+func (d *APIKeyDAO) Delete(ctx context.Context, id uint) error {
+	res := getDB(ctx).Delete(&model.APIKey{}, id)
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// GetList 获取密钥列表（支持按归属用户过滤 + 分页）
+func (d *APIKeyDAO) GetList(ctx context.Context, req *dtoAPIKey.ListRequest) ([]model.APIKey, error) {
+	q := getDB(ctx).Model(&model.APIKey{})
+	if req.OwnerID != 0 {
+		q = q.Where("owner_id = ?", req.OwnerID)
+	}
+	if err := countTotal(q, &req.Pagination); err != nil {
+		return nil, err
+	}
+
+	var keys []model.APIKey
+	if err := q.Scopes(paginate(&req.Pagination), defaultOrder()).Find(&keys).Error; err != nil {
+		return nil, err
+	}
+	return keys, nil
+}
@@ -0,0 +1,79 @@
+package dao
+
+import (
+	"context"
+	"sync"
+
+	dtoAudit "github.com/liuchen/gin-craft/internal/dto/audit"
+	"github.com/liuchen/gin-craft/internal/model"
+	"github.com/liuchen/gin-craft/internal/pkg/database"
+	"github.com/liuchen/gin-craft/pkg/query"
+)
+
+// auditBatchSize 批量写入审计日志的单批条数
+const auditBatchSize = 100
+
+// AuditDAO 审计日志数据访问对象
+type AuditDAO struct{}
+
+var (
+	auditDAO     *AuditDAO
+	auditDAOOnce sync.Once
+)
+
+// GetAuditDAO 获取 AuditDAO 单例实例
+func GetAuditDAO() *AuditDAO {
+	auditDAOOnce.Do(func() {
+		auditDAO = &AuditDAO{}
+	})
+	return auditDAO
+}
+
+// BatchCreate 批量写入审计日志
+func (d *AuditDAO) BatchCreate(ctx context.Context, logs []model.AuditLog) error {
+	if len(logs) == 0 {
+		return nil
+	}
+	return BatchCreateModel(ctx, database.GetDatabase(), &logs, auditBatchSize)
+}
+
+// AuditQuerySchema 审计日志列表允许排序的字段
+var AuditQuerySchema = query.Schema{
+	"id":         {Type: query.Int, Sortable: true},
+	"actor_id":   {Type: query.String, Sortable: true},
+	"action":     {Type: query.String, Sortable: true},
+	"target":     {Type: query.String, Sortable: true},
+	"created_at": {Type: query.Time, Sortable: true},
+}
+
+// GetList 获取审计日志列表（按操作人、动作、目标、链路ID、日期过滤 + 分页），未指定排序时按时间倒序；lq 由 AuditQuerySchema 解析
+func (d *AuditDAO) GetList(ctx context.Context, req *dtoAudit.ListRequest, lq *query.Query) ([]model.AuditLog, error) {
+	q := getDB(ctx).Model(&model.AuditLog{})
+	if req.ActorID != "" {
+		q = q.Where("actor_id = ?", req.ActorID)
+	}
+	if req.Action != "" {
+		q = q.Where("action = ?", req.Action)
+	}
+	if req.Target != "" {
+		q = q.Where("target = ?", req.Target)
+	}
+	if req.TraceID != "" {
+		q = q.Where("trace_id = ?", req.TraceID)
+	}
+	if req.From != nil {
+		q = q.Where("created_at >= ?", *req.From)
+	}
+	if req.To != nil {
+		q = q.Where("created_at < ?", req.To.AddDate(0, 0, 1))
+	}
+	if err := countTotal(q, &req.Pagination); err != nil {
+		return nil, err
+	}
+
+	var logs []model.AuditLog
+	if err := q.Scopes(paginate(&req.Pagination), lq.Order(defaultOrder())).Find(&logs).Error; err != nil {
+		return nil, err
+	}
+	return logs, nil
+}
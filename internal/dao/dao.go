@@ -1,9 +1,12 @@
 package dao
 
 import (
+	"context"
+	"fmt"
 	"strings"
 
 	"github.com/liuchen/gin-craft/internal/dto"
+	"github.com/liuchen/gin-craft/internal/pkg/database"
 	pkgdb "github.com/liuchen/gin-craft/pkg/database"
 	"github.com/pkg/errors"
 	"gorm.io/gorm"
@@ -12,6 +15,7 @@ import (
 const (
 	defaultPageSize = 10
 	maxPageSize     = 100
+	batchInsertSize = 100 // 批量写入时单条 INSERT 的行数
 )
 
 // paginate 分页 scope，不回写 req。
@@ -32,6 +36,46 @@ func paginate(p *dto.Pagination) func(db *gorm.DB) *gorm.DB {
 	}
 }
 
+// countTotal 统计总数写入 p.Total；p.SkipTotal 时跳过 COUNT 查询，Total 置为 dto.TotalSkipped
+func countTotal(q *gorm.DB, p *dto.Pagination) error {
+	if p.SkipTotal {
+		p.Total = dto.TotalSkipped
+		return nil
+	}
+	return q.Count(&p.Total).Error
+}
+
+// Keyset 游标分页位置：按 Column 排序、id 兜底，从 (Value, ID) 之后开始取。
+// Column 须由调用方按白名单校验
+type Keyset struct {
+	Column string
+	Desc   bool
+	After  bool        // 是否有起始位置，第一页为 false
+	Value  interface{} // 上一页最后一行的 Column 值，Column 为 id 时忽略
+	ID     uint
+}
+
+// keysetPaginate 游标分页 scope：WHERE 排序键在起始位置之后 ORDER BY 排序键 LIMIT size+1，
+// 多取的一条用于判断是否还有下一页
+func keysetPaginate(k Keyset, size int) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		op, dir := ">", "ASC"
+		if k.Desc {
+			op, dir = "<", "DESC"
+		}
+		if k.Column == "" || k.Column == "id" {
+			if k.After {
+				db = db.Where("id "+op+" ?", k.ID)
+			}
+			return db.Order("id " + dir).Limit(size + 1)
+		}
+		if k.After {
+			db = db.Where(fmt.Sprintf("(%[1]s %[2]s ? OR (%[1]s = ? AND id %[2]s ?))", k.Column, op), k.Value, k.Value, k.ID)
+		}
+		return db.Order(k.Column + " " + dir + ", id " + dir).Limit(size + 1)
+	}
+}
+
 // defaultOrder 默认按 id 倒序
 func defaultOrder() func(db *gorm.DB) *gorm.DB {
 	return func(db *gorm.DB) *gorm.DB {
@@ -39,14 +83,20 @@ func defaultOrder() func(db *gorm.DB) *gorm.DB {
 	}
 }
 
+// getDB 带上调用方 ctx 的数据库连接：ctx 在 TxManager.Do 的事务中时返回事务连接。
+// SQL 日志从 ctx 中取链路ID，请求取消时查询随之中止
+func getDB(ctx context.Context) *gorm.DB {
+	return pkgdb.Conn(ctx, database.GetDB())
+}
+
 // FirstByCondition 按条件查一条；找不到返回 gorm.ErrRecordNotFound
-func FirstByCondition(db pkgdb.Database, m interface{}, cond map[string]interface{}) error {
-	return db.GetDB().Where(cond).Scopes(defaultOrder()).First(m).Error
+func FirstByCondition(ctx context.Context, db pkgdb.Database, m interface{}, cond map[string]interface{}) error {
+	return pkgdb.Conn(ctx, db.GetDB()).Where(cond).Scopes(defaultOrder()).First(m).Error
 }
 
 // FindAllByCondition 按条件查多条；orders 为空则默认 id DESC
-func FindAllByCondition(db pkgdb.Database, data interface{}, cond map[string]interface{}, orders ...string) error {
-	cur := db.GetDB().Where(cond)
+func FindAllByCondition(ctx context.Context, db pkgdb.Database, data interface{}, cond map[string]interface{}, orders ...string) error {
+	cur := pkgdb.Conn(ctx, db.GetDB()).Where(cond)
 	if len(orders) > 0 {
 		cur = cur.Order(strings.Join(orders, ","))
 	} else {
@@ -56,21 +106,21 @@ func FindAllByCondition(db pkgdb.Database, data interface{}, cond map[string]int
 }
 
 // SaveModel 保存模型（存在则更新，不存在则创建）
-func SaveModel(db pkgdb.Database, m interface{}) error {
-	return db.GetDB().Save(m).Error
+func SaveModel(ctx context.Context, db pkgdb.Database, m interface{}) error {
+	return pkgdb.Conn(ctx, db.GetDB()).Save(m).Error
 }
 
 // CreateModel 创建模型
-func CreateModel(db pkgdb.Database, m interface{}) error {
-	return db.GetDB().Create(m).Error
+func CreateModel(ctx context.Context, db pkgdb.Database, m interface{}) error {
+	return pkgdb.Conn(ctx, db.GetDB()).Create(m).Error
 }
 
-// StartTransaction 开启事务
-func StartTransaction(db pkgdb.Database, f func(tx *gorm.DB) error) error {
-	return db.GetDB().Transaction(f)
+// StartTransaction 开启事务；ctx 已在 TxManager.Do 的事务中时以保存点嵌套
+func StartTransaction(ctx context.Context, db pkgdb.Database, f func(tx *gorm.DB) error) error {
+	return pkgdb.Conn(ctx, db.GetDB()).Transaction(f)
 }
 
 // BatchCreateModel 批量创建
-func BatchCreateModel(db pkgdb.Database, m interface{}, batchSize int) error {
-	return db.GetDB().CreateInBatches(m, batchSize).Error
+func BatchCreateModel(ctx context.Context, db pkgdb.Database, m interface{}, batchSize int) error {
+	return pkgdb.Conn(ctx, db.GetDB()).CreateInBatches(m, batchSize).Error
 }
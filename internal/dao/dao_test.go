@@ -0,0 +1,43 @@
+package dao
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func TestKeysetPaginate(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	type row struct {
+		ID    uint
+		Score int
+	}
+	require.NoError(t, db.AutoMigrate(&row{}))
+	// score 有重复值，翻页时依靠 id 区分
+	for i, score := range []int{3, 1, 2, 2, 1} {
+		require.NoError(t, db.Create(&row{ID: uint(i + 1), Score: score}).Error)
+	}
+
+	page := func(k Keyset) []uint {
+		var rows []row
+		require.NoError(t, db.Scopes(keysetPaginate(k, 2)).Find(&rows).Error)
+		ids := make([]uint, 0, len(rows))
+		for _, r := range rows {
+			ids = append(ids, r.ID)
+		}
+		return ids
+	}
+
+	assert.Equal(t, []uint{5, 4, 3}, page(Keyset{Column: "id", Desc: true}))
+	assert.Equal(t, []uint{3, 2, 1}, page(Keyset{Column: "id", Desc: true, After: true, ID: 4}))
+
+	// 按 score 升序：(1,2) (1,5) (2,3) (2,4) (3,1)
+	assert.Equal(t, []uint{2, 5, 3}, page(Keyset{Column: "score"}))
+	assert.Equal(t, []uint{3, 4, 1}, page(Keyset{Column: "score", After: true, Value: 1, ID: 5}))
+	assert.Equal(t, []uint{1}, page(Keyset{Column: "score", After: true, Value: 2, ID: 4}))
+	assert.Equal(t, []uint{4, 3, 5}, page(Keyset{Column: "score", Desc: true, After: true, Value: 3, ID: 1}))
+}
@@ -0,0 +1,38 @@
+package dao
+
+import (
+	"context"
+	"sync"
+
+	"github.com/liuchen/gin-craft/internal/model"
+)
+
+// IdentityDAO 外部身份数据访问对象
+type IdentityDAO struct{}
+
+var (
+	identityDAO     *IdentityDAO
+	identityDAOOnce sync.Once
+)
+
+// GetIdentityDAO 获取 IdentityDAO 单例实例
+func GetIdentityDAO() *IdentityDAO {
+	identityDAOOnce.Do(func() {
+		identityDAO = &IdentityDAO{}
+	})
+	return identityDAO
+}
+
+// GetByProviderSubject 根据提供方与 subject 获取绑定；找不到返回 gorm.ErrRecordNotFound
+func (d *IdentityDAO) GetByProviderSubject(ctx context.Context, provider, subject string) (*model.UserIdentity, error) {
+	var i model.UserIdentity
+	if err := getDB(ctx).Where("provider = ? AND subject = ?", provider, subject).First(&i).Error; err != nil {
+		return nil, err
+	}
+	return &i, nil
+}
+
+// Create 为已有用户创建绑定
+func (d *IdentityDAO) Create(ctx context.Context, i *model.UserIdentity) error {
+	return getDB(ctx).Create(i).Error
+}
@@ -0,0 +1,95 @@
+package dao
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/liuchen/gin-craft/internal/model"
+	"gorm.io/gorm"
+)
+
+// MFADAO 两步验证数据访问对象
+type MFADAO struct{}
+
+var (
+	mfaDAO     *MFADAO
+	mfaDAOOnce sync.Once
+)
+
+// GetMFADAO 获取 MFADAO 单例实例
+func GetMFADAO() *MFADAO {
+	mfaDAOOnce.Do(func() {
+		mfaDAO = &MFADAO{}
+	})
+	return mfaDAO
+}
+
+// GetByUserID 获取用户的两步验证设置；找不到返回 gorm.ErrRecordNotFound
+func (d *MFADAO) GetByUserID(ctx context.Context, userID uint) (*model.UserMFA, error) {
+	var m model.UserMFA
+	if err := getDB(ctx).Where("user_id = ?", userID).First(&m).Error; err != nil {
+		return nil, err
+	}
+	return &m, nil
+}
+
+// Save 保存两步验证设置（存在则更新）
+func (d *MFADAO) Save(ctx context.Context, m *model.UserMFA) error {
+	return getDB(ctx).Save(m).Error
+}
+
+// Enable 开启两步验证并替换全部恢复码；未发起绑定时返回 gorm.ErrRecordNotFound。涉及多次写入，须在 TxManager.Do 中调用
+func (d *MFADAO) Enable(ctx context.Context, userID uint, step int64, codes []model.MFARecoveryCode) error {
+	db := getDB(ctx)
+	res := db.Model(&model.UserMFA{}).Where("user_id = ?", userID).Updates(map[string]interface{}{
+		"enabled":        true,
+		"enabled_at":     time.Now(),
+		"last_used_step": step,
+	})
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	if err := db.Where("user_id = ?", userID).Delete(&model.MFARecoveryCode{}).Error; err != nil {
+		return err
+	}
+	for i := range codes {
+		codes[i].UserID = userID
+	}
+	return db.Create(&codes).Error
+}
+
+// ConsumeStep 记录通过校验的时间步；时间步不大于已记录值时返回 false（验证码重放）
+func (d *MFADAO) ConsumeStep(ctx context.Context, userID uint, step int64) (bool, error) {
+	res := getDB(ctx).Model(&model.UserMFA{}).
+		Where("user_id = ? AND last_used_step < ?", userID, step).
+		Update("last_used_step", step)
+	return res.RowsAffected > 0, res.Error
+}
+
+// ListUnusedRecoveryCodes 按查找标识获取用户未使用的恢复码，通常至多一条
+func (d *MFADAO) ListUnusedRecoveryCodes(ctx context.Context, userID uint, lookup string) ([]model.MFARecoveryCode, error) {
+	var codes []model.MFARecoveryCode
+	err := getDB(ctx).Where("user_id = ? AND lookup = ? AND used_at IS NULL", userID, lookup).Find(&codes).Error
+	return codes, err
+}
+
+// ConsumeRecoveryCode 标记恢复码已使用；并发下只有一个请求能成功
+func (d *MFADAO) ConsumeRecoveryCode(ctx context.Context, id uint) (bool, error) {
+	res := getDB(ctx).Model(&model.MFARecoveryCode{}).
+		Where("id = ? AND used_at IS NULL", id).
+		Update("used_at", time.Now())
+	return res.RowsAffected > 0, res.Error
+}
+
+// Delete 关闭两步验证，删除设置与全部恢复码；涉及多次写入，须在 TxManager.Do 中调用
+func (d *MFADAO) Delete(ctx context.Context, userID uint) error {
+	db := getDB(ctx)
+	if err := db.Where("user_id = ?", userID).Delete(&model.MFARecoveryCode{}).Error; err != nil {
+		return err
+	}
+	return db.Where("user_id = ?", userID).Delete(&model.UserMFA{}).Error
+}
@@ -0,0 +1,109 @@
+package dao
+
+import (
+	"context"
+	"sync"
+
+	"github.com/liuchen/gin-craft/internal/model"
+	"gorm.io/gorm/clause"
+)
+
+// RBACDAO 角色与权限数据访问对象
+type RBACDAO struct{}
+
+var (
+	rbacDAO     *RBACDAO
+	rbacDAOOnce sync.Once
+)
+
+// GetRBACDAO 获取 RBACDAO 单例实例
+func GetRBACDAO() *RBACDAO {
+	rbacDAOOnce.Do(func() {
+		rbacDAO = &RBACDAO{}
+	})
+	return rbacDAO
+}
+
+// ListRoles 获取全部角色
+func (d *RBACDAO) ListRoles(ctx context.Context) ([]model.Role, error) {
+	var roles []model.Role
+	err := getDB(ctx).Order("id ASC").Find(&roles).Error
+	return roles, err
+}
+
+// GetRoleByName 根据名称获取角色；找不到返回 gorm.ErrRecordNotFound
+func (d *RBACDAO) GetRoleByName(ctx context.Context, name string) (*model.Role, error) {
+	var r model.Role
+	if err := getDB(ctx).Where("name = ?", name).First(&r).Error; err != nil {
+		return nil, err
+	}
+	return &r, nil
+}
+
+// CreateRole 创建角色
+func (d *RBACDAO) CreateRole(ctx context.Context, r *model.Role) error {
+	return getDB(ctx).Create(r).Error
+}
+
+// ListPermissions 获取全部权限
+func (d *RBACDAO) ListPermissions(ctx context.Context) ([]model.Permission, error) {
+	var perms []model.Permission
+	err := getDB(ctx).Order("code ASC").Find(&perms).Error
+	return perms, err
+}
+
+// GetPermissionsByCodes 按权限码批量获取权限
+func (d *RBACDAO) GetPermissionsByCodes(ctx context.Context, codes []string) ([]model.Permission, error) {
+	var perms []model.Permission
+	err := getDB(ctx).Where("code IN ?", codes).Find(&perms).Error
+	return perms, err
+}
+
+// CreatePermission 创建权限
+func (d *RBACDAO) CreatePermission(ctx context.Context, p *model.Permission) error {
+	return getDB(ctx).Create(p).Error
+}
+
+// PermissionCodesByRole 获取角色绑定的全部权限码（表名为单数，见 pkg/database 的命名策略）
+func (d *RBACDAO) PermissionCodesByRole(ctx context.Context, roleName string) ([]string, error) {
+	var codes []string
+	err := getDB(ctx).Model(&model.Permission{}).
+		Joins("JOIN role_permission ON role_permission.permission_id = permission.id").
+		Joins("JOIN role ON role.id = role_permission.role_id").
+		Where("role.name = ?", roleName).
+		Order("permission.code ASC").
+		Pluck("permission.code", &codes).Error
+	return codes, err
+}
+
+// Grant 为角色绑定权限，已存在的绑定忽略
+func (d *RBACDAO) Grant(ctx context.Context, roleID uint, permissionIDs []uint) error {
+	if len(permissionIDs) == 0 {
+		return nil
+	}
+	bindings := make([]model.RolePermission, 0, len(permissionIDs))
+	for _, pid := range permissionIDs {
+		bindings = append(bindings, model.RolePermission{RoleID: roleID, PermissionID: pid})
+	}
+	return getDB(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(&bindings).Error
+}
+
+// Revoke 解除角色与权限的绑定
+func (d *RBACDAO) Revoke(ctx context.Context, roleID uint, permissionIDs []uint) error {
+	if len(permissionIDs) == 0 {
+		return nil
+	}
+	return getDB(ctx).
+		Where("role_id = ? AND permission_id IN ?", roleID, permissionIDs).
+		Delete(&model.RolePermission{}).Error
+}
+
+// EnsureRole 按名称查找角色，不存在则创建（用于写入内置数据）
+func (d *RBACDAO) EnsureRole(ctx context.Context, r *model.Role) error {
+	return getDB(ctx).Where(model.Role{Name: r.Name}).Attrs(model.Role{Description: r.Description}).FirstOrCreate(r).Error
+}
+
+// EnsurePermission 按权限码查找权限，不存在则创建（用于写入内置数据）
+func (d *RBACDAO) EnsurePermission(ctx context.Context, p *model.Permission) error {
+	return getDB(ctx).Where(model.Permission{Code: p.Code}).Attrs(model.Permission{Description: p.Description}).FirstOrCreate(p).Error
+}
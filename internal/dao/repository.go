@@ -0,0 +1,210 @@
+package dao
+
+import (
+	"context"
+	"reflect"
+
+	"github.com/liuchen/gin-craft/internal/dto"
+	pkgdb "github.com/liuchen/gin-craft/pkg/database"
+	"github.com/pkg/errors"
+	"gorm.io/gorm"
+)
+
+// Scope 查询条件，与 gorm.DB.Scopes 的参数相同
+type Scope = func(db *gorm.DB) *gorm.DB
+
+// ErrNotSoftDelete 模型不支持软删除
+var ErrNotSoftDelete = errors.New("model does not support soft delete")
+
+// trashed 软删除行的可见性
+type trashed int
+
+const (
+	trashedExclude trashed = iota // 默认：排除软删除的行
+	trashedWith                   // 包含软删除的行
+	trashedOnly                   // 只查软删除的行
+)
+
+// Repository 模型 T 的通用数据访问：按主键或条件读取、分页列表、计数、存在性判断与增删改。
+// 主键列为 id；T 含 gorm.DeletedAt 字段时 Delete 为软删除，默认查询排除已删除的行，
+// 可通过 WithTrashed / OnlyTrashed 改变。所有操作都带上调用方的 context，在 TxManager.Do 中调用时加入该事务。
+// 新模块的 DAO 通过 NewRepository 获得基础能力，只为特有查询编写方法：
+//
+//	func (d *ProductDAO) repo() *Repository[model.Product] {
+//		return NewRepository[model.Product](database.GetDatabase())
+//	}
+type Repository[T any] struct {
+	db      pkgdb.Database
+	trashed trashed
+}
+
+// NewRepository 创建模型 T 的 Repository
+func NewRepository[T any](db pkgdb.Database) *Repository[T] {
+	return &Repository[T]{db: db}
+}
+
+// WithTrashed 返回包含软删除行的副本
+func (r *Repository[T]) WithTrashed() *Repository[T] {
+	return &Repository[T]{db: r.db, trashed: trashedWith}
+}
+
+// OnlyTrashed 返回只查软删除行的副本；模型不支持软删除时查询不到任何行
+func (r *Repository[T]) OnlyTrashed() *Repository[T] {
+	return &Repository[T]{db: r.db, trashed: trashedOnly}
+}
+
+// DB 以 T 为模型、带 ctx 与软删除可见性的查询起点，供编写特有查询
+func (r *Repository[T]) DB(ctx context.Context) *gorm.DB {
+	q := pkgdb.Conn(ctx, r.db.GetDB()).Model(new(T))
+	switch r.trashed {
+	case trashedWith:
+		q = q.Unscoped()
+	case trashedOnly:
+		if !softDeletable[T]() {
+			return q.Where("1 = 0")
+		}
+		q = q.Unscoped().Where("deleted_at IS NOT NULL")
+	}
+	return q
+}
+
+// Get 按主键读取；找不到返回 gorm.ErrRecordNotFound
+func (r *Repository[T]) Get(ctx context.Context, id interface{}, scopes ...Scope) (*T, error) {
+	var m T
+	if err := r.DB(ctx).Scopes(scopes...).First(&m, id).Error; err != nil {
+		return nil, err
+	}
+	return &m, nil
+}
+
+// First 按条件读取第一行，scopes 未指定排序时取 id 最大的一行；找不到返回 gorm.ErrRecordNotFound
+func (r *Repository[T]) First(ctx context.Context, scopes ...Scope) (*T, error) {
+	var m T
+	// scopes 在执行时才生效，用 First 会把主键升序排在它们之前
+	if err := r.DB(ctx).Scopes(scopes...).Scopes(defaultOrder()).Take(&m).Error; err != nil {
+		return nil, err
+	}
+	return &m, nil
+}
+
+// List 按条件读取全部行；scopes 未指定排序时按 id 倒序
+func (r *Repository[T]) List(ctx context.Context, scopes ...Scope) ([]T, error) {
+	var list []T
+	err := r.DB(ctx).Scopes(scopes...).Scopes(defaultOrder()).Find(&list).Error
+	return list, err
+}
+
+// Page 分页读取，总数写入 p.Total（p.SkipTotal 时跳过）；scopes 中的排序之后追加 id 倒序，保证翻页稳定
+func (r *Repository[T]) Page(ctx context.Context, p *dto.Pagination, scopes ...Scope) ([]T, error) {
+	q := r.DB(ctx).Scopes(scopes...)
+	if err := countTotal(q, p); err != nil {
+		return nil, err
+	}
+	var list []T
+	err := q.Scopes(paginate(p), defaultOrder()).Find(&list).Error
+	return list, err
+}
+
+// Count 按条件计数
+func (r *Repository[T]) Count(ctx context.Context, scopes ...Scope) (int64, error) {
+	var cnt int64
+	err := r.DB(ctx).Scopes(scopes...).Count(&cnt).Error
+	return cnt, err
+}
+
+// Exists 是否存在满足条件的行，找到一行即返回
+func (r *Repository[T]) Exists(ctx context.Context, scopes ...Scope) (bool, error) {
+	var ids []interface{}
+	err := r.DB(ctx).Scopes(scopes...).Limit(1).Pluck("id", &ids).Error
+	return len(ids) > 0, err
+}
+
+// Create 创建一行
+func (r *Repository[T]) Create(ctx context.Context, m *T) error {
+	return r.DB(ctx).Create(m).Error
+}
+
+// CreateInBatches 批量创建，每条 INSERT 最多 batchSize 行；分多条写入时在同一事务中执行
+func (r *Repository[T]) CreateInBatches(ctx context.Context, list []*T, batchSize int) error {
+	return r.DB(ctx).CreateInBatches(list, batchSize).Error
+}
+
+// Update 按主键更新 updates 中的列，scopes 可追加条件（如乐观锁）；
+// updates 为空直接返回 nil，没有行被更新时返回 gorm.ErrRecordNotFound
+func (r *Repository[T]) Update(ctx context.Context, id interface{}, updates map[string]interface{}, scopes ...Scope) error {
+	if len(updates) == 0 {
+		return nil
+	}
+	res := r.DB(ctx).Scopes(scopes...).Where("id = ?", id).Updates(updates)
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// Delete 按主键删除，支持软删除的模型为软删除；没有行被删除时返回 gorm.ErrRecordNotFound
+func (r *Repository[T]) Delete(ctx context.Context, id interface{}) error {
+	return affected(r.DB(ctx).Delete(new(T), id))
+}
+
+// ForceDelete 按主键物理删除，包含已软删除的行
+func (r *Repository[T]) ForceDelete(ctx context.Context, id interface{}) error {
+	return affected(r.DB(ctx).Unscoped().Delete(new(T), id))
+}
+
+// Restore 恢复软删除的行；行不存在或未被删除时返回 gorm.ErrRecordNotFound
+func (r *Repository[T]) Restore(ctx context.Context, id interface{}) error {
+	if !softDeletable[T]() {
+		return ErrNotSoftDelete
+	}
+	return affected(r.DB(ctx).Unscoped().
+		Where("id = ? AND deleted_at IS NOT NULL", id).
+		Update("deleted_at", nil))
+}
+
+// Where 条件 scope
+func Where(query interface{}, args ...interface{}) Scope {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where(query, args...)
+	}
+}
+
+// OrderBy 排序 scope，order 须为代码中的常量或经过白名单校验
+func OrderBy(order string) Scope {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Order(order)
+	}
+}
+
+func affected(res *gorm.DB) error {
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+var deletedAtType = reflect.TypeOf(gorm.DeletedAt{})
+
+// softDeletable 模型 T 是否含 gorm.DeletedAt 字段（含嵌入的 gorm.Model）
+func softDeletable[T any]() bool {
+	return hasDeletedAt(reflect.TypeOf((*T)(nil)).Elem())
+}
+
+func hasDeletedAt(t reflect.Type) bool {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if f.Type == deletedAtType {
+			return true
+		}
+		if f.Anonymous && f.Type.Kind() == reflect.Struct && hasDeletedAt(f.Type) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,148 @@
+package dao
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/liuchen/gin-craft/internal/dto"
+	pkgdb "github.com/liuchen/gin-craft/pkg/database"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+type repoItem struct {
+	ID        uint
+	Name      string
+	Score     int
+	DeletedAt gorm.DeletedAt
+}
+
+type repoTag struct {
+	ID   uint
+	Name string
+}
+
+func newTestDatabase(t *testing.T) pkgdb.Database {
+	db := pkgdb.NewSQLiteDatabase(filepath.Join(t.TempDir(), "test.db"))
+	require.NoError(t, db.Connect())
+	t.Cleanup(func() { _ = db.Close() })
+	require.NoError(t, db.Migrate(&repoItem{}, &repoTag{}))
+	return db
+}
+
+func TestRepositoryCRUD(t *testing.T) {
+	ctx := context.Background()
+	r := NewRepository[repoItem](newTestDatabase(t))
+
+	require.NoError(t, r.CreateInBatches(ctx, []*repoItem{{Name: "a", Score: 1}, {Name: "b", Score: 2}, {Name: "c", Score: 2}}, 2))
+	item := &repoItem{Name: "d", Score: 3}
+	require.NoError(t, r.Create(ctx, item))
+	assert.Equal(t, uint(4), item.ID)
+
+	got, err := r.Get(ctx, item.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "d", got.Name)
+	_, err = r.Get(ctx, 99)
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+
+	got, err = r.First(ctx, Where("score = ?", 2))
+	require.NoError(t, err)
+	assert.Equal(t, "c", got.Name)
+
+	cnt, err := r.Count(ctx, Where("score >= ?", 2))
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), cnt)
+	ok, err := r.Exists(ctx, Where("name = ?", "b"))
+	require.NoError(t, err)
+	assert.True(t, ok)
+
+	require.NoError(t, r.Update(ctx, 1, map[string]interface{}{"score": 5}, Where("name = ?", "a")))
+	assert.ErrorIs(t, r.Update(ctx, 1, map[string]interface{}{"score": 6}, Where("name = ?", "x")), gorm.ErrRecordNotFound)
+	assert.NoError(t, r.Update(ctx, 99, nil))
+
+	list, err := r.List(ctx, OrderBy("score DESC"))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a", "d", "c", "b"}, names(list))
+
+	p := &dto.Pagination{NowPage: 2, PerPage: 3}
+	list, err = r.Page(ctx, p)
+	require.NoError(t, err)
+	assert.Equal(t, int64(4), p.Total)
+	assert.Equal(t, []string{"a"}, names(list))
+}
+
+func TestRepositorySoftDelete(t *testing.T) {
+	ctx := context.Background()
+	db := newTestDatabase(t)
+	r := NewRepository[repoItem](db)
+	require.NoError(t, r.CreateInBatches(ctx, []*repoItem{{Name: "a"}, {Name: "b"}}, 10))
+
+	require.NoError(t, r.Delete(ctx, 1))
+	assert.ErrorIs(t, r.Delete(ctx, 1), gorm.ErrRecordNotFound)
+	_, err := r.Get(ctx, 1)
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+	ok, err := r.Exists(ctx, Where("name = ?", "a"))
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	list, err := r.WithTrashed().List(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"b", "a"}, names(list))
+	list, err = r.OnlyTrashed().List(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a"}, names(list))
+
+	require.NoError(t, r.Restore(ctx, 1))
+	assert.ErrorIs(t, r.Restore(ctx, 1), gorm.ErrRecordNotFound)
+	_, err = r.Get(ctx, 1)
+	require.NoError(t, err)
+
+	require.NoError(t, r.Delete(ctx, 2))
+	require.NoError(t, r.ForceDelete(ctx, 2))
+	cnt, err := r.WithTrashed().Count(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), cnt)
+
+	// 不支持软删除的模型：Delete 为物理删除，没有已删除的行可查
+	tags := NewRepository[repoTag](db)
+	require.NoError(t, tags.Create(ctx, &repoTag{Name: "x"}))
+	assert.ErrorIs(t, tags.Restore(ctx, 1), ErrNotSoftDelete)
+	cnt, err = tags.OnlyTrashed().Count(ctx)
+	require.NoError(t, err)
+	assert.Zero(t, cnt)
+	require.NoError(t, tags.Delete(ctx, 1))
+	cnt, err = tags.WithTrashed().Count(ctx)
+	require.NoError(t, err)
+	assert.Zero(t, cnt)
+}
+
+func names(list []repoItem) []string {
+	out := make([]string, 0, len(list))
+	for _, it := range list {
+		out = append(out, it.Name)
+	}
+	return out
+}
+
+func TestRepositoryInTransaction(t *testing.T) {
+	ctx := context.Background()
+	db := newTestDatabase(t)
+	r := NewRepository[repoItem](db)
+	errFail := errors.New("fail")
+
+	err := pkgdb.Transaction(ctx, db.GetDB(), func(ctx context.Context) error {
+		require.NoError(t, r.Create(ctx, &repoItem{Name: "a"}))
+		ok, err := r.Exists(ctx, Where("name = ?", "a"))
+		require.NoError(t, err)
+		assert.True(t, ok)
+		return errFail
+	})
+	assert.ErrorIs(t, err, errFail)
+
+	cnt, err := r.Count(ctx)
+	require.NoError(t, err)
+	assert.Zero(t, cnt)
+}
@@ -0,0 +1,51 @@
+package dao
+
+import (
+	"context"
+	"sync"
+
+	"github.com/liuchen/gin-craft/internal/constant"
+	pkgCtx "github.com/liuchen/gin-craft/internal/pkg/context"
+	"github.com/liuchen/gin-craft/internal/pkg/database"
+	apperr "github.com/liuchen/gin-craft/internal/pkg/errors"
+	pkgdb "github.com/liuchen/gin-craft/pkg/database"
+	"github.com/liuchen/gin-craft/pkg/logger"
+	"go.uber.org/zap"
+)
+
+// TxManager 事务管理：service 在 Do 中调用多个 DAO，DAO 从 ctx 中取得事务，无需传递 *gorm.DB
+type TxManager struct{}
+
+var (
+	txManager     *TxManager
+	txManagerOnce sync.Once
+)
+
+// GetTxManager 获取 TxManager 单例实例
+func GetTxManager() *TxManager {
+	txManagerOnce.Do(func() {
+		txManager = &TxManager{}
+	})
+	return txManager
+}
+
+// Do 在事务中执行 fn，fn 内的 DAO 调用须使用传入的 ctx。ctx 已在事务中时以保存点嵌套。
+// fn 返回错误时回滚：业务错误（*errors.AppError）原样返回，因此 fn 应在内部把需要区分的 DAO 错误
+// （如 gorm.ErrRecordNotFound）转换为业务错误；其余错误及提交失败记录日志后返回 DBTransactionFailed
+func (m *TxManager) Do(ctx context.Context, fn func(ctx context.Context) error) error {
+	err := pkgdb.Transaction(ctx, database.GetDB(), fn)
+	if err == nil || apperr.IsAppError(err) {
+		return err
+	}
+	if appCtx := pkgCtx.GetContext(ctx); appCtx != nil {
+		appCtx.LogError("数据库事务失败", zap.Error(err))
+	} else {
+		logger.Error("Database transaction failed", zap.Error(err))
+	}
+	return apperr.New(constant.DBTransactionFailed)
+}
+
+// AfterCommit ctx 在事务中时 fn 在事务提交后执行、回滚则丢弃，否则立即执行
+func AfterCommit(ctx context.Context, fn func()) {
+	pkgdb.AfterCommit(ctx, fn)
+}
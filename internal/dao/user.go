@@ -1,15 +1,23 @@
 package dao
 
 import (
+	"context"
+	"sort"
+	"strings"
 	"sync"
+	"time"
 
+	"github.com/liuchen/gin-craft/internal/constant"
+	"github.com/liuchen/gin-craft/internal/dto"
 	dtoUser "github.com/liuchen/gin-craft/internal/dto/user"
 	"github.com/liuchen/gin-craft/internal/model"
 	"github.com/liuchen/gin-craft/internal/pkg/database"
+	"github.com/liuchen/gin-craft/internal/pkg/search"
+	"github.com/liuchen/gin-craft/pkg/query"
 	"gorm.io/gorm"
 )
 
-// UserDAO 用户数据访问对象
+// UserDAO 用户数据访问对象。写入后对检索索引的同步在所在事务提交后执行，回滚时不会生效
 type UserDAO struct{}
 
 var (
@@ -25,108 +33,373 @@ func GetUserDAO() *UserDAO {
 	return userDAO
 }
 
+// repo 用户的通用数据访问；数据库在启动时才初始化，每次调用时获取
+func (d *UserDAO) repo() *Repository[model.User] {
+	return NewRepository[model.User](database.GetDatabase())
+}
+
 // GetByID 根据 ID 获取用户；找不到返回 gorm.ErrRecordNotFound
-func (d *UserDAO) GetByID(id uint) (*model.User, error) {
-	var u model.User
-	if err := database.GetDB().First(&u, id).Error; err != nil {
-		return nil, err
-	}
-	return &u, nil
+func (d *UserDAO) GetByID(ctx context.Context, id uint) (*model.User, error) {
+	return d.repo().Get(ctx, id)
 }
 
 // GetByUsername 根据用户名获取用户
-func (d *UserDAO) GetByUsername(username string) (*model.User, error) {
-	var u model.User
-	if err := database.GetDB().Where("username = ?", username).First(&u).Error; err != nil {
-		return nil, err
-	}
-	return &u, nil
+func (d *UserDAO) GetByUsername(ctx context.Context, username string) (*model.User, error) {
+	return d.repo().First(ctx, Where("username = ?", username))
 }
 
 // GetByEmail 根据邮箱获取用户
-func (d *UserDAO) GetByEmail(email string) (*model.User, error) {
-	var u model.User
-	if err := database.GetDB().Where("email = ?", email).First(&u).Error; err != nil {
-		return nil, err
-	}
-	return &u, nil
+func (d *UserDAO) GetByEmail(ctx context.Context, email string) (*model.User, error) {
+	return d.repo().First(ctx, Where("email = ?", email))
 }
 
-// Create 创建用户
-func (d *UserDAO) Create(u *model.User) error {
-	return database.GetDB().Create(u).Error
+// Create 创建用户，并写入检索索引
+func (d *UserDAO) Create(ctx context.Context, u *model.User) error {
+	if err := d.repo().Create(ctx, u); err != nil {
+		return err
+	}
+	AfterCommit(ctx, func() { search.IndexUser(ctx, u) })
+	return nil
 }
 
-// Update 白名单字段更新；updates 为空则直接返回 nil
-func (d *UserDAO) Update(id uint, updates map[string]interface{}) error {
-	if len(updates) == 0 {
-		return nil
+// Update 白名单字段更新；updates 为空则直接返回 nil。修改用户名或邮箱时同步检索索引
+func (d *UserDAO) Update(ctx context.Context, id uint, updates map[string]interface{}) error {
+	if err := d.repo().Update(ctx, id, updates); err != nil {
+		return err
 	}
-	res := database.GetDB().Model(&model.User{}).Where("id = ?", id).Updates(updates)
-	if res.Error != nil {
-		return res.Error
-	}
-	if res.RowsAffected == 0 {
-		return gorm.ErrRecordNotFound
+	_, username := updates["username"]
+	_, email := updates["email"]
+	if username || email {
+		AfterCommit(ctx, func() { search.SyncUser(ctx, id) })
 	}
 	return nil
 }
 
-// Delete 软删除用户
-func (d *UserDAO) Delete(id uint) error {
-	res := database.GetDB().Delete(&model.User{}, id)
-	if res.Error != nil {
-		return res.Error
+// Delete 软删除用户，并移出检索索引
+func (d *UserDAO) Delete(ctx context.Context, id uint) error {
+	if err := d.repo().Delete(ctx, id); err != nil {
+		return err
 	}
-	if res.RowsAffected == 0 {
-		return gorm.ErrRecordNotFound
+	AfterCommit(ctx, func() { search.RemoveUser(ctx, id) })
+	return nil
+}
+
+// BatchCreate 批量创建用户，成功后写入检索索引。
+// 超过单条 INSERT 的行数时 GORM 在同一事务中分多条写入，全部成功或全部失败
+func (d *UserDAO) BatchCreate(ctx context.Context, users []*model.User) error {
+	if err := d.repo().CreateInBatches(ctx, users, batchInsertSize); err != nil {
+		return err
 	}
+	AfterCommit(ctx, func() {
+		for _, u := range users {
+			search.IndexUser(ctx, u)
+		}
+	})
 	return nil
 }
 
+// ExistingNames 查询已被占用的用户名与邮箱（含已删除用户，唯一索引同样约束它们），返回的值统一转为小写
+func (d *UserDAO) ExistingNames(ctx context.Context, usernames, emails []string) (map[string]bool, map[string]bool, error) {
+	var users []model.User
+	err := getDB(ctx).Unscoped().Select("username", "email").
+		Where("username IN ? OR email IN ?", usernames, emails).Find(&users).Error
+	if err != nil {
+		return nil, nil, err
+	}
+	names, mails := make(map[string]bool, len(users)), make(map[string]bool, len(users))
+	for _, u := range users {
+		names[strings.ToLower(u.Username)] = true
+		mails[strings.ToLower(u.Email)] = true
+	}
+	return names, mails, nil
+}
+
 // ExistsByUsername 检查用户名是否存在
-func (d *UserDAO) ExistsByUsername(username string) (bool, error) {
-	var cnt int64
-	err := database.GetDB().Model(&model.User{}).Where("username = ?", username).Count(&cnt).Error
-	return cnt > 0, err
+func (d *UserDAO) ExistsByUsername(ctx context.Context, username string) (bool, error) {
+	return d.repo().Exists(ctx, Where("username = ?", username))
 }
 
 // ExistsByEmail 检查邮箱是否存在
-func (d *UserDAO) ExistsByEmail(email string) (bool, error) {
-	var cnt int64
-	err := database.GetDB().Model(&model.User{}).Where("email = ?", email).Count(&cnt).Error
-	return cnt > 0, err
+func (d *UserDAO) ExistsByEmail(ctx context.Context, email string) (bool, error) {
+	return d.repo().Exists(ctx, Where("email = ?", email))
 }
 
-// GetList 获取用户列表（支持用户名/邮箱模糊过滤 + 分页）
-func (d *UserDAO) GetList(req *dtoUser.ListRequest) ([]model.User, error) {
-	q := database.GetDB().Model(&model.User{})
+// UserQuerySchema 用户列表可过滤、排序与返回的字段，字段名与 dto/user.User 的 JSON 字段一致
+var UserQuerySchema = query.Schema{
+	"id":             {Type: query.Int, Filterable: true, Sortable: true},
+	"username":       {Type: query.String, Filterable: true, Sortable: true},
+	"email":          {Type: query.String, Filterable: true, Sortable: true},
+	"email_verified": {Column: "email_verified_at", Type: query.Time},
+	"created_at":     {Type: query.Time, Filterable: true, Sortable: true},
+	"updated_at":     {Type: query.Time, Filterable: true, Sortable: true},
+}
+
+// GetList 获取用户列表（支持关键字检索、用户名/邮箱模糊过滤 + 通用查询 lq + 分页），lq 由 UserQuerySchema 解析
+func (d *UserDAO) GetList(ctx context.Context, req *dtoUser.ListRequest, lq *query.Query) ([]model.User, error) {
+	q, ranked, err := d.listQuery(ctx, req, lq)
+	if err != nil {
+		return nil, err
+	}
+	if ranked != nil && len(lq.Sorts) == 0 {
+		return d.rankedList(ctx, q, ranked, &req.Pagination)
+	}
+
+	if err := countTotal(q, &req.Pagination); err != nil {
+		return nil, err
+	}
+
+	var users []model.User
+	if err := q.Scopes(lq.Select(), paginate(&req.Pagination), lq.Order(defaultOrder())).Find(&users).Error; err != nil {
+		return nil, err
+	}
+	return users, nil
+}
+
+// Export 按 GetList 的筛选条件分批读取用户（按 id 升序，忽略分页与排序），每批调用一次 fn
+func (d *UserDAO) Export(ctx context.Context, req *dtoUser.ListRequest, lq *query.Query, batchSize int, fn func([]model.User) error) error {
+	q, _, err := d.listQuery(ctx, req, lq)
+	if err != nil {
+		return err
+	}
+	var users []model.User
+	return q.FindInBatches(&users, batchSize, func(*gorm.DB, int) error {
+		return fn(users)
+	}).Error
+}
+
+// listQuery 用户列表的筛选条件。指定关键字且检索可用时，额外返回按相关度排列的命中ID（非 nil）
+func (d *UserDAO) listQuery(ctx context.Context, req *dtoUser.ListRequest, lq *query.Query) (*gorm.DB, []uint, error) {
+	req.Truncated = false
+	q := getDB(ctx).Model(&model.User{})
 	if req.Username != "" {
-		q = q.Where("username LIKE ?", "%"+req.Username+"%")
+		q = q.Where("username LIKE ? ESCAPE '!'", query.LikeContains(req.Username))
 	}
 	if req.Email != "" {
-		q = q.Where("email LIKE ?", "%"+req.Email+"%")
+		q = q.Where("email LIKE ? ESCAPE '!'", query.LikeContains(req.Email))
 	}
+	q = q.Scopes(lq.Where())
 
-	if err := q.Count(&req.Total).Error; err != nil {
+	if req.Keyword == "" {
+		return q, nil, nil
+	}
+	if search.GetSearcher() == nil {
+		like := query.LikeContains(req.Keyword)
+		return q.Where("username LIKE ? ESCAPE '!' OR email LIKE ? ESCAPE '!'", like, like), nil, nil
+	}
+	ids, truncated, err := search.SearchUsers(ctx, req.Keyword)
+	if err != nil {
+		return nil, nil, err
+	}
+	// 只有前 max_hits 条命中参与筛选，此时 total 是下限
+	req.Truncated = truncated
+	return q.Where("id IN ?", ids), ids, nil
+}
+
+// rankedList 按检索相关度排序分页：先取出满足其余筛选条件的命中ID，按 ranked 的顺序分页后再查询当前页
+func (d *UserDAO) rankedList(ctx context.Context, q *gorm.DB, ranked []uint, p *dto.Pagination) ([]model.User, error) {
+	var matched []uint
+	if err := q.Pluck("id", &matched).Error; err != nil {
 		return nil, err
 	}
+	keep := make(map[uint]bool, len(matched))
+	for _, id := range matched {
+		keep[id] = true
+	}
+	ids := make([]uint, 0, len(matched))
+	for _, id := range ranked {
+		if keep[id] {
+			ids = append(ids, id)
+		}
+	}
+	p.Total = int64(len(ids))
+	if p.SkipTotal {
+		p.Total = dto.TotalSkipped
+	}
+
+	page, size := p.GetPage(), min(p.GetPageSize(), maxPageSize)
+	start := min((page-1)*size, len(ids))
+	ids = ids[start:min(start+size, len(ids))]
+	if len(ids) == 0 {
+		return nil, nil
+	}
 
 	var users []model.User
-	if err := q.Scopes(paginate(&req.Pagination), defaultOrder()).Find(&users).Error; err != nil {
+	if err := getDB(ctx).Where("id IN ?", ids).Find(&users).Error; err != nil {
 		return nil, err
 	}
+	pos := make(map[uint]int, len(ids))
+	for i, id := range ids {
+		pos[id] = i
+	}
+	sort.Slice(users, func(i, j int) bool { return pos[users[i].ID] < pos[users[j].ID] })
 	return users, nil
 }
 
-// UpdatePassword 更新密码
-func (d *UserDAO) UpdatePassword(id uint, password string) error {
-	res := database.GetDB().Model(&model.User{}).Where("id = ?", id).Update("password", password)
+// GetListByCursor 游标分页获取用户列表，按 k 定位起始位置，最多返回 size+1 条（多出的一条用于判断是否还有下一页）；
+// req.WithTotal 时统计总数写入 req.Total
+func (d *UserDAO) GetListByCursor(ctx context.Context, req *dtoUser.CursorListRequest, k Keyset, size int) ([]model.User, error) {
+	q := getDB(ctx).Model(&model.User{})
+	if req.Username != "" {
+		q = q.Where("username LIKE ? ESCAPE '!'", query.LikeContains(req.Username))
+	}
+	if req.Email != "" {
+		q = q.Where("email LIKE ? ESCAPE '!'", query.LikeContains(req.Email))
+	}
+
+	if req.WithTotal {
+		if err := q.Count(&req.Total).Error; err != nil {
+			return nil, err
+		}
+	}
+
+	var users []model.User
+	if err := q.Scopes(keysetPaginate(k, size)).Find(&users).Error; err != nil {
+		return nil, err
+	}
+	return users, nil
+}
+
+// AdminUserQuerySchema 管理后台用户列表允许排序的字段
+var AdminUserQuerySchema = query.Schema{
+	"id":         {Type: query.Int, Sortable: true},
+	"username":   {Type: query.String, Sortable: true},
+	"email":      {Type: query.String, Sortable: true},
+	"role":       {Type: query.String, Sortable: true},
+	"status":     {Type: query.String, Sortable: true},
+	"created_at": {Type: query.Time, Sortable: true},
+	"updated_at": {Type: query.Time, Sortable: true},
+}
+
+// AdminList 管理后台用户列表：关键字、角色、状态、注册时间筛选，可包含已删除用户；lq 由 AdminUserQuerySchema 解析
+func (d *UserDAO) AdminList(ctx context.Context, req *dtoUser.AdminListRequest, lq *query.Query) ([]model.User, error) {
+	q := getDB(ctx).Model(&model.User{})
+	switch {
+	case req.Trashed == "only":
+		q = q.Unscoped().Where("deleted_at IS NOT NULL")
+	case req.Trashed == "with", req.Status == constant.UserStatusDeleted:
+		q = q.Unscoped()
+	}
+	if req.Keyword != "" {
+		like := query.LikeContains(req.Keyword)
+		q = q.Where("username LIKE ? ESCAPE '!' OR email LIKE ? ESCAPE '!'", like, like)
+	}
+	if req.Role != "" {
+		q = q.Where("role = ?", req.Role)
+	}
+	if req.Status != "" {
+		q = q.Where("status = ?", req.Status)
+	}
+	if req.CreatedFrom != nil {
+		q = q.Where("created_at >= ?", *req.CreatedFrom)
+	}
+	if req.CreatedTo != nil {
+		q = q.Where("created_at < ?", req.CreatedTo.AddDate(0, 0, 1))
+	}
+
+	if err := countTotal(q, &req.Pagination); err != nil {
+		return nil, err
+	}
+
+	var users []model.User
+	if err := q.Scopes(paginate(&req.Pagination), lq.Order(defaultOrder())).Find(&users).Error; err != nil {
+		return nil, err
+	}
+	return users, nil
+}
+
+// GetByIDUnscoped 根据 ID 获取用户，包含已软删除的用户
+func (d *UserDAO) GetByIDUnscoped(ctx context.Context, id uint) (*model.User, error) {
+	return d.repo().WithTrashed().Get(ctx, id)
+}
+
+// ChangeStatus 迁移账号状态并写入变更记录。迁移到 deleted 时同时软删除并移出检索索引，从 deleted 迁出时恢复；
+// 仅当当前状态仍为 from 时生效，并发修改或用户不存在时返回 gorm.ErrRecordNotFound。涉及多次写入，须在 TxManager.Do 中调用
+func (d *UserDAO) ChangeStatus(ctx context.Context, id uint, from, to, reason, actorID string, at time.Time) error {
+	db := getDB(ctx)
+	updates := map[string]interface{}{
+		"status":            to,
+		"status_reason":     reason,
+		"status_changed_at": at,
+	}
+	switch {
+	case to == constant.UserStatusDeleted:
+		updates["deleted_at"] = at
+	case from == constant.UserStatusDeleted:
+		updates["deleted_at"] = nil
+	}
+	res := db.Unscoped().Model(&model.User{}).Where("id = ? AND status = ?", id, from).Updates(updates)
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	err := db.Create(&model.UserStatusLog{
+		UserID:    id,
+		From:      from,
+		To:        to,
+		Reason:    reason,
+		ActorID:   actorID,
+		CreatedAt: at,
+	}).Error
+	if err != nil {
+		return err
+	}
+	switch {
+	case to == constant.UserStatusDeleted:
+		AfterCommit(ctx, func() { search.RemoveUser(ctx, id) })
+	case from == constant.UserStatusDeleted:
+		AfterCommit(ctx, func() { search.SyncUser(ctx, id) })
+	}
+	return nil
+}
+
+// ListStatusLogs 账号状态变更记录，按时间倒序
+func (d *UserDAO) ListStatusLogs(ctx context.Context, userID uint) ([]model.UserStatusLog, error) {
+	var logs []model.UserStatusLog
+	err := getDB(ctx).Where("user_id = ?", userID).Scopes(defaultOrder()).Find(&logs).Error
+	return logs, err
+}
+
+// GetStatus 查询账号状态，包含已删除用户；找不到返回 gorm.ErrRecordNotFound
+func (d *UserDAO) GetStatus(ctx context.Context, id uint) (string, error) {
+	var u model.User
+	if err := getDB(ctx).Unscoped().Select("id", "status").First(&u, id).Error; err != nil {
+		return "", err
+	}
+	return u.Status, nil
+}
+
+// HardDelete 彻底删除用户及其外部身份、两步验证、恢复码、状态变更记录与 API 密钥，不可恢复，并移出检索索引；
+// 用户不存在时返回 gorm.ErrRecordNotFound。涉及多次写入，须在 TxManager.Do 中调用
+func (d *UserDAO) HardDelete(ctx context.Context, id uint) error {
+	db := getDB(ctx).Unscoped()
+	for _, m := range []interface{}{&model.UserIdentity{}, &model.UserMFA{}, &model.MFARecoveryCode{}, &model.UserStatusLog{}} {
+		if err := db.Where("user_id = ?", id).Delete(m).Error; err != nil {
+			return err
+		}
+	}
+	if err := db.Where("owner_id = ?", id).Delete(&model.APIKey{}).Error; err != nil {
+		return err
+	}
+	res := db.Delete(&model.User{}, id)
 	if res.Error != nil {
 		return res.Error
 	}
 	if res.RowsAffected == 0 {
 		return gorm.ErrRecordNotFound
 	}
+	AfterCommit(ctx, func() { search.RemoveUser(ctx, id) })
 	return nil
 }
+
+// MarkEmailVerified 标记邮箱已验证；仅当邮箱仍为 email 时生效，避免验证旧邮箱的链接作用于新邮箱
+func (d *UserDAO) MarkEmailVerified(ctx context.Context, id uint, email string, at time.Time) error {
+	return d.repo().Update(ctx, id,
+		map[string]interface{}{"email_verified_at": at}, Where("email = ?", email))
+}
+
+// UpdatePassword 更新密码
+func (d *UserDAO) UpdatePassword(ctx context.Context, id uint, password string) error {
+	return d.repo().Update(ctx, id, map[string]interface{}{"password": password})
+}
@@ -0,0 +1,34 @@
+package apikey
+
+import (
+	"time"
+
+	"github.com/liuchen/gin-craft/internal/dto"
+)
+
+// CreateRequest 创建 API 密钥请求参数
+type CreateRequest struct {
+	Name      string     `json:"name" binding:"required,max=50" example:"报表同步"`                        // 名称
+	OwnerID   uint       `json:"owner_id" binding:"required" example:"1"`                              // 归属用户ID
+	Scopes    []string   `json:"scopes" binding:"omitempty,dive,required,max=100" example:"data:read"` // 授权范围
+	ExpiresAt *time.Time `json:"expires_at" binding:"omitempty" example:"2025-01-01T00:00:00Z"`        // 过期时间，为空表示永不过期
+}
+
+// UpdateRequest 更新 API 密钥请求参数，字段为空表示不修改
+type UpdateRequest struct {
+	ID        uint       `json:"id" binding:"required" example:"1"`
+	Name      string     `json:"name" binding:"omitempty,max=50" example:"报表同步"`
+	Scopes    []string   `json:"scopes" binding:"omitempty,dive,required,max=100" example:"data:read"`
+	ExpiresAt *time.Time `json:"expires_at" binding:"omitempty" example:"2025-01-01T00:00:00Z"`
+}
+
+// ListRequest API 密钥列表请求参数
+type ListRequest struct {
+	dto.Pagination
+	OwnerID uint `form:"owner_id" json:"owner_id" example:"1"` // 按归属用户筛选
+}
+
+// DeleteRequest 吊销 API 密钥请求参数
+type DeleteRequest struct {
+	ID uint `json:"id" binding:"required" example:"1"`
+}
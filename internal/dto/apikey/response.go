@@ -0,0 +1,32 @@
+package apikey
+
+import (
+	"time"
+
+	"github.com/liuchen/gin-craft/internal/dto"
+)
+
+// APIKey API 密钥响应参数（不含明文与摘要）
+type APIKey struct {
+	ID         uint       `json:"id" example:"1"`                              // 记录ID
+	KeyID      string     `json:"key_id" example:"3f9a0c7b12de"`               // 密钥公开ID
+	Name       string     `json:"name" example:"报表同步"`                         // 名称
+	OwnerID    uint       `json:"owner_id" example:"1"`                        // 归属用户ID
+	Scopes     []string   `json:"scopes" example:"data:read"`                  // 授权范围
+	ExpiresAt  *time.Time `json:"expires_at" example:"2025-01-01T00:00:00Z"`   // 过期时间
+	LastUsedAt *time.Time `json:"last_used_at" example:"2024-06-01T12:00:00Z"` // 最近使用时间
+	CreatedAt  time.Time  `json:"created_at" example:"2024-01-01T00:00:00Z"`   // 创建时间
+}
+
+// CreateResponse 创建 API 密钥响应参数
+type CreateResponse struct {
+	APIKey
+	Key           string `json:"key" example:"gck_3f9a0c7b12de_xxxxxxxx"`     // 完整密钥，仅返回这一次，/api/v1/open/* 接口以 X-API-Key 请求头携带
+	SigningSecret string `json:"signing_secret" example:"Jm1bQ0nX8pZ2...xq4"` // /api/v1/api/* 请求签名密钥，仅返回这一次，与 key_id 一起交给 pkg/signature
+}
+
+// ListResponse API 密钥列表响应参数
+type ListResponse struct {
+	List []APIKey `json:"list"`
+	dto.Pagination
+}
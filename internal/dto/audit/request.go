@@ -0,0 +1,19 @@
+package audit
+
+import (
+	"time"
+
+	"github.com/liuchen/gin-craft/internal/dto"
+)
+
+// ListRequest 审计日志列表请求参数
+type ListRequest struct {
+	dto.Pagination
+	ActorID string     `form:"actor_id" json:"actor_id" example:"1"`                           // 操作人ID
+	Action  string     `form:"action" json:"action" example:"user.disable"`                    // 动作
+	Target  string     `form:"target" json:"target" example:"user:42"`                         // 目标
+	TraceID string     `form:"trace_id" json:"trace_id" example:"5f1c..."`                     // 请求链路ID
+	From    *time.Time `form:"from" json:"from" time_format:"2006-01-02" example:"2024-01-01"` // 起始日期（含）
+	To      *time.Time `form:"to" json:"to" time_format:"2006-01-02" example:"2024-12-31"`     // 截止日期（含当天）
+	Sort    string     `form:"sort" json:"sort" example:"-created_at"`                         // 排序字段，逗号分隔，- 前缀表示倒序；可选 id/actor_id/action/target/created_at
+}
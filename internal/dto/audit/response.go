@@ -0,0 +1,28 @@
+package audit
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/liuchen/gin-craft/internal/dto"
+)
+
+// AuditLog 审计日志响应参数
+type AuditLog struct {
+	ID        uint            `json:"id" example:"1"`                            // 记录ID
+	ActorID   string          `json:"actor_id" example:"1"`                      // 操作人ID，匿名操作为空
+	ActorName string          `json:"actor_name" example:"admin"`                // 操作人用户名
+	Action    string          `json:"action" example:"user.disable"`             // 动作
+	Target    string          `json:"target" example:"user:42"`                  // 目标
+	Changes   json.RawMessage `json:"changes" swaggertype:"object"`              // 变更字段：{"字段":{"before":..,"after":..}}
+	TraceID   string          `json:"trace_id" example:"5f1c..."`                // 请求链路ID
+	IP        string          `json:"ip" example:"10.0.0.1"`                     // 客户端 IP
+	UserAgent string          `json:"user_agent" example:"Mozilla/5.0"`          // 客户端 UA
+	CreatedAt time.Time       `json:"created_at" example:"2024-01-01T00:00:00Z"` // 发生时间
+}
+
+// ListResponse 审计日志列表响应参数
+type ListResponse struct {
+	List []AuditLog `json:"list"`
+	dto.Pagination
+}
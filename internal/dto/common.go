@@ -2,11 +2,16 @@ package dto
 
 // Pagination 分页请求参数
 type Pagination struct {
-	NowPage int   `form:"now_page" json:"now_page" binding:"omitempty,min=1" example:"1"`          // 页码，默认1
-	PerPage int   `form:"per_page" json:"per_page" binding:"omitempty,min=1,max=100" example:"10"` // 每页数量，默认10，最大100
-	Total   int64 `json:"total"`
+	NowPage   int   `form:"now_page" json:"now_page" binding:"omitempty,min=1" example:"1"`          // 页码，默认1
+	PerPage   int   `form:"per_page" json:"per_page" binding:"omitempty,min=1,max=100" example:"10"` // 每页数量，默认10，最大100
+	SkipTotal bool  `form:"skip_total" json:"skip_total,omitempty" example:"false"`                  // 为 true 时不统计总数，total 返回 -1
+	Total     int64 `json:"total"`
+	Truncated bool  `json:"truncated,omitempty"` // 关键字检索命中数达到 search.max_hits 上限，total 只统计了取回的命中，实际可能更多
 }
 
+// TotalSkipped 未统计总数时 Total 的取值
+const TotalSkipped = -1
+
 // GetPage 获取页码，如果为0则返回默认值1
 func (p *Pagination) GetPage() int {
 	if p.NowPage <= 0 {
@@ -23,6 +28,32 @@ func (p *Pagination) GetPageSize() int {
 	return p.PerPage
 }
 
+// CursorPagination 游标（keyset）分页请求参数：按排序键定位下一页，不使用 OFFSET，
+// 翻到深页时性能不下降。默认不统计总数
+type CursorPagination struct {
+	Cursor     string `form:"cursor" json:"cursor" example:"eyJzIjoiLWlkIiwiaSI6NDJ9.x9Zk..."`         // 上一页返回的 next_cursor，为空表示第一页
+	PerPage    int    `form:"per_page" json:"per_page" binding:"omitempty,min=1,max=100" example:"10"` // 每页数量，默认10，最大100
+	WithTotal  bool   `form:"with_total" json:"with_total,omitempty" example:"false"`                  // 为 true 时统计总数，否则 total 返回 -1
+	NextCursor string `json:"next_cursor" example:"eyJzIjoiLWlkIiwiaSI6MzJ9.a1Bc..."`                  // 下一页游标，没有更多数据时为空
+	HasMore    bool   `json:"has_more" example:"true"`                                                 // 是否还有下一页
+	Total      int64  `json:"total" example:"-1"`
+}
+
+// GetPageSize 获取每页数量，如果为0则返回默认值10
+func (p *CursorPagination) GetPageSize() int {
+	if p.PerPage <= 0 {
+		return 10
+	}
+	return p.PerPage
+}
+
+// Query 列表通用查询参数，语法见 pkg/query；可用字段由各列表接口的白名单决定
+type Query struct {
+	Filter string `form:"filter" json:"filter" example:"email:like:foo,created_at:gte:2024-01-01"` // 过滤条件，逗号分隔的 字段:操作符:值；操作符 eq/ne/gt/gte/lt/lte/like/in，in 的多个值以 | 分隔
+	Sort   string `form:"sort" json:"sort" example:"-created_at,id"`                               // 排序字段，逗号分隔，- 前缀表示倒序
+	Fields string `form:"fields" json:"fields" example:"id,username"`                              // 返回字段，逗号分隔，为空返回全部字段
+}
+
 // IDResponse 通用ID响应参数
 type IDResponse struct {
 	ID interface{} `json:"id" example:"1"` // ID，可以是int、uint、string等
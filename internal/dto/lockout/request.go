@@ -0,0 +1,13 @@
+package lockout
+
+// ListRequest 登录锁定查询请求参数；用户名与 IP 均为空时列出全部锁定中的条目
+type ListRequest struct {
+	Username string `form:"username" json:"username" example:"john_doe"` // 按用户名查询
+	IP       string `form:"ip" json:"ip" example:"203.0.113.7"`          // 按客户端 IP 查询
+}
+
+// ClearRequest 解除登录锁定请求参数，至少填写一项
+type ClearRequest struct {
+	Username string `json:"username" example:"john_doe"`                     // 清除该用户名的失败计数与锁定
+	IP       string `json:"ip" binding:"omitempty,ip" example:"203.0.113.7"` // 清除该 IP 的失败计数与锁定
+}
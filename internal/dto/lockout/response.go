@@ -0,0 +1,18 @@
+package lockout
+
+import "time"
+
+// Lockout 登录失败计数与锁定状态
+type Lockout struct {
+	Type        string     `json:"type" example:"account"`                                // account（用户名）/ ip
+	Key         string     `json:"key" example:"john_doe"`                                // 用户名或 IP
+	Failures    int64      `json:"failures" example:"6"`                                  // 窗口内连续失败次数
+	Locked      bool       `json:"locked" example:"true"`                                 // 是否锁定中
+	RetryAfter  int        `json:"retry_after" example:"60"`                              // 剩余锁定秒数
+	LockedUntil *time.Time `json:"locked_until,omitempty" example:"2024-01-01T00:01:00Z"` // 锁定截止时间
+}
+
+// ListResponse 登录锁定列表响应参数
+type ListResponse struct {
+	List []Lockout `json:"list"`
+}
@@ -0,0 +1,19 @@
+package rbac
+
+// CreateRoleRequest 创建角色请求参数
+type CreateRoleRequest struct {
+	Name        string `json:"name" binding:"required,min=2,max=50" example:"editor"` // 角色名
+	Description string `json:"description" binding:"omitempty,max=255" example:"编辑"`  // 描述
+}
+
+// CreatePermissionRequest 创建权限请求参数
+type CreatePermissionRequest struct {
+	Code        string `json:"code" binding:"required,max=100" example:"user:delete"`  // 权限码，格式为 资源:操作
+	Description string `json:"description" binding:"omitempty,max=255" example:"删除用户"` // 描述
+}
+
+// BindRequest 角色权限绑定/解绑请求参数
+type BindRequest struct {
+	Role        string   `json:"role" binding:"required" example:"editor"`                                 // 角色名
+	Permissions []string `json:"permissions" binding:"required,min=1,dive,required" example:"user:delete"` // 权限码列表
+}
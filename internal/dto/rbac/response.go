@@ -0,0 +1,16 @@
+package rbac
+
+// Role 角色响应参数
+type Role struct {
+	ID          uint     `json:"id" example:"1"`                    // 角色ID
+	Name        string   `json:"name" example:"admin"`              // 角色名
+	Description string   `json:"description" example:"管理员"`         // 描述
+	Permissions []string `json:"permissions" example:"user:delete"` // 已绑定的权限码
+}
+
+// Permission 权限响应参数
+type Permission struct {
+	ID          uint   `json:"id" example:"1"`             // 权限ID
+	Code        string `json:"code" example:"user:delete"` // 权限码
+	Description string `json:"description" example:"删除用户"` // 描述
+}
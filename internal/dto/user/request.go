@@ -1,13 +1,15 @@
 package user
 
 import (
+	"time"
+
 	"github.com/liuchen/gin-craft/internal/dto"
 )
 
 // RegisterRequest 用户注册请求参数
 type RegisterRequest struct {
 	Username string `json:"username" binding:"required,min=3,max=20" example:"john_doe"` // 用户名，3-20个字符
-	Password string `json:"password" binding:"required,min=6,max=20" example:"123456"`   // 密码，6-20个字符
+	Password string `json:"password" binding:"required,max=72" example:"123456"`         // 密码，长度与字符要求见密码策略
 	Email    string `json:"email" binding:"required,email" example:"john@example.com"`   // 邮箱地址
 }
 
@@ -17,27 +19,133 @@ type LoginRequest struct {
 	Password string `json:"password" binding:"required" example:"123456"`   // 密码
 }
 
+// ForgotPasswordRequest 忘记密码请求参数
+type ForgotPasswordRequest struct {
+	Email string `json:"email" binding:"required,email" example:"john@example.com"` // 注册邮箱
+}
+
+// ResetPasswordRequest 重置密码请求参数
+type ResetPasswordRequest struct {
+	Token    string `json:"token" binding:"required" example:"Jt3u...Q.x9Zk..."` // 重置邮件中的令牌
+	Password string `json:"password" binding:"required,max=72" example:"654321"` // 新密码，长度与字符要求见密码策略
+}
+
+// VerifyEmailRequest 邮箱验证请求参数
+type VerifyEmailRequest struct {
+	Token string `json:"token" binding:"required" example:"Jt3u...Q.x9Zk..."` // 验证邮件中的令牌
+}
+
+// RefreshRequest 刷新令牌请求参数
+type RefreshRequest struct {
+	RefreshToken string `json:"refresh_token" binding:"required" example:"q3Zb0cS1dPz..."` // 刷新令牌
+}
+
+// LogoutRequest 用户注销请求参数
+type LogoutRequest struct {
+	RefreshToken string `json:"refresh_token" example:"q3Zb0cS1dPz..."` // 可选，同时吊销该刷新令牌所属会话
+}
+
+// RevokeSessionsRequest 吊销用户全部会话请求参数
+type RevokeSessionsRequest struct {
+	UserID uint `json:"user_id" binding:"required" example:"1"` // 目标用户ID
+}
+
+// MFAEnrollRequest 生成两步验证密钥请求参数。
+// 修改两步验证设置都需再次校验当前密码，仅凭访问令牌不能更改；外部登录用户可先通过找回密码设置本地密码
+type MFAEnrollRequest struct {
+	Password string `json:"password" binding:"required" example:"123456"` // 当前密码
+}
+
+// MFACodeRequest 两步验证码请求参数（开启/关闭两步验证）
+type MFACodeRequest struct {
+	Password string `json:"password" binding:"required" example:"123456"` // 当前密码
+	Code     string `json:"code" binding:"required" example:"123456"`     // 验证器 App 中的 6 位验证码，关闭时也可使用恢复码
+}
+
+// MFALoginRequest 两阶段登录第二步请求参数
+type MFALoginRequest struct {
+	MFAToken string `json:"mfa_token" binding:"required" example:"Jt3u..."` // 登录第一步返回的 mfa_token
+	Code     string `json:"code" binding:"required" example:"123456"`       // 6 位验证码或恢复码
+}
+
+// OIDCLoginRequest 发起 OIDC 登录请求参数
+type OIDCLoginRequest struct {
+	Provider string `form:"provider" json:"provider" binding:"required" example:"corp"` // 登录提供方
+}
+
+// OIDCCallbackRequest OIDC 回调请求参数（由提供方重定向携带）
+type OIDCCallbackRequest struct {
+	Code    string `form:"code" json:"code" binding:"required"`   // 授权码
+	State   string `form:"state" json:"state" binding:"required"` // 授权地址中的 state，由提供方原样带回
+	Binding string `form:"-" json:"-"`                            // 发起登录时写入的 Cookie，由 controller 填充
+}
+
 // UpdateRequest 用户更新请求参数
 type UpdateRequest struct {
-	ID       uint   `json:"id" binding:"omitempty"`
+	ID       uint   `json:"id" binding:"omitempty"`                                       // 用户ID，为空时更新当前用户
 	Username string `json:"username" binding:"omitempty,min=3,max=20" example:"john_doe"` // 用户名，3-20个字符
 	Email    string `json:"email" binding:"omitempty,email" example:"john@example.com"`   // 邮箱地址
 }
 
-// PasswordUpdateRequest 用户密码更新请求参数
+// PasswordUpdateRequest 修改当前用户密码请求参数
 type PasswordUpdateRequest struct {
-	ID       uint   `json:"id" binding:"omitempty"`
-	Password string `json:"password" binding:"required,min=6,max=20" example:"newpassword123"` // 新密码，6-20个字符
+	OldPassword string `json:"old_password" binding:"required" example:"123456"`            // 当前密码
+	Password    string `json:"password" binding:"required,max=72" example:"newpassword123"` // 新密码，长度与字符要求见密码策略
 }
 
 // ListRequest 用户列表请求参数
 type ListRequest struct {
 	dto.Pagination
+	dto.Query
+	Keyword  string `form:"keyword" json:"keyword" example:"john"`   // 用户名或邮箱全文检索（前缀/子串匹配），未指定 sort 时按相关度排序
 	Username string `form:"username" json:"username" example:"john"` // 用户名筛选
 	Email    string `form:"email" json:"email" example:"john@"`      // 邮箱筛选
 }
 
+// ExportRequest 导出用户请求参数，筛选条件与用户列表相同，忽略分页与排序，按 id 升序导出全部结果
+type ExportRequest struct {
+	ListRequest
+	Format string `form:"format" json:"format" binding:"omitempty,oneof=csv jsonl" example:"csv"` // 导出格式，默认 csv
+}
+
+// CursorListRequest 用户列表游标分页请求参数
+type CursorListRequest struct {
+	dto.CursorPagination
+	Username string `form:"username" json:"username" example:"john"`                                                        // 用户名筛选
+	Email    string `form:"email" json:"email" example:"john@"`                                                             // 邮箱筛选
+	Sort     string `form:"sort" json:"sort" binding:"omitempty,oneof=id -id created_at -created_at" example:"-created_at"` // 排序，默认 -id；翻页时须与游标签发时一致
+}
+
 // InfoRequest 获取用户信息请求参数
 type InfoRequest struct {
 	ID uint `json:"id" binding:"required"`
 }
+
+// AdminListRequest 管理后台用户列表请求参数
+type AdminListRequest struct {
+	dto.Pagination
+	Keyword     string     `form:"keyword" json:"keyword" example:"john"`                                                            // 用户名或邮箱模糊匹配
+	Role        string     `form:"role" json:"role" example:"user"`                                                                  // 角色筛选
+	Status      string     `form:"status" json:"status" binding:"omitempty,oneof=pending active suspended deleted" example:"active"` // 状态筛选，deleted 时自动包含已删除用户
+	Trashed     string     `form:"trashed" json:"trashed" binding:"omitempty,oneof=with only" example:"with"`                        // with 包含已删除用户，only 仅已删除用户
+	CreatedFrom *time.Time `form:"created_from" json:"created_from" time_format:"2006-01-02" example:"2024-01-01"`                   // 注册时间起（含）
+	CreatedTo   *time.Time `form:"created_to" json:"created_to" time_format:"2006-01-02" example:"2024-12-31"`                       // 注册时间止（含当天）
+	Sort        string     `form:"sort" json:"sort" example:"-created_at,username"`                                                  // 排序字段，逗号分隔，- 前缀表示倒序；可选 id/username/email/role/status/created_at/updated_at
+}
+
+// AdminIDRequest 管理后台按ID操作用户请求参数（详情、状态变更记录、彻底删除）
+type AdminIDRequest struct {
+	ID uint `form:"id" json:"id" binding:"required" example:"1"` // 用户ID
+}
+
+// AdminStatusRequest 变更账号状态请求参数（停用、激活、恢复）
+type AdminStatusRequest struct {
+	ID     uint   `json:"id" binding:"required" example:"1"`                 // 用户ID
+	Reason string `json:"reason" binding:"omitempty,max=255" example:"违规发帖"` // 变更原因，记入状态变更记录与审计
+}
+
+// AdminAssignRoleRequest 分配角色请求参数
+type AdminAssignRoleRequest struct {
+	ID   uint   `json:"id" binding:"required" example:"1"`              // 用户ID
+	Role string `json:"role" binding:"required,max=50" example:"admin"` // 角色名，须已存在
+}
@@ -7,20 +7,91 @@ import (
 
 // User 用户响应参数
 type User struct {
-	ID        uint      `json:"id" example:"1"`                            // 用户ID
-	Username  string    `json:"username" example:"john_doe"`               // 用户名
-	Email     string    `json:"email" example:"john@example.com"`          // 邮箱地址
-	CreatedAt time.Time `json:"created_at" example:"2024-01-01T00:00:00Z"` // 创建时间
-	UpdatedAt time.Time `json:"updated_at" example:"2024-01-01T00:00:00Z"` // 更新时间
+	ID            uint      `json:"id" example:"1"`                            // 用户ID
+	Username      string    `json:"username" example:"john_doe"`               // 用户名
+	Email         string    `json:"email" example:"john@example.com"`          // 邮箱地址
+	EmailVerified bool      `json:"email_verified" example:"true"`             // 邮箱是否已验证
+	CreatedAt     time.Time `json:"created_at" example:"2024-01-01T00:00:00Z"` // 创建时间
+	UpdatedAt     time.Time `json:"updated_at" example:"2024-01-01T00:00:00Z"` // 更新时间
 }
 
-// LoginResponse 用户登录响应参数
+// LoginResponse 用户登录/刷新令牌响应参数
 type LoginResponse struct {
-	Token string `json:"token" example:"eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9..."` // 访问令牌
+	Token            string    `json:"token" example:"eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9..."` // 访问令牌
+	TokenType        string    `json:"token_type" example:"Bearer"`                             // 令牌类型
+	ExpiresAt        time.Time `json:"expires_at" example:"2024-01-01T00:15:00Z"`               // 访问令牌过期时间
+	RefreshToken     string    `json:"refresh_token" example:"q3Zb0cS1dPz..."`                  // 刷新令牌（不透明字符串，每次使用后轮换）
+	RefreshExpiresAt time.Time `json:"refresh_expires_at" example:"2024-01-31T00:00:00Z"`       // 刷新令牌过期时间
+	MFARequired      bool      `json:"mfa_required,omitempty" example:"false"`                  // 为 true 时需携带 mfa_token 与验证码调用 /user/login/mfa
+	MFAToken         string    `json:"mfa_token,omitempty" example:"Jt3u..."`                   // 两步验证待完成令牌，有效期见 expires_at
+}
+
+// MFAEnrollResponse 发起两步验证绑定响应参数
+type MFAEnrollResponse struct {
+	Secret string `json:"secret" example:"JBSWY3DPEHPK3PXP"`                                      // Base32 密钥，无法扫码时手动输入
+	URI    string `json:"uri" example:"otpauth://totp/GinCraft:john_doe?secret=JBSWY3DPEHPK3PXP"` // 生成二维码用
+}
+
+// MFARecoveryCodesResponse 恢复码响应参数
+type MFARecoveryCodesResponse struct {
+	RecoveryCodes []string `json:"recovery_codes" example:"abcde-fghij"` // 一次性恢复码，仅展示这一次
+}
+
+// OIDCLoginResponse 发起 OIDC 登录响应参数
+type OIDCLoginResponse struct {
+	AuthURL string `json:"auth_url" example:"https://sso.example.com/authorize?..."` // 前端跳转到该地址完成登录
+	Binding string `json:"-"`                                                        // 浏览器绑定值，由 controller 写入 Cookie
 }
 
 // ListResponse 用户列表响应参数
 type ListResponse struct {
+	List interface{} `json:"list" swaggertype:"array,object"` // []User；指定 fields 时每项只包含所选字段
+	dto.Pagination
+}
+
+// CursorListResponse 用户列表游标分页响应参数
+type CursorListResponse struct {
 	List []User `json:"list"`
+	dto.CursorPagination
+}
+
+// AdminUser 管理后台用户响应参数，包含角色、状态与删除时间
+type AdminUser struct {
+	User
+	Role            string     `json:"role" example:"user"`                              // 角色名
+	Status          string     `json:"status" example:"active"`                          // 账号状态：pending/active/suspended/deleted
+	StatusReason    string     `json:"status_reason" example:"违规发帖"`                     // 最近一次状态变更的原因
+	StatusChangedAt *time.Time `json:"status_changed_at" example:"2024-06-01T00:00:00Z"` // 最近一次状态变更时间
+	DeletedAt       *time.Time `json:"deleted_at" example:"2024-06-01T00:00:00Z"`        // 删除时间，为空表示未删除
+}
+
+// StatusLog 账号状态变更记录响应参数
+type StatusLog struct {
+	From      string    `json:"from" example:"active"`                     // 变更前状态
+	To        string    `json:"to" example:"suspended"`                    // 变更后状态
+	Reason    string    `json:"reason" example:"违规发帖"`                     // 变更原因
+	ActorID   string    `json:"actor_id" example:"1"`                      // 操作人ID，用户本人触发（如验证邮箱）时为空
+	CreatedAt time.Time `json:"created_at" example:"2024-06-01T00:00:00Z"` // 变更时间
+}
+
+// AdminListResponse 管理后台用户列表响应参数
+type AdminListResponse struct {
+	List []AdminUser `json:"list"`
 	dto.Pagination
 }
+
+// ImportRowError 导入失败的行
+type ImportRowError struct {
+	Line     int    `json:"line" example:"3"`            // 所在行号（CSV 含表头行，从 1 开始）
+	Username string `json:"username" example:"john_doe"` // 该行的用户名，无法解析时为空
+	Message  string `json:"message" example:"用户名已存在"`    // 失败原因
+}
+
+// ImportResponse 批量导入用户响应参数
+type ImportResponse struct {
+	Total     int              `json:"total" example:"100"`       // 读取的数据行数
+	Created   int              `json:"created" example:"98"`      // 成功创建的用户数
+	Failed    int              `json:"failed" example:"2"`        // 失败行数
+	Truncated bool             `json:"truncated" example:"false"` // 超过单次导入行数上限，其余行未处理
+	Errors    []ImportRowError `json:"errors"`                    // 逐行失败原因
+}
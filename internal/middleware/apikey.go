@@ -0,0 +1,53 @@
+package middleware
+
+import (
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/liuchen/gin-craft/internal/constant"
+	appctx "github.com/liuchen/gin-craft/internal/pkg/context"
+	"github.com/liuchen/gin-craft/internal/pkg/response"
+	"github.com/liuchen/gin-craft/internal/service"
+)
+
+// APIKeyHeader 携带 API 密钥的请求头
+const APIKeyHeader = "X-API-Key"
+
+// ValidateAPIKeyMiddleware API 密钥校验：按公开 ID 查找记录并以常量时间比对摘要，
+// 拒绝已吊销或已过期的密钥；通过后将密钥身份写入 appctx.Context，供日志与限流归因。
+// 传入 scopes 时要求密钥同时拥有全部授权范围。
+func ValidateAPIKeyMiddleware(scopes ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		raw := c.GetHeader(APIKeyHeader)
+		if raw == "" {
+			unauthorized(c, "缺少API密钥")
+			return
+		}
+		key, err := service.APIKeyService.Authenticate(c.Request.Context(), raw)
+		if err != nil {
+			response.Error(c, err)
+			c.Abort()
+			return
+		}
+		appctx.MustGetContext(c).SetAPIKey(key.KeyID, strconv.FormatUint(uint64(key.OwnerID), 10), key.ScopeList())
+		if !service.APIKeyService.HasScopes(key, scopes...) {
+			abort(c, constant.Forbidden, "API密钥缺少授权范围")
+			return
+		}
+		c.Next()
+	}
+}
+
+// RequireAPIKeyScope 路由级授权范围校验（必须在 ValidateAPIKeyMiddleware 或 SignatureMiddleware 之后）
+func RequireAPIKeyScope(scopes ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		granted := appctx.MustGetContext(c).GetAPIKeyScopes()
+		for _, scope := range scopes {
+			if !service.MatchPermission(granted, scope) {
+				abort(c, constant.Forbidden, "API密钥缺少授权范围 "+scope)
+				return
+			}
+		}
+		c.Next()
+	}
+}
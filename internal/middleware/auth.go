@@ -1,6 +1,7 @@
 package middleware
 
 import (
+	stderrors "errors"
 	"strings"
 
 	"github.com/gin-gonic/gin"
@@ -8,7 +9,10 @@ import (
 	appctx "github.com/liuchen/gin-craft/internal/pkg/context"
 	"github.com/liuchen/gin-craft/internal/pkg/errors"
 	"github.com/liuchen/gin-craft/internal/pkg/response"
-	"github.com/liuchen/gin-craft/internal/pkg/config"
+	"github.com/liuchen/gin-craft/internal/pkg/token"
+	"github.com/liuchen/gin-craft/internal/service"
+	pkgtoken "github.com/liuchen/gin-craft/pkg/token"
+	"go.uber.org/zap"
 )
 
 const (
@@ -24,58 +28,59 @@ func AuthMiddleware() gin.HandlerFunc {
 			unauthorized(c, "认证令牌无效")
 			return
 		}
-		token := strings.TrimPrefix(authHeader, "Bearer ")
-		if token == "" {
+		raw := strings.TrimPrefix(authHeader, "Bearer ")
+		if raw == "" {
 			unauthorized(c, "认证令牌为空")
 			return
 		}
 
-		// TODO: 接入真实 JWT 后，从 claims 中解析用户信息
-		userID, username, role := "123", "user_123", "user"
+		claims, err := token.GetManager().Parse(raw)
+		if err != nil {
+			if stderrors.Is(err, pkgtoken.ErrTokenExpired) {
+				abort(c, constant.TokenExpired)
+				return
+			}
+			abort(c, constant.TokenInvalid)
+			return
+		}
 
 		appCtx := appctx.MustGetContext(c)
-		appCtx.SetUser(userID, username, role)
-		appCtx.SetCustomField(ctxHasTokenKey, true) // 仅标记，不写 token 原文
-		c.Next()
-	}
-}
-
-// AdminAuthMiddleware 管理员认证中间件（必须在 AuthMiddleware 之后）
-func AdminAuthMiddleware() gin.HandlerFunc {
-	return func(c *gin.Context) {
-		appCtx := appctx.MustGetContext(c)
-		if appCtx.GetUserRole() != "admin" {
-			response.Error(c, errors.New(constant.Forbidden, "需要管理员权限"))
+		revoked, err := token.GetDenylist().IsRevoked(c.Request.Context(), claims)
+		if err != nil {
+			appCtx.LogError("查询令牌吊销名单失败", zap.Error(err))
+			abort(c, constant.SystemError)
+			return
+		}
+		if revoked {
+			abort(c, constant.TokenInvalid, "令牌已注销")
+			return
+		}
+		// 停用、删除的账号即使令牌未过期也拒绝
+		if err := service.UserService.CheckStatus(c.Request.Context(), claims.Subject); err != nil {
+			response.Error(c, err)
 			c.Abort()
 			return
 		}
-		c.Next()
-	}
-}
 
-// RateLimitMiddleware 限流中间件占位实现
-func RateLimitMiddleware() gin.HandlerFunc {
-	return func(c *gin.Context) {
-		// TODO: 接入真实的基于 token/ip 的限流
+		appCtx.SetUser(claims.Subject, claims.Username, claims.Role)
+		appCtx.SetToken(claims.ID, claims.ExpiresAt.Time)
+		appCtx.SetCustomField(ctxHasTokenKey, true) // 仅标记，不写 token 原文
 		c.Next()
 	}
 }
 
-// ValidateAPIKeyMiddleware API 密钥校验，密钥从 config.app.api_key 读取
-func ValidateAPIKeyMiddleware() gin.HandlerFunc {
-	return func(c *gin.Context) {
-		expected := config.Config.App.APIKey
-		apiKey := c.GetHeader("X-API-Key")
-		if expected == "" || apiKey == "" || apiKey != expected {
-			response.Error(c, errors.New(constant.Unauthorized, "无效的API密钥"))
-			c.Abort()
-			return
-		}
-		c.Next()
-	}
+// AdminAuthMiddleware 管理员认证中间件（必须在 AuthMiddleware 之后），
+// 等价于 RequirePermission(constant.PermAdminAccess)
+func AdminAuthMiddleware() gin.HandlerFunc {
+	return RequirePermission(constant.PermAdminAccess)
 }
 
 func unauthorized(c *gin.Context, msg string) {
 	response.Error(c, errors.New(constant.Unauthorized, msg))
 	c.Abort()
 }
+
+func abort(c *gin.Context, code int, detail ...string) {
+	response.Error(c, errors.New(code, detail...))
+	c.Abort()
+}
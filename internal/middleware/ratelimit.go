@@ -0,0 +1,123 @@
+package middleware
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/liuchen/gin-craft/internal/constant"
+	appctx "github.com/liuchen/gin-craft/internal/pkg/context"
+	"github.com/liuchen/gin-craft/internal/pkg/ratelimit"
+	pkgratelimit "github.com/liuchen/gin-craft/pkg/ratelimit"
+	"go.uber.org/zap"
+)
+
+// RateLimitKeyFunc 提取限流维度；返回空串表示该请求不参与限流
+type RateLimitKeyFunc func(c *gin.Context) string
+
+// RateLimitByUser 按登录用户限流（须在 AuthMiddleware 之后），未登录时退化为按 IP
+func RateLimitByUser(c *gin.Context) string {
+	if uid := appctx.MustGetContext(c).GetUserID(); uid != "" {
+		return "user:" + uid
+	}
+	return RateLimitByIP(c)
+}
+
+// RateLimitByIP 按客户端 IP 限流
+func RateLimitByIP(c *gin.Context) string {
+	return "ip:" + c.ClientIP()
+}
+
+// RateLimitByAPIKey 按 API Key 限流：已通过 ValidateAPIKeyMiddleware 或 SignatureMiddleware 时使用密钥公开 ID，
+// 否则使用 X-API-Key 请求头摘要（不存明文），未携带时退化为按 IP。
+// 未经校验的 X-Key-ID 不作为维度，避免伪造他人的密钥 ID 耗尽其配额
+func RateLimitByAPIKey(c *gin.Context) string {
+	if id := appctx.MustGetContext(c).GetAPIKeyID(); id != "" {
+		return "apikey:" + id
+	}
+	key := c.GetHeader(APIKeyHeader)
+	if key == "" {
+		return RateLimitByIP(c)
+	}
+	sum := sha256.Sum256([]byte(key))
+	return "apikey:" + hex.EncodeToString(sum[:8])
+}
+
+// RateLimitByRoute 整个路由共享一份配额
+func RateLimitByRoute(_ *gin.Context) string {
+	return "route"
+}
+
+// RateLimitMiddleware 限流中间件：同一路由下按 keyFunc 维度计数，超限返回 TooManyRequests。
+// 响应头遵循 IETF RateLimit 头部草案，拒绝时附带 Retry-After。
+func RateLimitMiddleware(limit pkgratelimit.Limit, keyFunc RateLimitKeyFunc) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := keyFunc(c)
+		if key == "" {
+			c.Next()
+			return
+		}
+		applyRateLimit(c, c.FullPath()+"|"+key, limit)
+	}
+}
+
+// rateLimitKeyFuncs 配置中的 key 类型 → 维度提取函数
+var rateLimitKeyFuncs = map[pkgratelimit.KeyType]RateLimitKeyFunc{
+	pkgratelimit.KeyByIP:     RateLimitByIP,
+	pkgratelimit.KeyByUser:   RateLimitByUser,
+	pkgratelimit.KeyByAPIKey: RateLimitByAPIKey,
+	pkgratelimit.KeyByRoute:  RateLimitByRoute,
+}
+
+// RateLimitPolicy 配置驱动的限流，作为 ElegantRouter 的路由级中间件工厂使用：
+//
+//	elegantR.UseRouteMiddleware(middleware.RateLimitPolicy)
+//
+// 每次请求读取当前策略表，配置热更新后无需重启即可生效。
+// 中间件位于路由组中间件之后，因此认证路由可以按用户与角色取配额。
+func RateLimitPolicy(method, fullPath string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		policy := ratelimit.GetPolicies().Match(method, fullPath)
+		if policy == nil {
+			c.Next()
+			return
+		}
+		key := rateLimitKeyFuncs[policy.Key](c)
+		if policy.Key == pkgratelimit.KeyByRoute {
+			key = method + " " + fullPath
+		}
+		role := appctx.MustGetContext(c).GetUserRole()
+		applyRateLimit(c, "policy:"+policy.Name+"|"+key, policy.LimitFor(role))
+	}
+}
+
+// applyRateLimit 执行一次限流判定；被拒绝时中断请求
+func applyRateLimit(c *gin.Context, key string, limit pkgratelimit.Limit) {
+	res, err := ratelimit.GetLimiter().Allow(c.Request.Context(), key, limit)
+	if err != nil {
+		// 限流后端故障时放行，避免 Redis 抖动拖垮业务
+		appctx.MustGetContext(c).LogWarn("限流判定失败，放行请求", zap.Error(err))
+		c.Next()
+		return
+	}
+
+	c.Header("RateLimit-Limit", strconv.Itoa(res.Limit))
+	c.Header("RateLimit-Remaining", strconv.Itoa(res.Remaining))
+	c.Header("RateLimit-Reset", ceilSeconds(res.ResetAfter))
+	c.Header("RateLimit-Policy", fmt.Sprintf("%d;w=%s", res.Limit, ceilSeconds(limit.Period)))
+	if !res.Allowed {
+		c.Header("Retry-After", ceilSeconds(res.RetryAfter))
+		abort(c, constant.TooManyRequests)
+		return
+	}
+	c.Next()
+}
+
+// ceilSeconds 向上取整到秒，避免客户端按 0 秒立即重试
+func ceilSeconds(d time.Duration) string {
+	return strconv.Itoa(int(math.Ceil(d.Seconds())))
+}
@@ -0,0 +1,34 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/liuchen/gin-craft/internal/constant"
+	appctx "github.com/liuchen/gin-craft/internal/pkg/context"
+	"github.com/liuchen/gin-craft/internal/service"
+	"go.uber.org/zap"
+)
+
+// RequirePermission 权限校验中间件（必须在 AuthMiddleware 之后），需同时拥有全部 permissions：
+//
+//	admin.POST("/users/delete", er.WrapRequestHandler(ctrl.Delete), middleware.RequirePermission("user:delete"))
+//
+// 角色 → 权限的解析结果缓存在 Redis，授权变更后即时失效。
+func RequirePermission(permissions ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		appCtx := appctx.MustGetContext(c)
+		role := appCtx.GetUserRole()
+		for _, p := range permissions {
+			ok, err := service.RBACService.HasPermission(c.Request.Context(), role, p)
+			if err != nil {
+				appCtx.LogError("权限解析失败", zap.String("permission", p), zap.Error(err))
+				abort(c, constant.SystemError)
+				return
+			}
+			if !ok {
+				abort(c, constant.Forbidden, "缺少权限 "+p)
+				return
+			}
+		}
+		c.Next()
+	}
+}
@@ -0,0 +1,41 @@
+package model
+
+import (
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// APIKey 服务端 API 密钥，只保存摘要，明文仅在创建时返回一次
+type APIKey struct {
+	ID         uint           `gorm:"primarykey" json:"id"`
+	KeyID      string         `gorm:"type:varchar(32);not null;uniqueIndex" json:"key_id"` // 密钥中的公开 ID
+	KeyHash    string         `gorm:"type:varchar(64);not null" json:"-"`                  // SHA-256 摘要
+	Name       string         `gorm:"type:varchar(50);not null" json:"name"`
+	OwnerID    uint           `gorm:"not null;index" json:"owner_id"`
+	Scopes     string         `gorm:"type:varchar(500);not null;default:''" json:"scopes"` // 逗号分隔，如 "data:read,data:write"
+	ExpiresAt  *time.Time     `json:"expires_at"`                                          // 为空表示永不过期
+	LastUsedAt *time.Time     `json:"last_used_at"`
+	CreatedAt  time.Time      `json:"created_at"`
+	UpdatedAt  time.Time      `json:"updated_at"`
+	DeletedAt  gorm.DeletedAt `gorm:"index" json:"-"` // 吊销即软删除
+}
+
+// TableName 表名
+func (APIKey) TableName() string {
+	return "api_keys"
+}
+
+// ScopeList 授权范围列表
+func (k *APIKey) ScopeList() []string {
+	if k.Scopes == "" {
+		return []string{}
+	}
+	return strings.Split(k.Scopes, ",")
+}
+
+// Expired 判断密钥在 now 时是否已过期
+func (k *APIKey) Expired(now time.Time) bool {
+	return k.ExpiresAt != nil && !now.Before(*k.ExpiresAt)
+}
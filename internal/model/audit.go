@@ -0,0 +1,22 @@
+package model
+
+import "time"
+
+// AuditLog 审计日志，只追加不修改
+type AuditLog struct {
+	ID        uint      `gorm:"primarykey" json:"id"`
+	ActorID   string    `gorm:"type:varchar(64);not null;default:'';index" json:"actor_id"` // 操作人ID，匿名操作为空
+	ActorName string    `gorm:"type:varchar(50);not null;default:''" json:"actor_name"`
+	Action    string    `gorm:"type:varchar(50);not null;index" json:"action"` // 如 user.disable
+	Target    string    `gorm:"type:varchar(100);not null;default:'';index" json:"target"`
+	Changes   string    `gorm:"type:text" json:"changes"` // 变更字段 JSON：{"字段":{"before":..,"after":..}}
+	TraceID   string    `gorm:"type:varchar(64);not null;default:'';index" json:"trace_id"`
+	IP        string    `gorm:"type:varchar(45);not null;default:''" json:"ip"`
+	UserAgent string    `gorm:"type:varchar(255);not null;default:''" json:"user_agent"`
+	CreatedAt time.Time `gorm:"index" json:"created_at"`
+}
+
+// TableName 表名
+func (AuditLog) TableName() string {
+	return "audit_logs"
+}
@@ -0,0 +1,14 @@
+package model
+
+import "time"
+
+// UserIdentity 外部身份（OIDC 提供方 + subject）与本地用户的绑定
+type UserIdentity struct {
+	ID        uint      `gorm:"primarykey" json:"id"`
+	UserID    uint      `gorm:"not null;index" json:"user_id"`
+	Provider  string    `gorm:"type:varchar(50);not null;uniqueIndex:idx_provider_subject" json:"provider"`
+	Subject   string    `gorm:"type:varchar(255);not null;uniqueIndex:idx_provider_subject" json:"subject"`
+	Email     string    `gorm:"type:varchar(100);not null;default:''" json:"email"` // 绑定时提供方返回的邮箱
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
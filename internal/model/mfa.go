@@ -0,0 +1,31 @@
+package model
+
+import "time"
+
+// UserMFA 用户 TOTP 两步验证设置；Enabled 为 false 表示已发起绑定但尚未验证
+type UserMFA struct {
+	ID           uint       `gorm:"primarykey" json:"id"`
+	UserID       uint       `gorm:"not null;uniqueIndex" json:"user_id"`
+	Secret       string     `gorm:"type:varchar(64);not null" json:"-"`
+	Enabled      bool       `gorm:"not null;default:false" json:"enabled"`
+	LastUsedStep int64      `gorm:"not null;default:0" json:"-"` // 最近一次通过校验的时间步，防止验证码重放
+	EnabledAt    *time.Time `json:"enabled_at"`
+	CreatedAt    time.Time  `json:"created_at"`
+	UpdatedAt    time.Time  `json:"updated_at"`
+}
+
+// TableName 表名
+func (UserMFA) TableName() string {
+	return "user_mfa"
+}
+
+// MFARecoveryCode 两步验证恢复码，只保存散列，每个只能使用一次；
+// Lookup 为恢复码第一段，明文保存用于定位记录，校验时只需比对一个散列
+type MFARecoveryCode struct {
+	ID        uint       `gorm:"primarykey" json:"id"`
+	UserID    uint       `gorm:"not null;index:idx_recovery_user_lookup" json:"user_id"`
+	Lookup    string     `gorm:"type:varchar(16);not null;default:'';index:idx_recovery_user_lookup" json:"-"`
+	CodeHash  string     `gorm:"type:varchar(255);not null" json:"-"`
+	UsedAt    *time.Time `json:"used_at"`
+	CreatedAt time.Time  `json:"created_at"`
+}
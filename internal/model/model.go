@@ -0,0 +1,17 @@
+package model
+
+// Models 需要自动迁移的全部模型
+func Models() []interface{} {
+	return []interface{}{
+		&User{},
+		&Role{},
+		&Permission{},
+		&RolePermission{},
+		&APIKey{},
+		&UserIdentity{},
+		&UserMFA{},
+		&MFARecoveryCode{},
+		&UserStatusLog{},
+		&AuditLog{},
+	}
+}
@@ -0,0 +1,28 @@
+package model
+
+import "time"
+
+// Role 角色
+type Role struct {
+	ID          uint      `gorm:"primarykey" json:"id"`
+	Name        string    `gorm:"type:varchar(50);not null;uniqueIndex" json:"name"`
+	Description string    `gorm:"type:varchar(255);not null;default:''" json:"description"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// Permission 权限，Code 形如 "user:delete"；"*" 与 "user:*" 为通配
+type Permission struct {
+	ID          uint      `gorm:"primarykey" json:"id"`
+	Code        string    `gorm:"type:varchar(100);not null;uniqueIndex" json:"code"`
+	Description string    `gorm:"type:varchar(255);not null;default:''" json:"description"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// RolePermission 角色与权限的绑定关系
+type RolePermission struct {
+	RoleID       uint      `gorm:"primaryKey" json:"role_id"`
+	PermissionID uint      `gorm:"primaryKey;index" json:"permission_id"`
+	CreatedAt    time.Time `json:"created_at"`
+}
@@ -3,16 +3,51 @@ package model
 import (
 	"time"
 
+	"github.com/liuchen/gin-craft/internal/constant"
 	"gorm.io/gorm"
 )
 
 // User 用户模型
 type User struct {
-	ID        uint           `gorm:"primarykey" json:"id"`
-	Username  string         `gorm:"type:varchar(20);not null;uniqueIndex" json:"username"`
-	Password  string         `gorm:"type:varchar(100);not null" json:"-"` // 不返回密码
-	Email     string         `gorm:"type:varchar(50);not null;uniqueIndex" json:"email"`
-	CreatedAt time.Time      `json:"created_at"`
-	UpdatedAt time.Time      `json:"updated_at"`
-	DeletedAt gorm.DeletedAt `gorm:"index" json:"-"`
+	ID              uint           `gorm:"primarykey" json:"id"`
+	Username        string         `gorm:"type:varchar(20);not null;uniqueIndex" json:"username"`
+	Password        string         `gorm:"type:varchar(255);not null" json:"-"` // 口令散列（PHC 格式或 bcrypt），不返回
+	Email           string         `gorm:"type:varchar(50);not null;uniqueIndex" json:"email"`
+	Role            string         `gorm:"type:varchar(50);not null;default:'user';index" json:"role"`     // 角色名，对应 Role.Name
+	Status          string         `gorm:"type:varchar(20);not null;default:'active';index" json:"status"` // 账号状态，见 constant.UserStatus*
+	StatusReason    string         `gorm:"type:varchar(255);not null;default:''" json:"status_reason"`     // 最近一次状态变更的原因
+	StatusChangedAt *time.Time     `json:"status_changed_at"`                                              // 最近一次状态变更时间
+	EmailVerifiedAt *time.Time     `json:"email_verified_at"`                                              // 邮箱验证时间，为空表示未验证；修改邮箱后清空
+	CreatedAt       time.Time      `json:"created_at"`
+	UpdatedAt       time.Time      `json:"updated_at"`
+	DeletedAt       gorm.DeletedAt `gorm:"index" json:"-"`
+}
+
+// userStatusTransitions 账号状态机：当前状态 → 允许迁移到的状态
+var userStatusTransitions = map[string][]string{
+	constant.UserStatusPending:   {constant.UserStatusActive, constant.UserStatusSuspended, constant.UserStatusDeleted},
+	constant.UserStatusActive:    {constant.UserStatusSuspended, constant.UserStatusDeleted},
+	constant.UserStatusSuspended: {constant.UserStatusActive, constant.UserStatusDeleted},
+	constant.UserStatusDeleted:   {constant.UserStatusActive},
+}
+
+// CanTransitionTo 判断账号能否从当前状态迁移到 status
+func (u *User) CanTransitionTo(status string) bool {
+	for _, s := range userStatusTransitions[u.Status] {
+		if s == status {
+			return true
+		}
+	}
+	return false
+}
+
+// UserStatusLog 账号状态变更记录，每次迁移一条
+type UserStatusLog struct {
+	ID        uint      `gorm:"primarykey" json:"id"`
+	UserID    uint      `gorm:"not null;index" json:"user_id"`
+	From      string    `gorm:"type:varchar(20);not null" json:"from"`
+	To        string    `gorm:"type:varchar(20);not null" json:"to"`
+	Reason    string    `gorm:"type:varchar(255);not null;default:''" json:"reason"`
+	ActorID   string    `gorm:"type:varchar(64);not null;default:''" json:"actor_id"` // 操作人ID，用户自己触发（如验证邮箱）时为空或为本人
+	CreatedAt time.Time `json:"created_at"`
 }
@@ -0,0 +1,77 @@
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/liuchen/gin-craft/internal/dao"
+	"github.com/liuchen/gin-craft/internal/model"
+	"github.com/liuchen/gin-craft/internal/pkg/config"
+	pkgaudit "github.com/liuchen/gin-craft/pkg/audit"
+	"github.com/liuchen/gin-craft/pkg/logger"
+	"go.uber.org/zap"
+)
+
+var (
+	recorder *pkgaudit.Recorder
+	once     sync.Once
+)
+
+// InitAudit 根据配置启动审计日志异步写入（须在 InitDatabase 之后调用）
+func InitAudit() {
+	once.Do(func() {
+		cfg := config.Config.Audit
+		recorder = pkgaudit.NewRecorder(pkgaudit.SinkFunc(writeDB), pkgaudit.Options{
+			BufferSize:    cfg.BufferSize,
+			BatchSize:     cfg.BatchSize,
+			FlushInterval: time.Duration(cfg.FlushInterval) * time.Millisecond,
+			OnError: func(err error, n int) {
+				logger.Error("Failed to write audit logs", zap.Int("count", n), zap.Error(err))
+			},
+		})
+	})
+}
+
+// GetRecorder 审计记录器；未初始化时返回 nil
+func GetRecorder() *pkgaudit.Recorder {
+	return recorder
+}
+
+// Close 写完队列中剩余的审计事件
+func Close(ctx context.Context) error {
+	if recorder == nil {
+		return nil
+	}
+	return recorder.Close(ctx)
+}
+
+func writeDB(ctx context.Context, entries []pkgaudit.Entry) error {
+	logs := make([]model.AuditLog, 0, len(entries))
+	for _, e := range entries {
+		changes, err := json.Marshal(e.Changes)
+		if err != nil {
+			return err
+		}
+		logs = append(logs, model.AuditLog{
+			ActorID:   e.ActorID,
+			ActorName: e.ActorName,
+			Action:    e.Action,
+			Target:    e.Target,
+			Changes:   string(changes),
+			TraceID:   e.TraceID,
+			IP:        e.IP,
+			UserAgent: truncate(e.UserAgent, 255),
+			CreatedAt: e.CreatedAt,
+		})
+	}
+	return dao.GetAuditDAO().BatchCreate(ctx, logs)
+}
+
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	return s[:n]
+}
@@ -1,11 +1,15 @@
 package config
 
 import (
+	"crypto/hmac"
+	"crypto/sha256"
 	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
+	"sync"
 
+	"github.com/fsnotify/fsnotify"
 	"github.com/spf13/viper"
 )
 
@@ -20,7 +24,6 @@ type AppConfig struct {
 		Port         int    `mapstructure:"port"`
 		ReadTimeout  int    `mapstructure:"read_timeout"`
 		WriteTimeout int    `mapstructure:"write_timeout"`
-		APIKey       string `mapstructure:"api_key"`
 	} `mapstructure:"app"`
 
 	Log struct {
@@ -41,6 +44,7 @@ type AppConfig struct {
 		MaxIdleConns    int    `mapstructure:"max_idle_conns"`
 		MaxOpenConns    int    `mapstructure:"max_open_conns"`
 		ConnMaxLifetime int    `mapstructure:"conn_max_lifetime"`
+		AutoMigrate     bool   `mapstructure:"auto_migrate"` // 启动时自动迁移表结构并写入内置角色
 	} `mapstructure:"mysql"`
 
 	Redis struct {
@@ -56,6 +60,107 @@ type AppConfig struct {
 		WriteTimeout int    `mapstructure:"write_timeout"`
 	} `mapstructure:"redis"`
 
+	JWT struct {
+		Algorithm      string `mapstructure:"algorithm"` // HS256 / RS256 / EdDSA
+		Secret         string `mapstructure:"secret"`
+		PrivateKeyFile string `mapstructure:"private_key_file"`
+		PublicKeyFile  string `mapstructure:"public_key_file"`
+		Issuer         string `mapstructure:"issuer"`
+		AccessTTL      int    `mapstructure:"access_ttl"`  // seconds
+		RefreshTTL     int    `mapstructure:"refresh_ttl"` // seconds
+	} `mapstructure:"jwt"`
+
+	RateLimit struct {
+		Backend   string            `mapstructure:"backend"`   // memory / redis
+		Algorithm string            `mapstructure:"algorithm"` // token_bucket / sliding_window / gcra
+		KeyPrefix string            `mapstructure:"key_prefix"`
+		Policies  []RateLimitPolicy `mapstructure:"policies"` // 支持热更新
+	} `mapstructure:"rate_limit"`
+
+	Lockout struct {
+		Window  int           `mapstructure:"window"` // seconds，失败计数保留时长，每次失败后重新计时
+		Account LockoutPolicy `mapstructure:"account"`
+		IP      LockoutPolicy `mapstructure:"ip"`
+	} `mapstructure:"lockout"`
+
+	Account struct {
+		TokenSecret string `mapstructure:"token_secret"` // 一次性令牌签名密钥，为空时由 jwt.secret 派生
+		ResetTTL    int    `mapstructure:"reset_ttl"`    // seconds，重置密码链接有效期
+		VerifyTTL   int    `mapstructure:"verify_ttl"`   // seconds，邮箱验证链接有效期
+		ResetURL    string `mapstructure:"reset_url"`    // 前端重置密码页面，令牌以 ?token= 追加
+		VerifyURL   string `mapstructure:"verify_url"`   // 前端邮箱验证页面
+
+		RequireVerification bool `mapstructure:"require_verification"` // 注册后处于 pending 状态，验证邮箱后才能登录
+		StatusCacheTTL      int  `mapstructure:"status_cache_ttl"`     // seconds，认证中间件缓存账号状态的时长
+	} `mapstructure:"account"`
+
+	Password struct {
+		MinLength     int    `mapstructure:"min_length"`
+		MaxLength     int    `mapstructure:"max_length"` // 字节数，bcrypt 上限 72
+		RequireUpper  bool   `mapstructure:"require_upper"`
+		RequireLower  bool   `mapstructure:"require_lower"`
+		RequireDigit  bool   `mapstructure:"require_digit"`
+		RequireSymbol bool   `mapstructure:"require_symbol"`
+		BreachedFile  string `mapstructure:"breached_file"` // 泄露密码列表，每行一条明文或 SHA-1 摘要
+		Hasher        struct {
+			Algorithm  string `mapstructure:"algorithm"` // argon2id / bcrypt，新密码使用该算法，旧算法的散列在登录成功后自动升级
+			BcryptCost int    `mapstructure:"bcrypt_cost"`
+			Argon2     struct {
+				Memory      uint32 `mapstructure:"memory"` // KiB
+				Iterations  uint32 `mapstructure:"iterations"`
+				Parallelism uint8  `mapstructure:"parallelism"`
+				SaltLength  uint32 `mapstructure:"salt_length"`
+				KeyLength   uint32 `mapstructure:"key_length"`
+			} `mapstructure:"argon2"`
+		} `mapstructure:"hasher"`
+	} `mapstructure:"password"`
+
+	Pagination struct {
+		CursorSecret string `mapstructure:"cursor_secret"` // 游标分页的签名密钥，为空时由 jwt.secret 派生
+	} `mapstructure:"pagination"`
+
+	Search struct {
+		Driver  string `mapstructure:"driver"`   // mysql（默认）/ memory（进程内索引，仅限本地开发与单实例）
+		MaxHits int    `mapstructure:"max_hits"` // 单次检索最多取回的命中数，超出部分不参与筛选与分页
+	} `mapstructure:"search"`
+
+	Bulk struct {
+		BatchSize int `mapstructure:"batch_size"` // 导入时每个事务写入的行数；导出时每次读取的行数
+		MaxRows   int `mapstructure:"max_rows"`   // 单次导入的最大数据行数
+	} `mapstructure:"bulk"`
+
+	Audit struct {
+		BufferSize    int `mapstructure:"buffer_size"`    // 异步写入队列长度，队列满时丢弃事件
+		BatchSize     int `mapstructure:"batch_size"`     // 单次批量写入条数
+		FlushInterval int `mapstructure:"flush_interval"` // milliseconds，未攒满一批时的最长等待时间
+	} `mapstructure:"audit"`
+
+	Mail struct {
+		Driver string `mapstructure:"driver"` // log / file
+		From   string `mapstructure:"from"`
+		Dir    string `mapstructure:"dir"` // file 驱动保存 .eml 的目录
+	} `mapstructure:"mail"`
+
+	MFA struct {
+		Issuer     string `mapstructure:"issuer"`      // 验证器 App 中显示的签发方
+		PendingTTL int    `mapstructure:"pending_ttl"` // seconds，密码校验通过后等待输入验证码的时长
+	} `mapstructure:"mfa"`
+
+	OIDC struct {
+		StateTTL     int            `mapstructure:"state_ttl"`      // seconds，授权请求 state 的有效期
+		JWKSCacheTTL int            `mapstructure:"jwks_cache_ttl"` // seconds
+		Providers    []OIDCProvider `mapstructure:"providers"`
+	} `mapstructure:"oidc"`
+
+	Signing struct {
+		Secret  string `mapstructure:"secret"`   // 派生各 API 密钥签名密钥的服务端密钥，为空时由 jwt.secret 派生
+		MaxSkew int    `mapstructure:"max_skew"` // seconds，请求时间戳允许的偏差
+	} `mapstructure:"signing"`
+
+	RBAC struct {
+		CacheTTL int `mapstructure:"cache_ttl"` // seconds，角色权限在 Redis 中的缓存时长
+	} `mapstructure:"rbac"`
+
 	CORS struct {
 		AllowOrigins     []string `mapstructure:"allow_origins"` // 空或含 "*" 表示允许所有来源
 		AllowMethods     []string `mapstructure:"allow_methods"`
@@ -65,6 +170,95 @@ type AppConfig struct {
 	} `mapstructure:"cors"`
 }
 
+// RateLimitQuota 限流配额：period 秒内允许 rate 次，burst 为突发容量
+type RateLimitQuota struct {
+	Rate   int `mapstructure:"rate"`
+	Period int `mapstructure:"period"` // seconds
+	Burst  int `mapstructure:"burst"`
+}
+
+// RateLimitPolicy 限流策略，routes 为 ElegantRouter 注册时的完整路径
+type RateLimitPolicy struct {
+	Name   string                    `mapstructure:"name"`
+	Routes []string                  `mapstructure:"routes"` // "/api/v1/user/login"、"POST /api/v1/user/login"、"/api/v1/admin/*"
+	Key    string                    `mapstructure:"key"`    // ip / user / api_key / route
+	Rate   int                       `mapstructure:"rate"`
+	Period int                       `mapstructure:"period"` // seconds
+	Burst  int                       `mapstructure:"burst"`
+	Roles  map[string]RateLimitQuota `mapstructure:"roles"` // 按角色覆盖配额
+}
+
+// LockoutPolicy 登录失败锁定策略：连续失败 threshold 次后锁定 base_delay 秒，之后每次失败翻倍，最长 max_delay 秒
+type LockoutPolicy struct {
+	Threshold int `mapstructure:"threshold"`
+	BaseDelay int `mapstructure:"base_delay"` // seconds
+	MaxDelay  int `mapstructure:"max_delay"`  // seconds
+}
+
+// OIDCProvider 单个 OpenID Connect 登录提供方
+type OIDCProvider struct {
+	Name         string   `mapstructure:"name"` // 路由与身份绑定中使用的标识，如 corp
+	Issuer       string   `mapstructure:"issuer"`
+	ClientID     string   `mapstructure:"client_id"`
+	ClientSecret string   `mapstructure:"client_secret"`
+	RedirectURL  string   `mapstructure:"redirect_url"`
+	Scopes       []string `mapstructure:"scopes"` // 为空时使用 openid email profile
+}
+
+// 派生签名密钥的用途，每个用途得到不同的密钥
+const (
+	PurposeCursor       = "cursor"
+	PurposeOneTimeToken = "one-time-token"
+	PurposeSigning      = "api-key-signing"
+)
+
+// SecretFor 某一用途的签名密钥：explicit 为该用途单独配置的密钥，非空时直接使用；
+// 否则以 HMAC-SHA256(jwt.secret, purpose) 派生，同一个 jwt.secret 签发的 JWT 与各用途的签名互不通用。
+// 两者均为空时返回 nil，由调用方决定如何处理
+func SecretFor(explicit, purpose string) []byte {
+	if explicit != "" {
+		return []byte(explicit)
+	}
+	if Config.JWT.Secret == "" {
+		return nil
+	}
+	mac := hmac.New(sha256.New, []byte(Config.JWT.Secret))
+	mac.Write([]byte("gin-craft/" + purpose))
+	return mac.Sum(nil)
+}
+
+var (
+	watchMu  sync.Mutex
+	watchers []func(*AppConfig)
+)
+
+// OnChange 注册配置热更新回调。回调收到重新解析的完整配置，
+// 由各模块自行挑选可热更新的字段；全局 Config 不会被替换。
+func OnChange(fn func(*AppConfig)) {
+	watchMu.Lock()
+	watchers = append(watchers, fn)
+	watchMu.Unlock()
+}
+
+// Watch 开始监听配置文件变化，解析失败时调用 onError 并保留旧配置
+func Watch(onError func(error)) {
+	viper.OnConfigChange(func(fsnotify.Event) {
+		var next AppConfig
+		if err := viper.Unmarshal(&next); err != nil {
+			onError(fmt.Errorf("unmarshal config: %w", err))
+			return
+		}
+		watchMu.Lock()
+		fns := make([]func(*AppConfig), len(watchers))
+		copy(fns, watchers)
+		watchMu.Unlock()
+		for _, fn := range fns {
+			fn(&next)
+		}
+	})
+	viper.WatchConfig()
+}
+
 // LoadConfig 加载配置
 func LoadConfig(configPath string) error {
 	viper.SetConfigFile(configPath)
@@ -113,6 +307,61 @@ func setDefaults() {
 	viper.SetDefault("redis.read_timeout", 3)
 	viper.SetDefault("redis.write_timeout", 3)
 
+	viper.SetDefault("jwt.algorithm", "HS256")
+	viper.SetDefault("jwt.issuer", "GinCraft")
+	viper.SetDefault("jwt.access_ttl", 900)
+	viper.SetDefault("jwt.refresh_ttl", 2592000)
+
+	viper.SetDefault("rate_limit.backend", "memory")
+	viper.SetDefault("rate_limit.algorithm", "gcra")
+	viper.SetDefault("rate_limit.key_prefix", "ratelimit:")
+
+	viper.SetDefault("lockout.window", 900)
+	viper.SetDefault("lockout.account.threshold", 5)
+	viper.SetDefault("lockout.account.base_delay", 30)
+	viper.SetDefault("lockout.account.max_delay", 3600)
+	viper.SetDefault("lockout.ip.threshold", 20)
+	viper.SetDefault("lockout.ip.base_delay", 60)
+	viper.SetDefault("lockout.ip.max_delay", 3600)
+
+	viper.SetDefault("account.reset_ttl", 1800)
+	viper.SetDefault("account.verify_ttl", 86400)
+	viper.SetDefault("account.status_cache_ttl", 60)
+
+	viper.SetDefault("password.min_length", 6)
+	viper.SetDefault("password.max_length", 72)
+	viper.SetDefault("password.hasher.algorithm", "argon2id")
+	viper.SetDefault("password.hasher.bcrypt_cost", 10)
+	viper.SetDefault("password.hasher.argon2.memory", 65536)
+	viper.SetDefault("password.hasher.argon2.iterations", 3)
+	viper.SetDefault("password.hasher.argon2.parallelism", 2)
+	viper.SetDefault("password.hasher.argon2.salt_length", 16)
+	viper.SetDefault("password.hasher.argon2.key_length", 32)
+
+	viper.SetDefault("search.driver", "mysql")
+	viper.SetDefault("search.max_hits", 1000)
+
+	viper.SetDefault("bulk.batch_size", 500)
+	viper.SetDefault("bulk.max_rows", 10000)
+
+	viper.SetDefault("audit.buffer_size", 1024)
+	viper.SetDefault("audit.batch_size", 100)
+	viper.SetDefault("audit.flush_interval", 1000)
+
+	viper.SetDefault("mail.driver", "log")
+	viper.SetDefault("mail.from", "no-reply@gincraft.local")
+	viper.SetDefault("mail.dir", "storage/mail")
+
+	viper.SetDefault("mfa.issuer", "GinCraft")
+	viper.SetDefault("mfa.pending_ttl", 300)
+
+	viper.SetDefault("oidc.state_ttl", 600)
+	viper.SetDefault("oidc.jwks_cache_ttl", 3600)
+
+	viper.SetDefault("signing.max_skew", 300)
+
+	viper.SetDefault("rbac.cache_ttl", 600)
+
 	viper.SetDefault("cors.allow_methods", []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"})
 	viper.SetDefault("cors.allow_headers", []string{
 		"Content-Type", "Authorization", "X-Requested-With", "X-API-Key", "X-Trace-ID",
@@ -127,5 +376,32 @@ func validate() error {
 	if Config.MySQL.Host == "" || Config.MySQL.Database == "" {
 		return fmt.Errorf("config: mysql.host and mysql.database are required")
 	}
+	if b := Config.RateLimit.Backend; b != "memory" && b != "redis" {
+		return fmt.Errorf("config: rate_limit.backend must be memory or redis, got %q", b)
+	}
+	if a := Config.Password.Hasher.Algorithm; a != "argon2id" && a != "bcrypt" {
+		return fmt.Errorf("config: password.hasher.algorithm must be argon2id or bcrypt, got %q", a)
+	}
+	if a := Config.RateLimit.Algorithm; a != "token_bucket" && a != "sliding_window" && a != "gcra" {
+		return fmt.Errorf("config: rate_limit.algorithm must be token_bucket, sliding_window or gcra, got %q", a)
+	}
+	if d := Config.Search.Driver; d != "memory" && d != "mysql" {
+		return fmt.Errorf("config: search.driver must be memory or mysql, got %q", d)
+	}
+	if d := Config.Mail.Driver; d != "log" && d != "file" {
+		return fmt.Errorf("config: mail.driver must be log or file, got %q", d)
+	}
+	// 使用 RS256/EdDSA 时 jwt.secret 通常为空，各用途密钥无从派生；进程内随机密钥在多实例与重启后失效，仅 debug 模式允许
+	if Config.App.Mode != "debug" {
+		for _, s := range []struct{ key, explicit, purpose string }{
+			{"account.token_secret", Config.Account.TokenSecret, PurposeOneTimeToken},
+			{"pagination.cursor_secret", Config.Pagination.CursorSecret, PurposeCursor},
+			{"signing.secret", Config.Signing.Secret, PurposeSigning},
+		} {
+			if len(SecretFor(s.explicit, s.purpose)) == 0 {
+				return fmt.Errorf("config: %s or jwt.secret is required outside debug mode", s.key)
+			}
+		}
+	}
 	return nil
 }
@@ -0,0 +1,53 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSecretFor(t *testing.T) {
+	old := Config.JWT.Secret
+	defer func() { Config.JWT.Secret = old }()
+
+	Config.JWT.Secret = ""
+	assert.Nil(t, SecretFor("", PurposeCursor))
+	assert.Equal(t, []byte("explicit"), SecretFor("explicit", PurposeCursor))
+
+	Config.JWT.Secret = "jwt-secret"
+	cursor := SecretFor("", PurposeCursor)
+	token := SecretFor("", PurposeOneTimeToken)
+	assert.Len(t, cursor, 32)
+	assert.NotEqual(t, cursor, token)
+	assert.NotEqual(t, []byte("jwt-secret"), cursor)
+	assert.Equal(t, cursor, SecretFor("", PurposeCursor))
+}
+
+func TestValidateSecretsOutsideDebug(t *testing.T) {
+	old := Config
+	defer func() { Config = old }()
+
+	Config.App.Port = 8080
+	Config.MySQL.Host, Config.MySQL.Database = "localhost", "app"
+	Config.RateLimit.Backend, Config.RateLimit.Algorithm = "memory", "gcra"
+	Config.Password.Hasher.Algorithm = "bcrypt"
+	Config.Search.Driver, Config.Mail.Driver = "mysql", "log"
+	Config.JWT.Secret = ""
+	Config.Account.TokenSecret, Config.Pagination.CursorSecret, Config.Signing.Secret = "", "", ""
+
+	// 非对称 JWT 且未单独配置各用途密钥：debug 模式放行，其他模式拒绝启动
+	Config.App.Mode = "debug"
+	assert.NoError(t, validate())
+	Config.App.Mode = "release"
+	assert.ErrorContains(t, validate(), "account.token_secret")
+
+	Config.Account.TokenSecret, Config.Pagination.CursorSecret = "token", "cursor"
+	assert.ErrorContains(t, validate(), "signing.secret")
+	Config.Signing.Secret = "signing"
+	assert.NoError(t, validate())
+
+	// 配置 jwt.secret 时各用途密钥由其派生
+	Config.Account.TokenSecret, Config.Pagination.CursorSecret, Config.Signing.Secret = "", "", ""
+	Config.JWT.Secret = "jwt-secret"
+	assert.NoError(t, validate())
+}
@@ -27,6 +27,13 @@ type Context struct {
 	Username string
 	UserRole string
 
+	TokenID        string
+	TokenExpiresAt time.Time
+
+	APIKeyID      string
+	APIKeyOwnerID string
+	APIKeyScopes  []string
+
 	Method    string
 	Path      string
 	ClientIP  string
@@ -131,6 +138,27 @@ func (c *Context) SetUser(userID, username, userRole string) {
 	c.mu.Unlock()
 }
 
+// SetToken 设置当前访问令牌的 ID（jti）与过期时间，令牌原文不入上下文
+func (c *Context) SetToken(tokenID string, expiresAt time.Time) {
+	c.mu.Lock()
+	c.TokenID = tokenID
+	c.TokenExpiresAt = expiresAt
+	c.mu.Unlock()
+}
+
+// SetAPIKey 设置调用方 API 密钥身份（公开 ID、归属用户与授权范围），密钥原文不入上下文
+func (c *Context) SetAPIKey(keyID, ownerID string, scopes []string) {
+	c.mu.Lock()
+	c.APIKeyID = keyID
+	c.APIKeyOwnerID = ownerID
+	c.APIKeyScopes = scopes
+	c.logFields = append(c.logFields,
+		zap.String("api_key_id", keyID),
+		zap.String("api_key_owner_id", ownerID),
+	)
+	c.mu.Unlock()
+}
+
 // SetRequestInfo 设置请求信息
 func (c *Context) SetRequestInfo(method, path, clientIP, userAgent string) {
 	c.mu.Lock()
@@ -236,6 +264,41 @@ func (c *Context) GetUserRole() string {
 	return c.UserRole
 }
 
+// GetTokenID 获取当前访问令牌 ID
+func (c *Context) GetTokenID() string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.TokenID
+}
+
+// GetTokenExpiresAt 获取当前访问令牌过期时间
+func (c *Context) GetTokenExpiresAt() time.Time {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.TokenExpiresAt
+}
+
+// GetAPIKeyID 获取调用方 API 密钥公开 ID
+func (c *Context) GetAPIKeyID() string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.APIKeyID
+}
+
+// GetAPIKeyOwnerID 获取调用方 API 密钥归属用户ID
+func (c *Context) GetAPIKeyOwnerID() string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.APIKeyOwnerID
+}
+
+// GetAPIKeyScopes 获取调用方 API 密钥授权范围
+func (c *Context) GetAPIKeyScopes() []string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.APIKeyScopes
+}
+
 // GetMethod 获取 HTTP 方法
 func (c *Context) GetMethod() string {
 	c.mu.RLock()
@@ -308,9 +371,9 @@ func (c *Context) buildLogFields(extra ...zap.Field) []zap.Field {
 
 // ----- context.Context 接口实现 -----
 
-func (c *Context) Deadline() (time.Time, bool)    { return c.ctx.Deadline() }
-func (c *Context) Done() <-chan struct{}          { return c.ctx.Done() }
-func (c *Context) Err() error                     { return c.ctx.Err() }
+func (c *Context) Deadline() (time.Time, bool) { return c.ctx.Deadline() }
+func (c *Context) Done() <-chan struct{}       { return c.ctx.Done() }
+func (c *Context) Err() error                  { return c.ctx.Err() }
 func (c *Context) Value(key interface{}) interface{} {
 	if key == CtxKey {
 		return c
@@ -342,19 +405,24 @@ func (c *Context) Clone() *Context {
 
 	ctx, cancel := stdctx.WithCancel(stdctx.Background())
 	clone := &Context{
-		ctx:          ctx,
-		cancel:       cancel,
-		TraceID:      c.TraceID,
-		StartTime:    time.Now(),
-		UserID:       c.UserID,
-		Username:     c.Username,
-		UserRole:     c.UserRole,
-		Method:       c.Method,
-		Path:         c.Path,
-		ClientIP:     c.ClientIP,
-		UserAgent:    c.UserAgent,
-		CustomFields: make(map[string]interface{}, len(c.CustomFields)),
-		logger:       c.logger,
+		ctx:            ctx,
+		cancel:         cancel,
+		TraceID:        c.TraceID,
+		StartTime:      time.Now(),
+		UserID:         c.UserID,
+		Username:       c.Username,
+		UserRole:       c.UserRole,
+		TokenID:        c.TokenID,
+		TokenExpiresAt: c.TokenExpiresAt,
+		APIKeyID:       c.APIKeyID,
+		APIKeyOwnerID:  c.APIKeyOwnerID,
+		APIKeyScopes:   c.APIKeyScopes,
+		Method:         c.Method,
+		Path:           c.Path,
+		ClientIP:       c.ClientIP,
+		UserAgent:      c.UserAgent,
+		CustomFields:   make(map[string]interface{}, len(c.CustomFields)),
+		logger:         c.logger,
 	}
 	for k, v := range c.CustomFields {
 		clone.CustomFields[k] = v
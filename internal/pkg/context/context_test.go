@@ -28,6 +28,18 @@ func TestSetUser(t *testing.T) {
 	assert.Equal(t, "admin", appCtx.GetUserRole())
 }
 
+func TestSetAPIKey(t *testing.T) {
+	appCtx := New(nil)
+	defer appCtx.Cancel()
+
+	appCtx.SetAPIKey("3f9a0c7b12de", "7", []string{"data:read"})
+
+	assert.Equal(t, "3f9a0c7b12de", appCtx.GetAPIKeyID())
+	assert.Equal(t, "7", appCtx.GetAPIKeyOwnerID())
+	assert.Equal(t, []string{"data:read"}, appCtx.GetAPIKeyScopes())
+	assert.Equal(t, "3f9a0c7b12de", appCtx.Clone().GetAPIKeyID())
+}
+
 func TestSetRequestInfo(t *testing.T) {
 	appCtx := New(nil)
 	defer appCtx.Cancel()
@@ -0,0 +1,31 @@
+package cursor
+
+import (
+	"crypto/rand"
+	"sync"
+
+	"github.com/liuchen/gin-craft/internal/pkg/config"
+	pkgcursor "github.com/liuchen/gin-craft/pkg/cursor"
+	"github.com/liuchen/gin-craft/pkg/logger"
+)
+
+var (
+	codec *pkgcursor.Codec
+	once  sync.Once
+)
+
+// GetCodec 获取分页游标编解码器。
+// 签名密钥取 pagination.cursor_secret，未配置时由 jwt.secret 派生（与 JWT 签名密钥不同）；两者均为空时使用进程内随机密钥，
+// 此时游标只能由签发它的实例解码，仅 debug 模式允许（其他模式下配置校验失败）。
+func GetCodec() *pkgcursor.Codec {
+	once.Do(func() {
+		secret := config.SecretFor(config.Config.Pagination.CursorSecret, config.PurposeCursor)
+		if len(secret) == 0 {
+			secret = make([]byte, 32)
+			_, _ = rand.Read(secret)
+			logger.Warn("pagination.cursor_secret and jwt.secret are empty, using an ephemeral key for cursors")
+		}
+		codec = pkgcursor.NewCodec(secret)
+	})
+	return codec
+}
@@ -0,0 +1,25 @@
+package database
+
+import (
+	"context"
+
+	pkgCtx "github.com/liuchen/gin-craft/internal/pkg/context"
+	"go.uber.org/zap"
+)
+
+// sqlLogFields SQL 日志关联请求的字段：链路ID、用户ID与路由，取自 ContextMiddleware 注入的应用 Context；
+// 不在请求中执行的查询（启动、后台任务）不附加字段
+func sqlLogFields(ctx context.Context) []zap.Field {
+	appCtx := pkgCtx.GetContext(ctx)
+	if appCtx == nil {
+		return nil
+	}
+	fields := []zap.Field{zap.String("trace_id", appCtx.GetTraceID())}
+	if uid := appCtx.GetUserID(); uid != "" {
+		fields = append(fields, zap.String("user_id", uid))
+	}
+	if path := appCtx.GetPath(); path != "" {
+		fields = append(fields, zap.String("route", appCtx.GetMethod()+" "+path))
+	}
+	return fields
+}
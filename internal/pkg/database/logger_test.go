@@ -0,0 +1,29 @@
+package database
+
+import (
+	"context"
+	"testing"
+
+	pkgCtx "github.com/liuchen/gin-craft/internal/pkg/context"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSQLLogFields(t *testing.T) {
+	assert.Empty(t, sqlLogFields(context.Background()))
+
+	appCtx := pkgCtx.NewWithTraceID(context.Background(), "trace-1")
+	defer appCtx.Cancel()
+	appCtx.SetRequestInfo("GET", "/api/v1/user/info", "127.0.0.1", "test")
+	ctx := context.WithValue(context.Background(), pkgCtx.CtxKey, appCtx)
+
+	fields := sqlLogFields(ctx)
+	assert.Len(t, fields, 2)
+	assert.Equal(t, "trace-1", fields[0].String)
+	assert.Equal(t, "GET /api/v1/user/info", fields[1].String)
+
+	appCtx.SetUser("42", "alice", "user")
+	fields = sqlLogFields(ctx)
+	assert.Len(t, fields, 3)
+	assert.Equal(t, "user_id", fields[1].Key)
+	assert.Equal(t, "42", fields[1].String)
+}
@@ -29,6 +29,7 @@ func InitDatabase() error {
 			ConnMaxLifetime: cfg.ConnMaxLifetime,
 		}
 
+		pkgdb.SetContextFields(sqlLogFields)
 		db = pkgdb.NewMySQLDatabase(mysqlConfig)
 		if err = db.Connect(); err != nil {
 			return
@@ -38,6 +39,11 @@ func InitDatabase() error {
 	return err
 }
 
+// SetDatabase 替换数据库连接，用于在测试中注入 SQLite 等实现
+func SetDatabase(d pkgdb.Database) {
+	db = d
+}
+
 // GetDatabase 获取 Database 接口实例
 func GetDatabase() pkgdb.Database {
 	return db
@@ -0,0 +1,39 @@
+package hasher
+
+import (
+	"sync"
+
+	"github.com/liuchen/gin-craft/internal/pkg/config"
+	pkghasher "github.com/liuchen/gin-craft/pkg/hasher"
+)
+
+var (
+	manager *pkghasher.Manager
+	once    sync.Once
+)
+
+// GetHasher 获取口令散列管理器：新散列使用 password.hasher.algorithm，
+// 两种算法的历史散列均可校验
+func GetHasher() *pkghasher.Manager {
+	once.Do(func() {
+		cfg := config.Config.Password.Hasher
+		bcrypt := pkghasher.NewBcrypt(cfg.BcryptCost)
+		// 未配置的参数取默认值
+		argon := pkghasher.DefaultArgon2id()
+		if a := cfg.Argon2; a.Memory > 0 && a.Iterations > 0 && a.Parallelism > 0 {
+			argon.Memory, argon.Iterations, argon.Parallelism = a.Memory, a.Iterations, a.Parallelism
+		}
+		if cfg.Argon2.SaltLength > 0 {
+			argon.SaltLength = cfg.Argon2.SaltLength
+		}
+		if cfg.Argon2.KeyLength > 0 {
+			argon.KeyLength = cfg.Argon2.KeyLength
+		}
+		if cfg.Algorithm == "bcrypt" {
+			manager = pkghasher.NewManager(bcrypt, argon)
+			return
+		}
+		manager = pkghasher.NewManager(argon, bcrypt)
+	})
+	return manager
+}
@@ -0,0 +1,56 @@
+package lockout
+
+import (
+	"sync"
+	"time"
+
+	"github.com/liuchen/gin-craft/internal/pkg/config"
+	"github.com/liuchen/gin-craft/internal/pkg/redis"
+	pkglockout "github.com/liuchen/gin-craft/pkg/lockout"
+)
+
+// Redis key 前缀，账号与 IP 两个维度分开计数
+const (
+	accountPrefix = "auth:lockout:account:"
+	ipPrefix      = "auth:lockout:ip:"
+)
+
+var (
+	accountGuard *pkglockout.Guard
+	ipGuard      *pkglockout.Guard
+	once         sync.Once
+)
+
+// InitLockout 根据配置初始化登录失败计数器（须在 InitRedis 之后调用）
+func InitLockout() error {
+	var err error
+	once.Do(func() {
+		cfg := config.Config.Lockout
+		window := time.Duration(cfg.Window) * time.Second
+		accountGuard, err = pkglockout.NewGuard(redis.GetRedisClient(), accountPrefix, toPolicy(cfg.Account, window))
+		if err != nil {
+			return
+		}
+		ipGuard, err = pkglockout.NewGuard(redis.GetRedisClient(), ipPrefix, toPolicy(cfg.IP, window))
+	})
+	return err
+}
+
+// GetAccountGuard 按用户名计数的失败计数器
+func GetAccountGuard() *pkglockout.Guard {
+	return accountGuard
+}
+
+// GetIPGuard 按客户端 IP 计数的失败计数器
+func GetIPGuard() *pkglockout.Guard {
+	return ipGuard
+}
+
+func toPolicy(p config.LockoutPolicy, window time.Duration) pkglockout.Policy {
+	return pkglockout.Policy{
+		Threshold: p.Threshold,
+		BaseDelay: time.Duration(p.BaseDelay) * time.Second,
+		MaxDelay:  time.Duration(p.MaxDelay) * time.Second,
+		Window:    window,
+	}
+}
@@ -0,0 +1,43 @@
+package mailer
+
+import (
+	"sync"
+
+	"github.com/liuchen/gin-craft/internal/pkg/config"
+	"github.com/liuchen/gin-craft/pkg/logger"
+	pkgmailer "github.com/liuchen/gin-craft/pkg/mailer"
+	"go.uber.org/zap"
+)
+
+var (
+	mu     sync.Mutex
+	mailer pkgmailer.Mailer
+)
+
+// GetMailer 获取邮件发送器，首次使用时按 mail.driver 创建
+func GetMailer() pkgmailer.Mailer {
+	mu.Lock()
+	defer mu.Unlock()
+
+	if mailer != nil {
+		return mailer
+	}
+	cfg := config.Config.Mail
+	if cfg.Driver == "file" {
+		m, err := pkgmailer.NewFileMailer(cfg.From, cfg.Dir)
+		if err == nil {
+			mailer = m
+			return mailer
+		}
+		logger.Error("Failed to init file mailer, falling back to log", zap.Error(err))
+	}
+	mailer = pkgmailer.NewLogMailer(cfg.From)
+	return mailer
+}
+
+// SetMailer 替换邮件发送器，用于接入 SMTP 等自定义实现或在测试中注入
+func SetMailer(m pkgmailer.Mailer) {
+	mu.Lock()
+	mailer = m
+	mu.Unlock()
+}
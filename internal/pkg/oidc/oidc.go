@@ -0,0 +1,51 @@
+package oidc
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/liuchen/gin-craft/internal/pkg/config"
+	pkgoidc "github.com/liuchen/gin-craft/pkg/oidc"
+)
+
+// ErrProviderNotFound 未配置的提供方
+var ErrProviderNotFound = errors.New("oidc: provider not configured")
+
+var (
+	mu        sync.Mutex
+	providers = make(map[string]*pkgoidc.Provider)
+
+	httpClient = &http.Client{Timeout: 10 * time.Second}
+)
+
+// GetProvider 按名称获取登录提供方。首次使用时拉取发现文档，失败不缓存，下次请求重试
+func GetProvider(ctx context.Context, name string) (*pkgoidc.Provider, error) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	if p, ok := providers[name]; ok {
+		return p, nil
+	}
+	for _, cfg := range config.Config.OIDC.Providers {
+		if cfg.Name != name {
+			continue
+		}
+		p, err := pkgoidc.NewProvider(ctx, pkgoidc.Config{
+			Issuer:       cfg.Issuer,
+			ClientID:     cfg.ClientID,
+			ClientSecret: cfg.ClientSecret,
+			RedirectURL:  cfg.RedirectURL,
+			Scopes:       cfg.Scopes,
+			JWKSCacheTTL: time.Duration(config.Config.OIDC.JWKSCacheTTL) * time.Second,
+		}, httpClient)
+		if err != nil {
+			return nil, err
+		}
+		providers[name] = p
+		return p, nil
+	}
+	return nil, ErrProviderNotFound
+}
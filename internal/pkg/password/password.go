@@ -0,0 +1,41 @@
+package password
+
+import (
+	"sync"
+
+	"github.com/liuchen/gin-craft/internal/pkg/config"
+	"github.com/liuchen/gin-craft/pkg/logger"
+	pkgpassword "github.com/liuchen/gin-craft/pkg/password"
+	"go.uber.org/zap"
+)
+
+var (
+	policy *pkgpassword.Policy
+	once   sync.Once
+)
+
+// GetPolicy 获取密码策略，首次使用时加载泄露密码文件；文件读取失败时记录错误并跳过泄露库检查
+func GetPolicy() *pkgpassword.Policy {
+	once.Do(func() {
+		cfg := config.Config.Password
+		policy = &pkgpassword.Policy{
+			MinLength:     cfg.MinLength,
+			MaxLength:     cfg.MaxLength,
+			RequireUpper:  cfg.RequireUpper,
+			RequireLower:  cfg.RequireLower,
+			RequireDigit:  cfg.RequireDigit,
+			RequireSymbol: cfg.RequireSymbol,
+		}
+		if cfg.BreachedFile == "" {
+			return
+		}
+		list, err := pkgpassword.LoadBreachedFile(cfg.BreachedFile)
+		if err != nil {
+			logger.Error("Failed to load breached password list", zap.String("file", cfg.BreachedFile), zap.Error(err))
+			return
+		}
+		policy.Breached = list
+		logger.Info("Breached password list loaded", zap.Int("entries", list.Len()))
+	})
+	return policy
+}
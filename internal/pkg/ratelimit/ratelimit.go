@@ -0,0 +1,90 @@
+package ratelimit
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/liuchen/gin-craft/internal/pkg/config"
+	"github.com/liuchen/gin-craft/internal/pkg/redis"
+	"github.com/liuchen/gin-craft/pkg/logger"
+	pkgratelimit "github.com/liuchen/gin-craft/pkg/ratelimit"
+	"go.uber.org/zap"
+)
+
+var (
+	limiter pkgratelimit.Limiter
+	once    sync.Once
+
+	policies atomic.Pointer[pkgratelimit.PolicyTable]
+)
+
+// InitRateLimiter 根据配置初始化限流器与策略表（redis 后端须在 InitRedis 之后调用）。
+// 策略表随配置文件热更新，新配置非法时保留旧策略。
+func InitRateLimiter() error {
+	var err error
+	once.Do(func() {
+		cfg := config.Config.RateLimit
+		alg := pkgratelimit.Algorithm(cfg.Algorithm)
+		if cfg.Backend == "redis" {
+			limiter, err = pkgratelimit.NewRedisLimiter(redis.GetRedisClient(), alg, cfg.KeyPrefix)
+		} else {
+			limiter, err = pkgratelimit.NewMemoryLimiter(alg)
+		}
+		if err != nil {
+			return
+		}
+		if err = LoadPolicies(cfg.Policies); err != nil {
+			return
+		}
+
+		config.OnChange(func(next *config.AppConfig) {
+			if err := LoadPolicies(next.RateLimit.Policies); err != nil {
+				logger.Error("Failed to reload rate limit policies, keeping previous", zap.Error(err))
+				return
+			}
+			logger.Info("Rate limit policies reloaded", zap.Int("policies", GetPolicies().Len()))
+		})
+	})
+	return err
+}
+
+// GetLimiter 获取限流器
+func GetLimiter() pkgratelimit.Limiter {
+	return limiter
+}
+
+// GetPolicies 获取当前生效的策略表
+func GetPolicies() *pkgratelimit.PolicyTable {
+	return policies.Load()
+}
+
+// LoadPolicies 校验配置并原子替换策略表
+func LoadPolicies(cfgs []config.RateLimitPolicy) error {
+	list := make([]*pkgratelimit.Policy, 0, len(cfgs))
+	for _, c := range cfgs {
+		p := &pkgratelimit.Policy{
+			Name:   c.Name,
+			Routes: c.Routes,
+			Key:    pkgratelimit.KeyType(c.Key),
+			Limit:  toLimit(c.Rate, c.Period, c.Burst),
+		}
+		if len(c.Roles) > 0 {
+			p.Roles = make(map[string]pkgratelimit.Limit, len(c.Roles))
+			for role, q := range c.Roles {
+				p.Roles[role] = toLimit(q.Rate, q.Period, q.Burst)
+			}
+		}
+		list = append(list, p)
+	}
+	table, err := pkgratelimit.NewPolicyTable(list)
+	if err != nil {
+		return err
+	}
+	policies.Store(table)
+	return nil
+}
+
+func toLimit(rate, period, burst int) pkgratelimit.Limit {
+	return pkgratelimit.Limit{Rate: rate, Period: time.Duration(period) * time.Second, Burst: burst}
+}
@@ -40,6 +40,11 @@ func InitRedis() error {
 	return err
 }
 
+// SetClient 替换 Redis 客户端，用于在测试中注入 miniredis 等实现
+func SetClient(c *pkgredis.Client) {
+	client = c
+}
+
 // GetClient 获取底层 *redis.Client
 func GetClient() *redis.Client {
 	if client == nil {
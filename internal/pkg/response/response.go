@@ -19,10 +19,15 @@ type Response struct {
 // httpStatusByCode 业务错误码 → HTTP 状态码映射
 var httpStatusByCode = map[int]int{
 	constant.Unauthorized:    http.StatusUnauthorized,
+	constant.TokenExpired:    http.StatusUnauthorized,
+	constant.TokenInvalid:    http.StatusUnauthorized,
 	constant.Forbidden:       http.StatusForbidden,
 	constant.NotFound:        http.StatusNotFound,
 	constant.MethodNotAllow:  http.StatusMethodNotAllowed,
 	constant.TooManyRequests: http.StatusTooManyRequests,
+	constant.AccountLocked:   http.StatusTooManyRequests,
+	constant.UserSuspended:   http.StatusForbidden,
+	constant.UserPending:     http.StatusForbidden,
 	constant.Timeout:         http.StatusGatewayTimeout,
 	constant.ParamError:      http.StatusBadRequest,
 	constant.SystemError:     http.StatusInternalServerError,
@@ -1,10 +1,19 @@
 package router
 
-import "github.com/gin-gonic/gin"
+import (
+	"net/http"
+	"path"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RouteMiddlewareFunc 按路由（方法 + 注册时的完整路径）生成附加中间件，返回 nil 表示不附加
+type RouteMiddlewareFunc func(method, fullPath string) gin.HandlerFunc
 
 // ElegantRouter 薄封装 gin.IRoutes，主要提供泛型 WrapRequestHandler 的路由入口。
 type ElegantRouter struct {
-	routes gin.IRoutes
+	routes    gin.IRoutes
+	routeMWFs []RouteMiddlewareFunc
 }
 
 // NewElegantRouter 从 *gin.Engine 构造
@@ -26,7 +35,15 @@ func (r *ElegantRouter) Group(path string, mws ...gin.HandlerFunc) *ElegantRoute
 	if !ok {
 		return r
 	}
-	return NewElegantRouterGroup(g.Group(path, mws...))
+	sub := NewElegantRouterGroup(g.Group(path, mws...))
+	sub.routeMWFs = r.routeMWFs
+	return sub
+}
+
+// UseRouteMiddleware 注册路由级中间件工厂，之后注册的路由（含子路由组）都会调用。
+// 生成的中间件位于路由组中间件之后、路由自带中间件之前。
+func (r *ElegantRouter) UseRouteMiddleware(fs ...RouteMiddlewareFunc) {
+	r.routeMWFs = append(r.routeMWFs[:len(r.routeMWFs):len(r.routeMWFs)], fs...)
 }
 
 // Use 添加中间件
@@ -34,29 +51,53 @@ func (r *ElegantRouter) Use(mws ...gin.HandlerFunc) gin.IRoutes {
 	return r.routes.Use(mws...)
 }
 
-// combine 拷贝出新切片，避免共享 variadic 底层数组
-func combine(mws []gin.HandlerFunc, h gin.HandlerFunc) []gin.HandlerFunc {
-	out := make([]gin.HandlerFunc, 0, len(mws)+1)
+// FullPath 返回相对路径 p 在当前路由组下的完整路径
+func (r *ElegantRouter) FullPath(p string) string {
+	base := "/"
+	if b, ok := r.routes.(interface{ BasePath() string }); ok {
+		base = b.BasePath()
+	}
+	if p == "" {
+		return base
+	}
+	full := path.Join(base, p)
+	if p[len(p)-1] == '/' && full[len(full)-1] != '/' {
+		full += "/"
+	}
+	return full
+}
+
+// combine 拷贝出新切片，避免共享 variadic 底层数组；路由级中间件排在最前
+func (r *ElegantRouter) combine(method, p string, mws []gin.HandlerFunc, h gin.HandlerFunc) []gin.HandlerFunc {
+	out := make([]gin.HandlerFunc, 0, len(r.routeMWFs)+len(mws)+1)
+	if len(r.routeMWFs) > 0 {
+		full := r.FullPath(p)
+		for _, f := range r.routeMWFs {
+			if mw := f(method, full); mw != nil {
+				out = append(out, mw)
+			}
+		}
+	}
 	out = append(out, mws...)
 	return append(out, h)
 }
 
 func (r *ElegantRouter) GET(p string, h gin.HandlerFunc, mws ...gin.HandlerFunc) gin.IRoutes {
-	return r.routes.GET(p, combine(mws, h)...)
+	return r.routes.GET(p, r.combine(http.MethodGet, p, mws, h)...)
 }
 
 func (r *ElegantRouter) POST(p string, h gin.HandlerFunc, mws ...gin.HandlerFunc) gin.IRoutes {
-	return r.routes.POST(p, combine(mws, h)...)
+	return r.routes.POST(p, r.combine(http.MethodPost, p, mws, h)...)
 }
 
 func (r *ElegantRouter) PUT(p string, h gin.HandlerFunc, mws ...gin.HandlerFunc) gin.IRoutes {
-	return r.routes.PUT(p, combine(mws, h)...)
+	return r.routes.PUT(p, r.combine(http.MethodPut, p, mws, h)...)
 }
 
 func (r *ElegantRouter) DELETE(p string, h gin.HandlerFunc, mws ...gin.HandlerFunc) gin.IRoutes {
-	return r.routes.DELETE(p, combine(mws, h)...)
+	return r.routes.DELETE(p, r.combine(http.MethodDelete, p, mws, h)...)
 }
 
 func (r *ElegantRouter) PATCH(p string, h gin.HandlerFunc, mws ...gin.HandlerFunc) gin.IRoutes {
-	return r.routes.PATCH(p, combine(mws, h)...)
+	return r.routes.PATCH(p, r.combine(http.MethodPatch, p, mws, h)...)
 }
@@ -0,0 +1,56 @@
+package router
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRouteMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	engine := gin.New()
+	r := NewElegantRouter(engine)
+
+	var registered []string
+	var order []string
+	r.UseRouteMiddleware(func(method, fullPath string) gin.HandlerFunc {
+		registered = append(registered, method+" "+fullPath)
+		return func(c *gin.Context) {
+			order = append(order, "route")
+			c.Next()
+		}
+	})
+
+	group := r.Group("/api/v1", func(c *gin.Context) {
+		order = append(order, "group")
+		c.Next()
+	})
+	group.POST("/user/login", func(c *gin.Context) {
+		order = append(order, "handler")
+		c.Status(http.StatusNoContent)
+	}, func(c *gin.Context) {
+		order = append(order, "own")
+		c.Next()
+	})
+	r.GET("/health", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	assert.Equal(t, []string{"POST /api/v1/user/login", "GET /health"}, registered)
+
+	w := httptest.NewRecorder()
+	engine.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api/v1/user/login", nil))
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	assert.Equal(t, []string{"group", "route", "own", "handler"}, order)
+}
+
+func TestFullPath(t *testing.T) {
+	r := NewElegantRouter(gin.New())
+	assert.Equal(t, "/", r.FullPath(""))
+	assert.Equal(t, "/health", r.FullPath("/health"))
+
+	g := r.Group("/api").Group("/v1")
+	assert.Equal(t, "/api/v1/user", g.FullPath("/user"))
+	assert.Equal(t, "/api/v1/user/", g.FullPath("/user/"))
+}
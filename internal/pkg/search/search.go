@@ -0,0 +1,127 @@
+package search
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/liuchen/gin-craft/internal/model"
+	"github.com/liuchen/gin-craft/internal/pkg/config"
+	"github.com/liuchen/gin-craft/internal/pkg/database"
+	"github.com/liuchen/gin-craft/pkg/logger"
+	pkgsearch "github.com/liuchen/gin-craft/pkg/search"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+const rebuildBatchSize = 500
+
+var (
+	searcher pkgsearch.Searcher
+	// managed 为 true 时索引由本进程维护（memory 驱动），用户写入后需要同步
+	managed bool
+	once    sync.Once
+)
+
+// InitSearch 按 search.driver 创建用户检索（须在 InitDatabase 之后调用）。
+// mysql 驱动（默认）在 mysql.auto_migrate 时确保 FULLTEXT 索引存在；memory 驱动从数据库全量构建进程内索引，
+// 只同步本实例的写入，多实例部署时各实例结果不一致，仅用于本地开发与单实例部署
+func InitSearch(ctx context.Context) error {
+	var err error
+	once.Do(func() {
+		if config.Config.Search.Driver == "mysql" {
+			s := pkgsearch.NewMySQL(database.GetDB(), "user", "username", "email") // 单数表名，见 pkg/database 的命名策略
+			if config.Config.MySQL.AutoMigrate {
+				if err = s.EnsureIndex(ctx); err != nil {
+					return
+				}
+			}
+			searcher = s
+			return
+		}
+
+		m := pkgsearch.NewMemory()
+		var users []model.User
+		err = database.GetDB().WithContext(ctx).Select("id", "username", "email").
+			FindInBatches(&users, rebuildBatchSize, func(tx *gorm.DB, _ int) error {
+				docs := make([]pkgsearch.Document, 0, len(users))
+				for i := range users {
+					docs = append(docs, UserDocument(&users[i]))
+				}
+				return m.Index(ctx, docs...)
+			}).Error
+		if err != nil {
+			return
+		}
+		logger.Info("User search index built", zap.Int("users", m.Len()))
+		searcher, managed = m, true
+	})
+	return err
+}
+
+// GetSearcher 获取用户检索；未初始化时返回 nil，调用方应退回 LIKE 查询
+func GetSearcher() pkgsearch.Searcher {
+	return searcher
+}
+
+// SearchUsers 检索用户，按相关度从高到低返回用户ID，最多 search.max_hits 条；
+// 命中数达到上限时 truncated 为 true，表示可能还有未取回的命中
+func SearchUsers(ctx context.Context, text string) (ids []uint, truncated bool, err error) {
+	limit := config.Config.Search.MaxHits
+	hits, err := searcher.Search(ctx, text, limit)
+	if err != nil {
+		return nil, false, err
+	}
+	ids = make([]uint, 0, len(hits))
+	for _, h := range hits {
+		ids = append(ids, uint(h.ID))
+	}
+	return ids, len(hits) >= limit, nil
+}
+
+// IndexUser 将新建的用户写入索引
+func IndexUser(ctx context.Context, u *model.User) {
+	if !managed {
+		return
+	}
+	if err := searcher.Index(ctx, UserDocument(u)); err != nil {
+		logger.Error("Failed to index user", zap.Uint("user_id", u.ID), zap.Error(err))
+	}
+}
+
+// UserDocument 用户的检索文档：用户名权重高于邮箱
+func UserDocument(u *model.User) pkgsearch.Document {
+	return pkgsearch.Document{
+		ID:     uint64(u.ID),
+		Fields: []pkgsearch.Field{{Text: u.Username, Weight: 2}, {Text: u.Email, Weight: 1}},
+	}
+}
+
+// SyncUser 用户写入后同步索引：重新读取用户并覆盖文档，用户不存在或已删除时移出索引。
+// 索引由数据库维护时直接返回；同步失败只记录日志，检索结果以数据库为准
+func SyncUser(ctx context.Context, id uint) {
+	if !managed {
+		return
+	}
+	var u model.User
+	err := database.GetDB().WithContext(ctx).Select("id", "username", "email").First(&u, id).Error
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		err = searcher.Remove(ctx, uint64(id))
+	case err == nil:
+		err = searcher.Index(ctx, UserDocument(&u))
+	}
+	if err != nil {
+		logger.Error("Failed to sync user search index", zap.Uint("user_id", id), zap.Error(err))
+	}
+}
+
+// RemoveUser 将用户移出索引
+func RemoveUser(ctx context.Context, id uint) {
+	if !managed {
+		return
+	}
+	if err := searcher.Remove(ctx, uint64(id)); err != nil {
+		logger.Error("Failed to remove user from search index", zap.Uint("user_id", id), zap.Error(err))
+	}
+}
@@ -0,0 +1,48 @@
+package signature
+
+import (
+	"crypto/rand"
+	"sync"
+	"time"
+
+	"github.com/liuchen/gin-craft/internal/pkg/config"
+	"github.com/liuchen/gin-craft/internal/pkg/redis"
+	"github.com/liuchen/gin-craft/pkg/apikey"
+	"github.com/liuchen/gin-craft/pkg/logger"
+	pkgsignature "github.com/liuchen/gin-craft/pkg/signature"
+)
+
+// noncePrefix nonce 在 Redis 中的 key 前缀
+const noncePrefix = "sign:nonce:"
+
+var (
+	verifier *pkgsignature.Verifier
+	once     sync.Once
+
+	pepper     []byte
+	pepperOnce sync.Once
+)
+
+// GetVerifier 获取请求签名校验器（依赖 Redis，须在 InitRedis 之后调用）
+func GetVerifier() *pkgsignature.Verifier {
+	once.Do(func() {
+		maxSkew := time.Duration(config.Config.Signing.MaxSkew) * time.Second
+		verifier = pkgsignature.NewVerifier(maxSkew, pkgsignature.NewRedisNonceStore(redis.GetRedisClient(), noncePrefix))
+	})
+	return verifier
+}
+
+// SigningSecret API 密钥的请求签名密钥，由服务端密钥与密钥摘要派生，仅凭数据库记录无法算出。
+// 服务端密钥取 signing.secret，未配置时由 jwt.secret 派生；两者均为空时使用进程内随机密钥，
+// 此时签名密钥在重启后失效，仅 debug 模式允许（其他模式下配置校验失败）。
+func SigningSecret(keyHash string) string {
+	pepperOnce.Do(func() {
+		pepper = config.SecretFor(config.Config.Signing.Secret, config.PurposeSigning)
+		if len(pepper) == 0 {
+			pepper = make([]byte, 32)
+			_, _ = rand.Read(pepper)
+			logger.Warn("signing.secret and jwt.secret are empty, using an ephemeral key for API key signing secrets")
+		}
+	})
+	return apikey.SigningSecret(pepper, keyHash)
+}
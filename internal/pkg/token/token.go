@@ -0,0 +1,81 @@
+package token
+
+import (
+	"crypto/rand"
+	"sync"
+	"time"
+
+	"github.com/liuchen/gin-craft/internal/pkg/config"
+	"github.com/liuchen/gin-craft/internal/pkg/redis"
+	"github.com/liuchen/gin-craft/pkg/logger"
+	pkgtoken "github.com/liuchen/gin-craft/pkg/token"
+)
+
+var (
+	manager *pkgtoken.Manager
+	once    sync.Once
+
+	refreshStore *pkgtoken.RefreshStore
+	refreshOnce  sync.Once
+
+	denylist     *pkgtoken.Denylist
+	denylistOnce sync.Once
+
+	oneTimeStore *pkgtoken.OneTimeStore
+	oneTimeOnce  sync.Once
+)
+
+// InitToken 根据配置初始化令牌管理器
+func InitToken() error {
+	var err error
+	once.Do(func() {
+		cfg := config.Config.JWT
+		manager, err = pkgtoken.NewManager(&pkgtoken.Config{
+			Algorithm:      cfg.Algorithm,
+			Secret:         cfg.Secret,
+			PrivateKeyFile: cfg.PrivateKeyFile,
+			PublicKeyFile:  cfg.PublicKeyFile,
+			Issuer:         cfg.Issuer,
+			AccessTTL:      cfg.AccessTTL,
+		})
+	})
+	return err
+}
+
+// GetManager 获取令牌管理器
+func GetManager() *pkgtoken.Manager {
+	return manager
+}
+
+// GetRefreshStore 获取刷新令牌存储（依赖 Redis，须在 InitRedis 之后调用）
+func GetRefreshStore() *pkgtoken.RefreshStore {
+	refreshOnce.Do(func() {
+		ttl := time.Duration(config.Config.JWT.RefreshTTL) * time.Second
+		refreshStore = pkgtoken.NewRefreshStore(redis.GetRedisClient(), ttl)
+	})
+	return refreshStore
+}
+
+// GetDenylist 获取访问令牌吊销名单（依赖 Redis，须在 InitRedis 之后调用）
+func GetDenylist() *pkgtoken.Denylist {
+	denylistOnce.Do(func() {
+		denylist = pkgtoken.NewDenylist(redis.GetRedisClient())
+	})
+	return denylist
+}
+
+// GetOneTimeStore 获取一次性令牌存储（依赖 Redis，须在 InitRedis 之后调用）。
+// 签名密钥取 account.token_secret，未配置时由 jwt.secret 派生（与 JWT 签名密钥不同）；两者均为空时使用进程内随机密钥，
+// 此时令牌只能由签发它的实例校验，仅 debug 模式允许（其他模式下配置校验失败）。
+func GetOneTimeStore() *pkgtoken.OneTimeStore {
+	oneTimeOnce.Do(func() {
+		secret := config.SecretFor(config.Config.Account.TokenSecret, config.PurposeOneTimeToken)
+		if len(secret) == 0 {
+			secret = make([]byte, 32)
+			_, _ = rand.Read(secret)
+			logger.Warn("account.token_secret and jwt.secret are empty, using an ephemeral key for one-time tokens")
+		}
+		oneTimeStore = pkgtoken.NewOneTimeStore(redis.GetRedisClient(), secret)
+	})
+	return oneTimeStore
+}
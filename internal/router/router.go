@@ -2,9 +2,11 @@ package router
 
 import (
 	"github.com/gin-gonic/gin"
+	"github.com/liuchen/gin-craft/internal/constant"
 	"github.com/liuchen/gin-craft/internal/controller"
 	"github.com/liuchen/gin-craft/internal/middleware"
 	er "github.com/liuchen/gin-craft/internal/pkg/router"
+	"github.com/liuchen/gin-craft/pkg/ratelimit"
 	swaggerFiles "github.com/swaggo/files"
 	ginSwagger "github.com/swaggo/gin-swagger"
 )
@@ -20,6 +22,8 @@ func InitRouter() *gin.Engine {
 	)
 
 	elegantR := er.NewElegantRouter(r)
+	// 配置驱动的限流策略（rate_limit.policies），按注册路由匹配，支持热更新
+	elegantR.UseRouteMiddleware(middleware.RateLimitPolicy)
 
 	elegantR.GET("/health", er.WrapHandler(func(c *gin.Context) (interface{}, error) {
 		return gin.H{"status": "ok"}, nil
@@ -27,6 +31,11 @@ func InitRouter() *gin.Engine {
 	elegantR.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
 
 	userCtrl := controller.NewUserController()
+	rbacCtrl := controller.NewRBACController()
+	apiKeyCtrl := controller.NewAPIKeyController()
+	lockoutCtrl := controller.NewLockoutController()
+	adminUserCtrl := controller.NewAdminUserController()
+	auditCtrl := controller.NewAuditController()
 
 	api := elegantR.Group("/api")
 	v1 := api.Group("/v1")
@@ -36,30 +45,82 @@ func InitRouter() *gin.Engine {
 	{
 		publicUser.POST("/register", er.WrapRequestHandler(userCtrl.Register))
 		publicUser.POST("/login", er.WrapRequestHandler(userCtrl.Login))
+		publicUser.POST("/login/mfa", er.WrapRequestHandler(userCtrl.MFALogin))
+		publicUser.POST("/refresh", er.WrapRequestHandler(userCtrl.Refresh))
+		publicUser.POST("/forgot-password", er.WrapRequestHandler(userCtrl.ForgotPassword))
+		publicUser.POST("/reset-password", er.WrapRequestHandler(userCtrl.ResetPassword))
+		publicUser.POST("/verify-email", er.WrapRequestHandler(userCtrl.VerifyEmail))
+		publicUser.GET("/oidc/login", er.WrapRequestHandler(userCtrl.OIDCLogin))
+		publicUser.GET("/oidc/callback", er.WrapRequestHandler(userCtrl.OIDCCallback))
 	}
 
 	// 认证路由
 	authUser := v1.Group("/user", middleware.AuthMiddleware())
 	{
 		authUser.GET("/info", er.WrapRequestHandler(userCtrl.Info))
+		authUser.POST("/logout", er.WrapRequestHandler(userCtrl.Logout))
 		authUser.POST("/list", er.WrapRequestHandler(userCtrl.List))
+		authUser.POST("/list/cursor", er.WrapRequestHandler(userCtrl.ListByCursor))
 		authUser.POST("/edit", er.WrapRequestHandler(userCtrl.Update))
 		authUser.POST("/delete", er.WrapRequestHandler(userCtrl.Delete))
-		authUser.GET("/profile", er.WrapRequestHandler(userCtrl.Info), middleware.RateLimitMiddleware())
+		authUser.POST("/change-password", er.WrapRequestHandler(userCtrl.ChangePassword))
+		authUser.POST("/verify-email/send", er.WrapHandler(userCtrl.SendVerification))
+		authUser.POST("/mfa/enroll", er.WrapRequestHandler(userCtrl.MFAEnroll))
+		authUser.POST("/mfa/enable", er.WrapRequestHandler(userCtrl.MFAEnable))
+		authUser.POST("/mfa/disable", er.WrapRequestHandler(userCtrl.MFADisable))
+		authUser.GET("/profile", er.WrapRequestHandler(userCtrl.Info), middleware.RateLimitMiddleware(ratelimit.PerMinute(60), middleware.RateLimitByUser))
 	}
 
-	admin := v1.Group("/admin", middleware.AuthMiddleware(), middleware.AdminAuthMiddleware())
+	admin := v1.Group("/admin", middleware.AuthMiddleware(), middleware.RequirePermission(constant.PermAdminAccess))
 	{
-		admin.GET("/users", er.WrapHandler(func(c *gin.Context) (interface{}, error) {
-			return gin.H{"message": "管理员用户列表"}, nil
-		}))
+		userAdmin := middleware.RequirePermission(constant.PermUserAdmin)
+		admin.GET("/users", er.WrapRequestHandler(adminUserCtrl.List), userAdmin)
+		admin.GET("/users/detail", er.WrapRequestHandler(adminUserCtrl.Detail), userAdmin)
+		admin.POST("/users/import", er.WrapHandler(adminUserCtrl.Import), userAdmin)
+		admin.GET("/users/export", adminUserCtrl.Export, userAdmin)
+		admin.POST("/users/restore", er.WrapRequestHandler(adminUserCtrl.Restore), userAdmin)
+		admin.POST("/users/hard-delete", er.WrapRequestHandler(adminUserCtrl.HardDelete), userAdmin, middleware.RequirePermission(constant.PermUserDelete))
+		admin.GET("/users/status-logs", er.WrapRequestHandler(adminUserCtrl.StatusLogs), userAdmin)
+		admin.POST("/users/suspend", er.WrapRequestHandler(adminUserCtrl.Suspend), userAdmin)
+		admin.POST("/users/activate", er.WrapRequestHandler(adminUserCtrl.Activate), userAdmin)
+		admin.POST("/users/role", er.WrapRequestHandler(adminUserCtrl.AssignRole), userAdmin)
+		admin.POST("/users/revoke-sessions", er.WrapRequestHandler(userCtrl.RevokeSessions), middleware.RequirePermission(constant.PermSessionRevoke))
+
+		roleManage := middleware.RequirePermission(constant.PermRoleManage)
+		admin.GET("/roles", er.WrapHandler(rbacCtrl.ListRoles), roleManage)
+		admin.POST("/roles", er.WrapRequestHandler(rbacCtrl.CreateRole), roleManage)
+		admin.POST("/roles/grant", er.WrapRequestHandler(rbacCtrl.Grant), roleManage)
+		admin.POST("/roles/revoke", er.WrapRequestHandler(rbacCtrl.Revoke), roleManage)
+		admin.GET("/permissions", er.WrapHandler(rbacCtrl.ListPermissions), roleManage)
+		admin.POST("/permissions", er.WrapRequestHandler(rbacCtrl.CreatePermission), roleManage)
+
+		apiKeyManage := middleware.RequirePermission(constant.PermAPIKeyManage)
+		admin.GET("/api-keys", er.WrapRequestHandler(apiKeyCtrl.List), apiKeyManage)
+		admin.POST("/api-keys", er.WrapRequestHandler(apiKeyCtrl.Create), apiKeyManage)
+		admin.POST("/api-keys/edit", er.WrapRequestHandler(apiKeyCtrl.Update), apiKeyManage)
+		admin.POST("/api-keys/delete", er.WrapRequestHandler(apiKeyCtrl.Delete), apiKeyManage)
+
+		lockoutManage := middleware.RequirePermission(constant.PermLockoutManage)
+		admin.GET("/lockouts", er.WrapRequestHandler(lockoutCtrl.List), lockoutManage)
+		admin.POST("/lockouts/clear", er.WrapRequestHandler(lockoutCtrl.Clear), lockoutManage)
+
+		admin.GET("/audit-logs", er.WrapRequestHandler(auditCtrl.List), middleware.RequirePermission(constant.PermAuditRead))
 	}
 
-	apiRoutes := v1.Group("/api", middleware.ValidateAPIKeyMiddleware())
+	// 服务间接口：HMAC 请求签名（pkg/signature），不传输密钥本身
+	apiRoutes := v1.Group("/api", middleware.SignatureMiddleware())
 	{
 		apiRoutes.GET("/data", er.WrapHandler(func(c *gin.Context) (interface{}, error) {
 			return gin.H{"data": "API数据"}, nil
-		}))
+		}), middleware.RequireAPIKeyScope("data:read"))
+	}
+
+	// 开放接口：X-API-Key 携带完整密钥，适用于无法实现请求签名的调用方
+	openRoutes := v1.Group("/open", middleware.ValidateAPIKeyMiddleware())
+	{
+		openRoutes.GET("/data", er.WrapHandler(func(c *gin.Context) (interface{}, error) {
+			return gin.H{"data": "API数据"}, nil
+		}), middleware.RequireAPIKeyScope("data:read"))
 	}
 
 	return r
@@ -0,0 +1,204 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/liuchen/gin-craft/internal/constant"
+	"github.com/liuchen/gin-craft/internal/dao"
+	dtoUser "github.com/liuchen/gin-craft/internal/dto/user"
+	"github.com/liuchen/gin-craft/internal/model"
+	"github.com/liuchen/gin-craft/internal/pkg/config"
+	pkgCtx "github.com/liuchen/gin-craft/internal/pkg/context"
+	apperr "github.com/liuchen/gin-craft/internal/pkg/errors"
+	"github.com/liuchen/gin-craft/internal/pkg/mailer"
+	"github.com/liuchen/gin-craft/internal/pkg/token"
+	pkgmailer "github.com/liuchen/gin-craft/pkg/mailer"
+	pkgtoken "github.com/liuchen/gin-craft/pkg/token"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// 一次性令牌用途，签名与 Redis key 均按用途隔离
+const (
+	purposeResetPassword = "reset_password"
+	purposeVerifyEmail   = "verify_email"
+)
+
+// accountService 账号安全服务：找回密码与邮箱验证
+type accountService struct {
+	userDAO *dao.UserDAO
+}
+
+// NewAccountService 构造函数
+func NewAccountService() *accountService {
+	return &accountService{userDAO: dao.GetUserDAO()}
+}
+
+// AccountService 全局默认实例
+var AccountService = NewAccountService()
+
+// ForgotPassword 向注册邮箱发送重置密码链接。
+// 邮箱未注册时同样返回成功，避免接口被用来探测账号是否存在。
+func (s *accountService) ForgotPassword(ctx context.Context, req *dtoUser.ForgotPasswordRequest) error {
+	appCtx := pkgCtx.MustGetContext(ctx)
+
+	user, err := s.userDAO.GetByEmail(ctx, req.Email)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			appCtx.LogInfo("找回密码：邮箱未注册")
+			return nil
+		}
+		return err
+	}
+
+	ttl := time.Duration(config.Config.Account.ResetTTL) * time.Second
+	raw, err := token.GetOneTimeStore().Issue(ctx, purposeResetPassword, strconv.FormatUint(uint64(user.ID), 10), ttl)
+	if err != nil {
+		return apperr.New(constant.SystemError, err.Error())
+	}
+	body := fmt.Sprintf("%s，您好：\n\n请在 %d 分钟内打开以下链接重置密码：\n%s\n\n如果这不是您本人的操作，请忽略本邮件。\n",
+		user.Username, int(ttl.Minutes()), withToken(config.Config.Account.ResetURL, raw))
+	if err := s.send(ctx, user.Email, "重置密码", body); err != nil {
+		appCtx.LogError("重置密码邮件发送失败", zap.Uint("user_id", user.ID), zap.Error(err))
+		return nil
+	}
+	appCtx.LogInfo("已发送重置密码邮件", zap.Uint("user_id", user.ID))
+	return nil
+}
+
+// ResetPassword 校验重置令牌并设置新密码，成功后吊销该用户全部会话。
+// 新密码不符合策略时令牌不被消费，用户可修改后重试。
+func (s *accountService) ResetPassword(ctx context.Context, req *dtoUser.ResetPasswordRequest) error {
+	store := token.GetOneTimeStore()
+
+	subject, err := store.Peek(ctx, purposeResetPassword, req.Token)
+	if err != nil {
+		return oneTimeError(err)
+	}
+	uid, err := strconv.ParseUint(subject, 10, 64)
+	if err != nil {
+		return apperr.New(constant.AccountTokenInvalid)
+	}
+	user, err := s.userDAO.GetByID(ctx, uint(uid))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return apperr.New(constant.AccountTokenInvalid)
+		}
+		return err
+	}
+	if err := checkPasswordPolicy(req.Password, user.Username, user.Email); err != nil {
+		return err
+	}
+	// 并发提交同一令牌时只有一个请求能消费成功
+	if _, err := store.Consume(ctx, purposeResetPassword, req.Token); err != nil {
+		return oneTimeError(err)
+	}
+
+	hashed, err := hashPassword(req.Password)
+	if err != nil {
+		return apperr.New(constant.SystemError, err.Error())
+	}
+	if err := s.userDAO.UpdatePassword(ctx, user.ID, hashed); err != nil {
+		return err
+	}
+	if err := UserService.revokeAllSessions(ctx, user.ID); err != nil {
+		return err
+	}
+	recordAudit(ctx, auditPasswordReset, userTarget(user.ID), nil, nil)
+	return nil
+}
+
+// SendVerification 为当前登录用户重新发送邮箱验证邮件
+func (s *accountService) SendVerification(ctx context.Context) error {
+	user, err := s.userDAO.GetByID(ctx, CurrentSubject(ctx).UserID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return apperr.New(constant.UserNotExist)
+		}
+		return err
+	}
+	if user.EmailVerifiedAt != nil {
+		return apperr.New(constant.EmailAlreadyVerified)
+	}
+	return s.sendVerification(ctx, user)
+}
+
+// VerifyEmail 校验邮箱验证令牌，待激活的账号随之激活；令牌签发后邮箱被修改时失效
+func (s *accountService) VerifyEmail(ctx context.Context, req *dtoUser.VerifyEmailRequest) error {
+	appCtx := pkgCtx.MustGetContext(ctx)
+
+	subject, err := token.GetOneTimeStore().Consume(ctx, purposeVerifyEmail, req.Token)
+	if err != nil {
+		return oneTimeError(err)
+	}
+	uidStr, email, ok := strings.Cut(subject, ":")
+	uid, err := strconv.ParseUint(uidStr, 10, 64)
+	if !ok || err != nil {
+		return apperr.New(constant.AccountTokenInvalid)
+	}
+	if err := s.userDAO.MarkEmailVerified(ctx, uint(uid), email, time.Now()); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return apperr.New(constant.AccountTokenInvalid)
+		}
+		return err
+	}
+	appCtx.LogInfo("邮箱验证成功", zap.Uint64("user_id", uid))
+
+	// 待激活的账号验证邮箱后激活
+	user, err := s.userDAO.GetByID(ctx, uint(uid))
+	if err != nil {
+		return err
+	}
+	if user.Status == constant.UserStatusPending {
+		return UserService.transition(ctx, user, constant.UserStatusActive, "邮箱验证通过")
+	}
+	return nil
+}
+
+// sendVerification 签发邮箱验证令牌并发送邮件；令牌主体绑定用户 ID 与当前邮箱
+func (s *accountService) sendVerification(ctx context.Context, user *model.User) error {
+	ttl := time.Duration(config.Config.Account.VerifyTTL) * time.Second
+	subject := strconv.FormatUint(uint64(user.ID), 10) + ":" + user.Email
+	raw, err := token.GetOneTimeStore().Issue(ctx, purposeVerifyEmail, subject, ttl)
+	if err != nil {
+		return apperr.New(constant.SystemError, err.Error())
+	}
+	body := fmt.Sprintf("%s，您好：\n\n请打开以下链接验证您的邮箱：\n%s\n\n链接 %d 小时内有效。\n",
+		user.Username, withToken(config.Config.Account.VerifyURL, raw), int(ttl.Hours()))
+	if err := s.send(ctx, user.Email, "验证邮箱", body); err != nil {
+		return apperr.New(constant.SystemError, err.Error())
+	}
+	pkgCtx.MustGetContext(ctx).LogInfo("已发送邮箱验证邮件", zap.Uint("user_id", user.ID))
+	return nil
+}
+
+func (s *accountService) send(ctx context.Context, to, subject, body string) error {
+	return mailer.GetMailer().Send(ctx, &pkgmailer.Message{
+		To:      []string{to},
+		Subject: fmt.Sprintf("[%s] %s", config.Config.App.Name, subject),
+		Body:    body,
+	})
+}
+
+// oneTimeError 一次性令牌错误 → 业务错误
+func oneTimeError(err error) error {
+	if errors.Is(err, pkgtoken.ErrOneTimeInvalid) {
+		return apperr.New(constant.AccountTokenInvalid)
+	}
+	return apperr.New(constant.SystemError, err.Error())
+}
+
+// withToken 把令牌作为 token 查询参数追加到页面地址
+func withToken(base, raw string) string {
+	sep := "?"
+	if strings.Contains(base, "?") {
+		sep = "&"
+	}
+	return base + sep + "token=" + url.QueryEscape(raw)
+}
@@ -0,0 +1,12 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithToken(t *testing.T) {
+	assert.Equal(t, "https://app/reset?token=a.b", withToken("https://app/reset", "a.b"))
+	assert.Equal(t, "https://app/#/verify?lang=zh&token=a-_b", withToken("https://app/#/verify?lang=zh", "a-_b"))
+}
@@ -0,0 +1,281 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"github.com/liuchen/gin-craft/internal/constant"
+	"github.com/liuchen/gin-craft/internal/dao"
+	dtoUser "github.com/liuchen/gin-craft/internal/dto/user"
+	"github.com/liuchen/gin-craft/internal/model"
+	pkgCtx "github.com/liuchen/gin-craft/internal/pkg/context"
+	apperr "github.com/liuchen/gin-craft/internal/pkg/errors"
+	"github.com/liuchen/gin-craft/pkg/query"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// adminUserService 管理后台用户管理：检索、恢复、彻底删除、停用/激活与分配角色，变更均记录审计；
+// 变更只能作用于角色权限被调用方覆盖的用户
+type adminUserService struct {
+	userDAO *dao.UserDAO
+	rbacDAO *dao.RBACDAO
+	tx      *dao.TxManager
+}
+
+// NewAdminUserService 构造函数
+func NewAdminUserService() *adminUserService {
+	return &adminUserService{userDAO: dao.GetUserDAO(), rbacDAO: dao.GetRBACDAO(), tx: dao.GetTxManager()}
+}
+
+// AdminUserService 全局默认实例
+var AdminUserService = NewAdminUserService()
+
+// List 用户列表，可包含已删除用户；sort 按 dao.AdminUserQuerySchema 校验，不合法时返回参数错误
+func (s *adminUserService) List(ctx context.Context, req *dtoUser.AdminListRequest) (*dtoUser.AdminListResponse, error) {
+	lq, err := dao.AdminUserQuerySchema.Parse("", req.Sort, "")
+	if err != nil {
+		if errors.Is(err, query.ErrInvalid) {
+			return nil, apperr.New(constant.ParamError, err.Error())
+		}
+		return nil, err
+	}
+	users, err := s.userDAO.AdminList(ctx, req, lq)
+	if err != nil {
+		return nil, err
+	}
+	list := make([]dtoUser.AdminUser, 0, len(users))
+	for i := range users {
+		list = append(list, toAdminUserDTO(&users[i]))
+	}
+	return &dtoUser.AdminListResponse{List: list, Pagination: req.Pagination}, nil
+}
+
+// Detail 用户详情，包含已删除用户
+func (s *adminUserService) Detail(ctx context.Context, req *dtoUser.AdminIDRequest) (*dtoUser.AdminUser, error) {
+	u, err := s.getUnscoped(ctx, req.ID)
+	if err != nil {
+		return nil, err
+	}
+	resp := toAdminUserDTO(u)
+	return &resp, nil
+}
+
+// Restore 恢复已删除的用户，状态迁移回 active
+func (s *adminUserService) Restore(ctx context.Context, req *dtoUser.AdminStatusRequest) error {
+	u, err := s.getUnscoped(ctx, req.ID)
+	if err != nil {
+		return err
+	}
+	if u.Status != constant.UserStatusDeleted {
+		return apperr.New(constant.UserNotDeleted)
+	}
+	if err := checkRoleWithinCaller(ctx, u.Role); err != nil {
+		return err
+	}
+	return UserService.transition(ctx, u, constant.UserStatusActive, req.Reason)
+}
+
+// HardDelete 彻底删除用户及其关联数据，并吊销全部会话；不能删除自己
+func (s *adminUserService) HardDelete(ctx context.Context, req *dtoUser.AdminIDRequest) error {
+	if err := forbidSelf(ctx, req.ID); err != nil {
+		return err
+	}
+	u, err := s.getUnscoped(ctx, req.ID)
+	if err != nil {
+		return err
+	}
+	if err := checkRoleWithinCaller(ctx, u.Role); err != nil {
+		return err
+	}
+	if err := UserService.revokeAllSessions(ctx, req.ID); err != nil {
+		return err
+	}
+	err = s.tx.Do(ctx, func(ctx context.Context) error {
+		err := s.userDAO.HardDelete(ctx, req.ID)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return apperr.New(constant.UserNotExist)
+		}
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	UserService.invalidateStatus(ctx, req.ID)
+	recordAudit(ctx, auditUserHardDelete, userTarget(req.ID),
+		map[string]interface{}{"username": u.Username, "email": u.Email, "role": u.Role, "status": u.Status},
+		nil,
+	)
+	return nil
+}
+
+// Suspend 停用用户，已签发的令牌立即失效；不能停用自己
+func (s *adminUserService) Suspend(ctx context.Context, req *dtoUser.AdminStatusRequest) error {
+	if err := forbidSelf(ctx, req.ID); err != nil {
+		return err
+	}
+	u, err := s.get(ctx, req.ID)
+	if err != nil {
+		return err
+	}
+	if err := checkRoleWithinCaller(ctx, u.Role); err != nil {
+		return err
+	}
+	return UserService.transition(ctx, u, constant.UserStatusSuspended, req.Reason)
+}
+
+// Activate 激活待激活或已停用的用户
+func (s *adminUserService) Activate(ctx context.Context, req *dtoUser.AdminStatusRequest) error {
+	u, err := s.get(ctx, req.ID)
+	if err != nil {
+		return err
+	}
+	if err := checkRoleWithinCaller(ctx, u.Role); err != nil {
+		return err
+	}
+	return UserService.transition(ctx, u, constant.UserStatusActive, req.Reason)
+}
+
+// StatusLogs 账号状态变更记录
+func (s *adminUserService) StatusLogs(ctx context.Context, req *dtoUser.AdminIDRequest) ([]dtoUser.StatusLog, error) {
+	if _, err := s.getUnscoped(ctx, req.ID); err != nil {
+		return nil, err
+	}
+	logs, err := s.userDAO.ListStatusLogs(ctx, req.ID)
+	if err != nil {
+		return nil, err
+	}
+	list := make([]dtoUser.StatusLog, 0, len(logs))
+	for _, l := range logs {
+		list = append(list, dtoUser.StatusLog{
+			From:      l.From,
+			To:        l.To,
+			Reason:    l.Reason,
+			ActorID:   l.ActorID,
+			CreatedAt: l.CreatedAt,
+		})
+	}
+	return list, nil
+}
+
+// AssignRole 为用户分配角色；角色写在访问令牌中，分配后吊销其会话使新角色立即生效。
+// 调用方只能在自身权限范围内分配：新角色与用户当前角色的权限都须被调用方覆盖，
+// 否则仅持有 user:admin 即可把任意用户（包括同伙账号）提升为管理员，或降级权限更高的用户
+func (s *adminUserService) AssignRole(ctx context.Context, req *dtoUser.AdminAssignRoleRequest) error {
+	if err := forbidSelf(ctx, req.ID); err != nil {
+		return err
+	}
+	u, err := s.get(ctx, req.ID)
+	if err != nil {
+		return err
+	}
+	if _, err := s.rbacDAO.GetRoleByName(ctx, req.Role); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return apperr.New(constant.RoleNotExist)
+		}
+		return err
+	}
+	if u.Role == req.Role {
+		return nil
+	}
+	if err := checkRoleWithinCaller(ctx, u.Role, req.Role); err != nil {
+		return err
+	}
+	if err := s.userDAO.Update(ctx, req.ID, map[string]interface{}{"role": req.Role}); err != nil {
+		return apperr.New(constant.UserUpdateFailed, err.Error())
+	}
+	if err := UserService.revokeAllSessions(ctx, req.ID); err != nil {
+		return err
+	}
+	recordAudit(ctx, auditUserAssignRole, userTarget(req.ID),
+		map[string]interface{}{"role": u.Role},
+		map[string]interface{}{"role": req.Role},
+	)
+	return nil
+}
+
+// checkRoleWithinCaller 校验 roles 的全部权限都被调用方角色覆盖，
+// 避免仅持有 user:admin 的调用方操作权限更高的用户或分配更高的角色
+func checkRoleWithinCaller(ctx context.Context, roles ...string) error {
+	role, permission, err := uncoveredPermission(ctx, CurrentSubject(ctx).Role, roles...)
+	if err != nil {
+		return err
+	}
+	if permission != "" {
+		pkgCtx.MustGetContext(ctx).LogWarn("目标角色超出自身权限", zap.String("role", role), zap.String("permission", permission))
+		return apperr.New(constant.Forbidden, "不能操作或分配超出自身权限的角色")
+	}
+	return nil
+}
+
+// uncoveredPermission 返回 roles 中第一个未被 callerRole 覆盖的权限及其所属角色，全部覆盖时 permission 为空
+func uncoveredPermission(ctx context.Context, callerRole string, roles ...string) (role, permission string, err error) {
+	granted, err := RBACService.RolePermissions(ctx, callerRole)
+	if err != nil {
+		return "", "", err
+	}
+	for _, role := range roles {
+		required, err := RBACService.RolePermissions(ctx, role)
+		if err != nil {
+			return "", "", err
+		}
+		for _, p := range required {
+			if !MatchPermission(granted, p) {
+				return role, p, nil
+			}
+		}
+	}
+	return "", "", nil
+}
+
+func (s *adminUserService) get(ctx context.Context, id uint) (*model.User, error) {
+	u, err := s.userDAO.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperr.New(constant.UserNotExist)
+		}
+		return nil, err
+	}
+	return u, nil
+}
+
+func (s *adminUserService) getUnscoped(ctx context.Context, id uint) (*model.User, error) {
+	u, err := s.userDAO.GetByIDUnscoped(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperr.New(constant.UserNotExist)
+		}
+		return nil, err
+	}
+	return u, nil
+}
+
+// forbidSelf 禁止管理员对自己执行禁用、删除、改角色等操作，避免把自己锁在后台之外
+func forbidSelf(ctx context.Context, id uint) error {
+	if CurrentSubject(ctx).UserID == id {
+		return apperr.New(constant.Forbidden, "不能对自己执行该操作")
+	}
+	return nil
+}
+
+func toAdminUserDTO(u *model.User) dtoUser.AdminUser {
+	resp := dtoUser.AdminUser{
+		User: dtoUser.User{
+			ID:            u.ID,
+			Username:      u.Username,
+			Email:         u.Email,
+			EmailVerified: u.EmailVerifiedAt != nil,
+			CreatedAt:     u.CreatedAt,
+			UpdatedAt:     u.UpdatedAt,
+		},
+		Role:            u.Role,
+		Status:          u.Status,
+		StatusReason:    u.StatusReason,
+		StatusChangedAt: u.StatusChangedAt,
+	}
+	if u.DeletedAt.Valid {
+		deletedAt := u.DeletedAt.Time
+		resp.DeletedAt = &deletedAt
+	}
+	return resp
+}
@@ -0,0 +1,92 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/liuchen/gin-craft/internal/constant"
+	dtoRBAC "github.com/liuchen/gin-craft/internal/dto/rbac"
+	dtoUser "github.com/liuchen/gin-craft/internal/dto/user"
+	"github.com/liuchen/gin-craft/internal/model"
+	"github.com/liuchen/gin-craft/internal/pkg/database"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// createUserManager 创建只持有 user:admin 的 user-manager 角色及其用户，以及一个拥有全部权限的管理员
+func createUserManager(t *testing.T) (manager, admin *model.User) {
+	t.Helper()
+	require.NoError(t, RBACService.EnsureDefaults(newTestContext(t, nil)))
+	admin = createTestUser(t, "admin", "Passw0rd!")
+	require.NoError(t, database.GetDB().Model(admin).Update("role", constant.RoleAdmin).Error)
+	admin.Role = constant.RoleAdmin
+
+	ctx := newTestContext(t, admin)
+	_, err := RBACService.CreateRole(ctx, &dtoRBAC.CreateRoleRequest{Name: "user-manager"})
+	require.NoError(t, err)
+	require.NoError(t, RBACService.Grant(ctx, &dtoRBAC.BindRequest{Role: "user-manager", Permissions: []string{constant.PermUserAdmin}}))
+
+	manager = createTestUser(t, "manager", "Passw0rd!")
+	require.NoError(t, database.GetDB().Model(manager).Update("role", "user-manager").Error)
+	manager.Role = "user-manager"
+	return manager, admin
+}
+
+func TestAssignRoleWithinCallerPermissions(t *testing.T) {
+	setupServiceEnv(t)
+	manager, admin := createUserManager(t)
+	target := createTestUser(t, "target", "Passw0rd!")
+
+	// 只持有 user:admin 不能授予或撤销更大的角色
+	managerCtx := newTestContext(t, manager)
+	err := AdminUserService.AssignRole(managerCtx, &dtoUser.AdminAssignRoleRequest{ID: target.ID, Role: constant.RoleAdmin})
+	assertAppErrorCode(t, constant.Forbidden, err)
+	err = AdminUserService.AssignRole(managerCtx, &dtoUser.AdminAssignRoleRequest{ID: admin.ID, Role: constant.RoleUser})
+	assertAppErrorCode(t, constant.Forbidden, err)
+
+	// 权限范围内的角色可以分配
+	require.NoError(t, AdminUserService.AssignRole(managerCtx, &dtoUser.AdminAssignRoleRequest{ID: target.ID, Role: "user-manager"}))
+
+	// 拥有全部权限的管理员不受限制
+	adminCtx := newTestContext(t, admin)
+	require.NoError(t, AdminUserService.AssignRole(adminCtx, &dtoUser.AdminAssignRoleRequest{ID: target.ID, Role: constant.RoleAdmin}))
+
+	var role string
+	require.NoError(t, database.GetDB().Model(&model.User{}).Where("id = ?", target.ID).Pluck("role", &role).Error)
+	assert.Equal(t, constant.RoleAdmin, role)
+}
+
+func TestAdminActionsWithinCallerPermissions(t *testing.T) {
+	setupServiceEnv(t)
+	manager, admin := createUserManager(t)
+	target := createTestUser(t, "status_target", "Passw0rd!")
+	ctx := newTestContext(t, manager)
+
+	// 只持有 user:admin 不能停用、删除拥有 * 的管理员
+	err := AdminUserService.Suspend(ctx, &dtoUser.AdminStatusRequest{ID: admin.ID, Reason: "takeover"})
+	assertAppErrorCode(t, constant.Forbidden, err)
+	err = AdminUserService.HardDelete(ctx, &dtoUser.AdminIDRequest{ID: admin.ID})
+	assertAppErrorCode(t, constant.Forbidden, err)
+
+	var status string
+	require.NoError(t, database.GetDB().Model(&model.User{}).Where("id = ?", admin.ID).Pluck("status", &status).Error)
+	assert.Equal(t, constant.UserStatusActive, status)
+
+	// 权限范围内的用户照常管理
+	require.NoError(t, AdminUserService.Suspend(ctx, &dtoUser.AdminStatusRequest{ID: target.ID}))
+	require.NoError(t, AdminUserService.Activate(ctx, &dtoUser.AdminStatusRequest{ID: target.ID}))
+}
+
+func TestAdminListKeywordIsLiteral(t *testing.T) {
+	setupServiceEnv(t)
+	_, admin := createUserManager(t)
+	createTestUser(t, "100%_off", "Passw0rd!")
+	createTestUser(t, "plain", "Passw0rd!")
+	ctx := newTestContext(t, admin)
+
+	// % 与 _ 按字面匹配，不作为通配符
+	for keyword, want := range map[string]int64{"%": 1, "_": 1, "0%_o": 1, "!": 0} {
+		resp, err := AdminUserService.List(ctx, &dtoUser.AdminListRequest{Keyword: keyword})
+		require.NoError(t, err)
+		assert.Equal(t, want, resp.Total, keyword)
+	}
+}
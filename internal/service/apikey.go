@@ -0,0 +1,250 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/liuchen/gin-craft/internal/constant"
+	"github.com/liuchen/gin-craft/internal/dao"
+	dtoAPIKey "github.com/liuchen/gin-craft/internal/dto/apikey"
+	"github.com/liuchen/gin-craft/internal/model"
+	pkgCtx "github.com/liuchen/gin-craft/internal/pkg/context"
+	apperr "github.com/liuchen/gin-craft/internal/pkg/errors"
+	"github.com/liuchen/gin-craft/internal/pkg/signature"
+	"github.com/liuchen/gin-craft/pkg/apikey"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// lastUsedInterval 最近使用时间的最小写入间隔，避免每个请求都写库
+const lastUsedInterval = time.Minute
+
+// apiKeyService API 密钥服务
+type apiKeyService struct {
+	apiKeyDAO *dao.APIKeyDAO
+	userDAO   *dao.UserDAO
+}
+
+// NewAPIKeyService 构造函数
+func NewAPIKeyService() *apiKeyService {
+	return &apiKeyService{apiKeyDAO: dao.GetAPIKeyDAO(), userDAO: dao.GetUserDAO()}
+}
+
+// APIKeyService 全局默认实例
+var APIKeyService = NewAPIKeyService()
+
+// Authenticate 校验请求携带的密钥，返回对应记录；密钥无效、已吊销或已过期时返回 constant.Unauthorized
+func (s *apiKeyService) Authenticate(ctx context.Context, raw string) (*model.APIKey, error) {
+	appCtx := pkgCtx.MustGetContext(ctx)
+
+	keyID, err := apikey.Parse(raw)
+	if err != nil {
+		return nil, apperr.New(constant.Unauthorized, "无效的API密钥")
+	}
+	key, err := s.lookup(ctx, keyID)
+	if err != nil {
+		return nil, err
+	}
+	if !apikey.Verify(raw, key.KeyHash) {
+		appCtx.LogWarn("API密钥校验失败", zap.String("api_key_id", keyID))
+		return nil, apperr.New(constant.Unauthorized, "无效的API密钥")
+	}
+	if err := s.activate(ctx, key); err != nil {
+		return nil, err
+	}
+	return key, nil
+}
+
+// AuthenticateSigned 校验签名请求：按公开 ID 查找密钥，以该密钥的签名密钥（服务端密钥与摘要派生）交给 verify 校验签名。
+// 请求只携带密钥 ID，密钥本身不在网络上传输；数据库中的摘要不能直接用于签名。
+func (s *apiKeyService) AuthenticateSigned(ctx context.Context, keyID string, verify func(secret []byte) error) (*model.APIKey, error) {
+	key, err := s.lookup(ctx, keyID)
+	if err != nil {
+		return nil, err
+	}
+	if err := verify([]byte(signature.SigningSecret(key.KeyHash))); err != nil {
+		return nil, err
+	}
+	if err := s.activate(ctx, key); err != nil {
+		return nil, err
+	}
+	return key, nil
+}
+
+// lookup 按公开 ID 查找未吊销的密钥
+func (s *apiKeyService) lookup(ctx context.Context, keyID string) (*model.APIKey, error) {
+	key, err := s.apiKeyDAO.GetByKeyID(ctx, keyID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperr.New(constant.Unauthorized, "无效的API密钥")
+		}
+		return nil, err
+	}
+	return key, nil
+}
+
+// activate 校验过期时间并记录最近使用时间
+func (s *apiKeyService) activate(ctx context.Context, key *model.APIKey) error {
+	now := time.Now()
+	if key.Expired(now) {
+		return apperr.New(constant.Unauthorized, "API密钥已过期")
+	}
+	if key.LastUsedAt == nil || now.Sub(*key.LastUsedAt) >= lastUsedInterval {
+		if err := s.apiKeyDAO.TouchLastUsed(ctx, key.ID, now); err != nil {
+			pkgCtx.MustGetContext(ctx).LogWarn("更新API密钥使用时间失败", zap.String("api_key_id", key.KeyID), zap.Error(err))
+		} else {
+			key.LastUsedAt = &now
+		}
+	}
+	return nil
+}
+
+// HasScopes 判断密钥是否拥有全部授权范围
+func (s *apiKeyService) HasScopes(key *model.APIKey, scopes ...string) bool {
+	granted := key.ScopeList()
+	for _, scope := range scopes {
+		if !MatchPermission(granted, scope) {
+			return false
+		}
+	}
+	return true
+}
+
+// Create 创建密钥，明文与签名密钥仅在响应中返回一次
+func (s *apiKeyService) Create(ctx context.Context, req *dtoAPIKey.CreateRequest) (*dtoAPIKey.CreateResponse, error) {
+	if _, err := s.userDAO.GetByID(ctx, req.OwnerID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperr.New(constant.UserNotExist)
+		}
+		return nil, err
+	}
+	scopes, err := normalizeScopes(req.Scopes)
+	if err != nil {
+		return nil, err
+	}
+
+	generated, err := apikey.Generate()
+	if err != nil {
+		return nil, apperr.New(constant.SystemError, err.Error())
+	}
+	key := &model.APIKey{
+		KeyID:     generated.ID,
+		KeyHash:   generated.Hash,
+		Name:      req.Name,
+		OwnerID:   req.OwnerID,
+		Scopes:    scopes,
+		ExpiresAt: req.ExpiresAt,
+	}
+	if err := s.apiKeyDAO.Create(ctx, key); err != nil {
+		return nil, err
+	}
+	recordAudit(ctx, auditAPIKeyCreate, apiKeyTarget(key.ID), nil, map[string]interface{}{
+		"name": key.Name, "owner_id": key.OwnerID, "scopes": key.Scopes, "expires_at": key.ExpiresAt,
+	})
+	return &dtoAPIKey.CreateResponse{
+		APIKey:        toAPIKeyDTO(key),
+		Key:           generated.Raw,
+		SigningSecret: signature.SigningSecret(generated.Hash),
+	}, nil
+}
+
+// List 获取密钥列表
+func (s *apiKeyService) List(ctx context.Context, req *dtoAPIKey.ListRequest) (*dtoAPIKey.ListResponse, error) {
+	keys, err := s.apiKeyDAO.GetList(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	list := make([]dtoAPIKey.APIKey, 0, len(keys))
+	for i := range keys {
+		list = append(list, toAPIKeyDTO(&keys[i]))
+	}
+	return &dtoAPIKey.ListResponse{List: list, Pagination: req.Pagination}, nil
+}
+
+// Update 更新密钥名称、授权范围与过期时间
+func (s *apiKeyService) Update(ctx context.Context, req *dtoAPIKey.UpdateRequest) error {
+	key, err := s.apiKeyDAO.GetByID(ctx, req.ID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return apperr.New(constant.APIKeyNotExist)
+		}
+		return err
+	}
+
+	updates := map[string]interface{}{}
+	if req.Name != "" {
+		updates["name"] = req.Name
+	}
+	if req.Scopes != nil {
+		scopes, err := normalizeScopes(req.Scopes)
+		if err != nil {
+			return err
+		}
+		updates["scopes"] = scopes
+	}
+	if req.ExpiresAt != nil {
+		updates["expires_at"] = req.ExpiresAt
+	}
+	if len(updates) == 0 {
+		return nil
+	}
+	if err := s.apiKeyDAO.Update(ctx, req.ID, updates); err != nil {
+		return err
+	}
+	recordAudit(ctx, auditAPIKeyUpdate, apiKeyTarget(req.ID),
+		map[string]interface{}{"name": key.Name, "scopes": key.Scopes, "expires_at": key.ExpiresAt},
+		updates,
+	)
+	return nil
+}
+
+// Delete 吊销密钥，立即生效
+func (s *apiKeyService) Delete(ctx context.Context, req *dtoAPIKey.DeleteRequest) error {
+	if err := s.apiKeyDAO.Delete(ctx, req.ID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return apperr.New(constant.APIKeyNotExist)
+		}
+		return err
+	}
+	recordAudit(ctx, auditAPIKeyDelete, apiKeyTarget(req.ID), nil, nil)
+	return nil
+}
+
+// apiKeyTarget 审计目标标识
+func apiKeyTarget(id uint) string {
+	return "apikey:" + strconv.FormatUint(uint64(id), 10)
+}
+
+// normalizeScopes 校验授权范围格式并去重，返回逗号分隔的存储形式
+func normalizeScopes(scopes []string) (string, error) {
+	seen := make(map[string]struct{}, len(scopes))
+	list := make([]string, 0, len(scopes))
+	for _, scope := range scopes {
+		scope = strings.TrimSpace(scope)
+		if !validPermissionCode(scope) || strings.Contains(scope, ",") {
+			return "", apperr.New(constant.ParamError, "授权范围格式应为 资源:操作")
+		}
+		if _, dup := seen[scope]; dup {
+			continue
+		}
+		seen[scope] = struct{}{}
+		list = append(list, scope)
+	}
+	return strings.Join(list, ","), nil
+}
+
+func toAPIKeyDTO(k *model.APIKey) dtoAPIKey.APIKey {
+	return dtoAPIKey.APIKey{
+		ID:         k.ID,
+		KeyID:      k.KeyID,
+		Name:       k.Name,
+		OwnerID:    k.OwnerID,
+		Scopes:     k.ScopeList(),
+		ExpiresAt:  k.ExpiresAt,
+		LastUsedAt: k.LastUsedAt,
+		CreatedAt:  k.CreatedAt,
+	}
+}
@@ -0,0 +1,53 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/liuchen/gin-craft/internal/constant"
+	dtoAPIKey "github.com/liuchen/gin-craft/internal/dto/apikey"
+	"github.com/liuchen/gin-craft/internal/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalizeScopes(t *testing.T) {
+	scopes, err := normalizeScopes([]string{"data:read", " data:write ", "data:read"})
+	require.NoError(t, err)
+	assert.Equal(t, "data:read,data:write", scopes)
+
+	_, err = normalizeScopes([]string{"data"})
+	assert.Error(t, err)
+}
+
+func TestAPIKeyHasScopes(t *testing.T) {
+	key := &model.APIKey{Scopes: "data:*,report:read"}
+	assert.True(t, APIKeyService.HasScopes(key))
+	assert.True(t, APIKeyService.HasScopes(key, "data:write", "report:read"))
+	assert.False(t, APIKeyService.HasScopes(key, "report:write"))
+	assert.False(t, APIKeyService.HasScopes(&model.APIKey{}, "data:read"))
+}
+
+func TestAPIKeyAuthenticate(t *testing.T) {
+	setupServiceEnv(t)
+	owner := createTestUser(t, "apikey_owner", "Passw0rd!")
+	ctx := newTestContext(t, nil)
+
+	created, err := APIKeyService.Create(ctx, &dtoAPIKey.CreateRequest{Name: "sync", OwnerID: owner.ID, Scopes: []string{"data:read"}})
+	require.NoError(t, err)
+
+	key, err := APIKeyService.Authenticate(ctx, created.Key)
+	require.NoError(t, err)
+	assert.Equal(t, created.KeyID, key.KeyID)
+	assert.True(t, APIKeyService.HasScopes(key, "data:read"))
+
+	// 公开 ID 正确但密钥主体不符
+	_, err = APIKeyService.Authenticate(ctx, created.Key+"x")
+	assertAppErrorCode(t, constant.Unauthorized, err)
+	_, err = APIKeyService.Authenticate(ctx, "not-a-key")
+	assertAppErrorCode(t, constant.Unauthorized, err)
+
+	// 吊销后立即失效
+	require.NoError(t, APIKeyService.Delete(ctx, &dtoAPIKey.DeleteRequest{ID: created.ID}))
+	_, err = APIKeyService.Authenticate(ctx, created.Key)
+	assertAppErrorCode(t, constant.Unauthorized, err)
+}
@@ -0,0 +1,21 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/liuchen/gin-craft/internal/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAuditFields(t *testing.T) {
+	updates := map[string]interface{}{"username": "john", "email_verified_at": nil}
+	assert.Equal(t, map[string]interface{}{"username": "john"}, auditFields(updates, "username", "email"))
+}
+
+func TestToAuditDTO(t *testing.T) {
+	l := toAuditDTO(&model.AuditLog{Action: auditUserSuspend, Changes: `{"status":{"before":"active","after":"disabled"}}`})
+	assert.JSONEq(t, `{"status":{"before":"active","after":"disabled"}}`, string(l.Changes))
+
+	l = toAuditDTO(&model.AuditLog{Action: auditUserSuspend})
+	assert.JSONEq(t, `{}`, string(l.Changes))
+}
@@ -0,0 +1,186 @@
+package service
+
+import (
+	"context"
+	"math"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/liuchen/gin-craft/internal/constant"
+	dtoLockout "github.com/liuchen/gin-craft/internal/dto/lockout"
+	pkgCtx "github.com/liuchen/gin-craft/internal/pkg/context"
+	apperr "github.com/liuchen/gin-craft/internal/pkg/errors"
+	"github.com/liuchen/gin-craft/internal/pkg/hasher"
+	"github.com/liuchen/gin-craft/internal/pkg/lockout"
+	pkglockout "github.com/liuchen/gin-craft/pkg/lockout"
+	"go.uber.org/zap"
+)
+
+// 锁定维度，对应 dto Lockout.Type
+const (
+	lockoutAccount = "account"
+	lockoutIP      = "ip"
+)
+
+// lockoutService 登录防暴力破解：按用户名与客户端 IP 分别计数，连续失败后指数退避锁定
+type lockoutService struct{}
+
+// NewLockoutService 构造函数
+func NewLockoutService() *lockoutService {
+	return &lockoutService{}
+}
+
+// LockoutService 全局默认实例
+var LockoutService = NewLockoutService()
+
+// List 查询锁定状态；未指定用户名与 IP 时列出全部锁定中的条目（管理员操作）
+func (s *lockoutService) List(ctx context.Context, req *dtoLockout.ListRequest) (*dtoLockout.ListResponse, error) {
+	resp := &dtoLockout.ListResponse{List: []dtoLockout.Lockout{}}
+	if req.Username == "" && req.IP == "" {
+		for _, typ := range []string{lockoutAccount, lockoutIP} {
+			list, err := s.guards()[typ].Locked(ctx)
+			if err != nil {
+				return nil, apperr.New(constant.SystemError, err.Error())
+			}
+			for _, st := range list {
+				resp.List = append(resp.List, toLockoutDTO(typ, st))
+			}
+		}
+		return resp, nil
+	}
+
+	if req.Username != "" {
+		st, err := lockout.GetAccountGuard().Check(ctx, accountKey(req.Username))
+		if err != nil {
+			return nil, apperr.New(constant.SystemError, err.Error())
+		}
+		resp.List = append(resp.List, toLockoutDTO(lockoutAccount, st))
+	}
+	if req.IP != "" {
+		st, err := lockout.GetIPGuard().Check(ctx, req.IP)
+		if err != nil {
+			return nil, apperr.New(constant.SystemError, err.Error())
+		}
+		resp.List = append(resp.List, toLockoutDTO(lockoutIP, st))
+	}
+	return resp, nil
+}
+
+// Clear 清除用户名和/或 IP 的失败计数与锁定（管理员操作）
+func (s *lockoutService) Clear(ctx context.Context, req *dtoLockout.ClearRequest) error {
+	if req.Username == "" && req.IP == "" {
+		return apperr.New(constant.ParamError, "username 与 ip 至少填写一项")
+	}
+	if req.Username != "" {
+		if err := lockout.GetAccountGuard().Reset(ctx, accountKey(req.Username)); err != nil {
+			return apperr.New(constant.SystemError, err.Error())
+		}
+	}
+	if req.IP != "" {
+		if err := lockout.GetIPGuard().Reset(ctx, req.IP); err != nil {
+			return apperr.New(constant.SystemError, err.Error())
+		}
+	}
+	recordAudit(ctx, auditLockoutClear, "lockout", nil, map[string]interface{}{"username": req.Username, "ip": req.IP})
+	return nil
+}
+
+// check 登录前检查用户名与 IP 是否处于锁定中。计数器故障时放行，避免 Redis 抖动导致无法登录
+func (s *lockoutService) check(ctx context.Context, username, ip string) error {
+	appCtx := pkgCtx.MustGetContext(ctx)
+	for typ, key := range map[string]string{lockoutAccount: accountKey(username), lockoutIP: ip} {
+		if key == "" {
+			continue
+		}
+		st, err := s.guards()[typ].Check(ctx, key)
+		if err != nil {
+			appCtx.LogWarn("登录锁定检查失败，放行请求", zap.Error(err))
+			continue
+		}
+		if st.Locked() {
+			appCtx.LogWarn("登录被锁定", zap.String("type", typ), zap.String("key", key), zap.Duration("retry_after", st.RetryAfter))
+			return lockedError(st.RetryAfter)
+		}
+	}
+	return nil
+}
+
+// fail 记录一次登录失败，返回统一的凭证错误；本次失败触发锁定时返回锁定错误
+func (s *lockoutService) fail(ctx context.Context, username, ip string) error {
+	appCtx := pkgCtx.MustGetContext(ctx)
+	var retryAfter time.Duration
+	for typ, key := range map[string]string{lockoutAccount: accountKey(username), lockoutIP: ip} {
+		if key == "" {
+			continue
+		}
+		st, err := s.guards()[typ].Fail(ctx, key)
+		if err != nil {
+			appCtx.LogWarn("登录失败计数失败", zap.Error(err))
+			continue
+		}
+		if st.RetryAfter > retryAfter {
+			retryAfter = st.RetryAfter
+		}
+	}
+	appCtx.LogWarn("登录失败", zap.String("username", username))
+	if retryAfter > 0 {
+		return lockedError(retryAfter)
+	}
+	return apperr.New(constant.InvalidCredentials)
+}
+
+// succeed 登录成功后清除用户名维度的计数；IP 维度不清除，避免攻击者穿插自己的正常登录重置计数
+func (s *lockoutService) succeed(ctx context.Context, username string) {
+	if err := lockout.GetAccountGuard().Reset(ctx, accountKey(username)); err != nil {
+		pkgCtx.MustGetContext(ctx).LogWarn("清除登录失败计数失败", zap.Error(err))
+	}
+}
+
+func (s *lockoutService) guards() map[string]*pkglockout.Guard {
+	return map[string]*pkglockout.Guard{
+		lockoutAccount: lockout.GetAccountGuard(),
+		lockoutIP:      lockout.GetIPGuard(),
+	}
+}
+
+// accountKey 用户名维度的计数 key，忽略大小写避免绕过
+func accountKey(username string) string {
+	return strings.ToLower(strings.TrimSpace(username))
+}
+
+func lockedError(retryAfter time.Duration) error {
+	return apperr.Newf(constant.AccountLocked, "请 %d 秒后重试", ceilSeconds(retryAfter))
+}
+
+func ceilSeconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
+
+func toLockoutDTO(typ string, st pkglockout.Status) dtoLockout.Lockout {
+	l := dtoLockout.Lockout{
+		Type:       typ,
+		Key:        st.Key,
+		Failures:   st.Failures,
+		Locked:     st.Locked(),
+		RetryAfter: ceilSeconds(st.RetryAfter),
+	}
+	if l.Locked {
+		until := time.Now().Add(st.RetryAfter)
+		l.LockedUntil = &until
+	}
+	return l
+}
+
+var (
+	dummyHash     string
+	dummyHashOnce sync.Once
+)
+
+// burnPasswordCheck 用户不存在时仍执行一次 bcrypt 比对，使响应耗时与密码错误一致，避免按耗时探测用户名
+func burnPasswordCheck(password string) {
+	dummyHashOnce.Do(func() {
+		dummyHash, _ = hashPassword("gin-craft-dummy-password")
+	})
+	_, _ = hasher.GetHasher().Verify(password, dummyHash)
+}
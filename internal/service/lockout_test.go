@@ -0,0 +1,31 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	apperr "github.com/liuchen/gin-craft/internal/pkg/errors"
+	pkglockout "github.com/liuchen/gin-craft/pkg/lockout"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLockedError(t *testing.T) {
+	appErr, ok := apperr.GetAppError(lockedError(1500 * time.Millisecond))
+	require.True(t, ok)
+	assert.Equal(t, "请 2 秒后重试", appErr.Detail)
+
+	assert.Equal(t, "john_doe", accountKey(" John_Doe "))
+}
+
+func TestToLockoutDTO(t *testing.T) {
+	l := toLockoutDTO(lockoutAccount, pkglockout.Status{Key: "john", Failures: 3})
+	assert.False(t, l.Locked)
+	assert.Nil(t, l.LockedUntil)
+
+	l = toLockoutDTO(lockoutIP, pkglockout.Status{Key: "1.2.3.4", Failures: 20, RetryAfter: time.Minute})
+	assert.True(t, l.Locked)
+	assert.Equal(t, 60, l.RetryAfter)
+	require.NotNil(t, l.LockedUntil)
+	assert.WithinDuration(t, time.Now().Add(time.Minute), *l.LockedUntil, time.Second)
+}
@@ -0,0 +1,310 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/liuchen/gin-craft/internal/constant"
+	"github.com/liuchen/gin-craft/internal/dao"
+	dtoUser "github.com/liuchen/gin-craft/internal/dto/user"
+	"github.com/liuchen/gin-craft/internal/model"
+	"github.com/liuchen/gin-craft/internal/pkg/config"
+	pkgCtx "github.com/liuchen/gin-craft/internal/pkg/context"
+	apperr "github.com/liuchen/gin-craft/internal/pkg/errors"
+	"github.com/liuchen/gin-craft/internal/pkg/hasher"
+	"github.com/liuchen/gin-craft/internal/pkg/redis"
+	pkgoidc "github.com/liuchen/gin-craft/pkg/oidc"
+	"github.com/liuchen/gin-craft/pkg/totp"
+	goredis "github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+const (
+	// mfaPendingPrefix 两阶段登录待完成令牌，完整 key 为 auth:mfa:<sha256(token)>
+	mfaPendingPrefix = "auth:mfa:"
+	// mfaMaxAttempts 单个 mfa_token 允许的验证码尝试次数
+	mfaMaxAttempts = 5
+	// mfaSkew 允许前后各一个时间步的时钟偏差
+	mfaSkew = 1
+	// recoveryCodeCount 每次开启两步验证生成的恢复码数量
+	recoveryCodeCount = 10
+)
+
+// mfaService TOTP 两步验证服务
+type mfaService struct {
+	mfaDAO  *dao.MFADAO
+	userDAO *dao.UserDAO
+	tx      *dao.TxManager
+}
+
+// NewMFAService 构造函数
+func NewMFAService() *mfaService {
+	return &mfaService{mfaDAO: dao.GetMFADAO(), userDAO: dao.GetUserDAO(), tx: dao.GetTxManager()}
+}
+
+// MFAService 全局默认实例
+var MFAService = NewMFAService()
+
+// Enroll 校验当前密码后为当前用户生成新密钥，返回 otpauth:// 地址；验证通过前不生效，可重复发起
+func (s *mfaService) Enroll(ctx context.Context, req *dtoUser.MFAEnrollRequest) (*dtoUser.MFAEnrollResponse, error) {
+	user, err := s.confirmCurrentUser(ctx, req.Password)
+	if err != nil {
+		return nil, err
+	}
+	m, err := s.mfaDAO.GetByUserID(ctx, user.ID)
+	switch {
+	case err == nil && m.Enabled:
+		return nil, apperr.New(constant.MFAAlreadyEnabled)
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		m = &model.UserMFA{UserID: user.ID}
+	case err != nil:
+		return nil, err
+	}
+
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		return nil, apperr.New(constant.SystemError, err.Error())
+	}
+	m.Secret = secret
+	if err := s.mfaDAO.Save(ctx, m); err != nil {
+		return nil, err
+	}
+	return &dtoUser.MFAEnrollResponse{
+		Secret: secret,
+		URI:    totp.URI(config.Config.MFA.Issuer, user.Username, secret),
+	}, nil
+}
+
+// Enable 校验当前密码与首个验证码后开启两步验证，返回一次性恢复码
+func (s *mfaService) Enable(ctx context.Context, req *dtoUser.MFACodeRequest) (*dtoUser.MFARecoveryCodesResponse, error) {
+	user, err := s.confirmCurrentUser(ctx, req.Password)
+	if err != nil {
+		return nil, err
+	}
+	uid := user.ID
+
+	m, err := s.mfaDAO.GetByUserID(ctx, uid)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperr.New(constant.MFANotEnabled, "请先发起绑定")
+		}
+		return nil, err
+	}
+	if m.Enabled {
+		return nil, apperr.New(constant.MFAAlreadyEnabled)
+	}
+	step, ok := totp.Validate(m.Secret, req.Code, time.Now(), mfaSkew)
+	if !ok {
+		return nil, apperr.New(constant.MFACodeInvalid)
+	}
+
+	codes, err := totp.GenerateRecoveryCodes(recoveryCodeCount)
+	if err != nil {
+		return nil, apperr.New(constant.SystemError, err.Error())
+	}
+	records := make([]model.MFARecoveryCode, 0, len(codes))
+	for _, c := range codes {
+		h, err := hashPassword(c)
+		if err != nil {
+			return nil, apperr.New(constant.SystemError, err.Error())
+		}
+		lookup, _ := totp.RecoveryCodeLookup(c)
+		records = append(records, model.MFARecoveryCode{Lookup: lookup, CodeHash: h})
+	}
+	err = s.tx.Do(ctx, func(ctx context.Context) error {
+		err := s.mfaDAO.Enable(ctx, uid, step, records)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return apperr.New(constant.MFANotEnabled, "请先发起绑定")
+		}
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	recordAudit(ctx, auditMFAEnable, userTarget(uid), map[string]interface{}{"enabled": false}, map[string]interface{}{"enabled": true})
+	return &dtoUser.MFARecoveryCodesResponse{RecoveryCodes: codes}, nil
+}
+
+// Disable 校验当前密码与验证码（或恢复码）后关闭两步验证
+func (s *mfaService) Disable(ctx context.Context, req *dtoUser.MFACodeRequest) error {
+	user, err := s.confirmCurrentUser(ctx, req.Password)
+	if err != nil {
+		return err
+	}
+	uid := user.ID
+
+	m, err := s.enabledMFA(ctx, uid)
+	if err != nil {
+		return err
+	}
+	if err := s.verify(ctx, m, req.Code); err != nil {
+		return err
+	}
+	if err := s.tx.Do(ctx, func(ctx context.Context) error { return s.mfaDAO.Delete(ctx, uid) }); err != nil {
+		return err
+	}
+	recordAudit(ctx, auditMFADisable, userTarget(uid), map[string]interface{}{"enabled": true}, map[string]interface{}{"enabled": false})
+	return nil
+}
+
+// challenge 用户已开启两步验证时签发 mfa_token 作为登录第一步的结果；未开启时返回 nil
+func (s *mfaService) challenge(ctx context.Context, user *model.User) (*dtoUser.LoginResponse, error) {
+	m, err := s.mfaDAO.GetByUserID(ctx, user.ID)
+	if errors.Is(err, gorm.ErrRecordNotFound) || (err == nil && !m.Enabled) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	raw, err := pkgoidc.RandomString(32)
+	if err != nil {
+		return nil, apperr.New(constant.SystemError, err.Error())
+	}
+	ttl := time.Duration(config.Config.MFA.PendingTTL) * time.Second
+	uid := strconv.FormatUint(uint64(user.ID), 10)
+	if err := redis.GetRedisClient().Set(ctx, mfaPendingKey(raw), uid, ttl); err != nil {
+		return nil, apperr.New(constant.SystemError, err.Error())
+	}
+	return &dtoUser.LoginResponse{
+		MFARequired: true,
+		MFAToken:    raw,
+		ExpiresAt:   time.Now().Add(ttl),
+	}, nil
+}
+
+// Login 两阶段登录第二步：校验 mfa_token 与验证码（或恢复码），通过后签发令牌
+func (s *mfaService) Login(ctx context.Context, req *dtoUser.MFALoginRequest) (*dtoUser.LoginResponse, error) {
+	appCtx := pkgCtx.MustGetContext(ctx)
+	rdb := redis.GetRedisClient()
+	key := mfaPendingKey(req.MFAToken)
+
+	uidStr, err := rdb.Get(ctx, key)
+	if err != nil {
+		if errors.Is(err, goredis.Nil) {
+			return nil, apperr.New(constant.MFATokenInvalid)
+		}
+		return nil, apperr.New(constant.SystemError, err.Error())
+	}
+	uid, err := strconv.ParseUint(uidStr, 10, 64)
+	if err != nil {
+		return nil, apperr.New(constant.MFATokenInvalid)
+	}
+
+	// 限制单个 mfa_token 的尝试次数，防止在有效期内穷举 6 位验证码
+	attempts, err := rdb.Increment(ctx, key+":attempts")
+	if err != nil {
+		return nil, apperr.New(constant.SystemError, err.Error())
+	}
+	if attempts == 1 {
+		_ = rdb.Expire(ctx, key+":attempts", time.Duration(config.Config.MFA.PendingTTL)*time.Second)
+	}
+	if attempts > mfaMaxAttempts {
+		_ = rdb.Del(ctx, key, key+":attempts")
+		return nil, apperr.New(constant.MFATokenInvalid)
+	}
+
+	m, err := s.enabledMFA(ctx, uint(uid))
+	if err != nil {
+		return nil, err
+	}
+	if err := s.verify(ctx, m, req.Code); err != nil {
+		appCtx.LogWarn("两步验证失败", zap.Uint64("user_id", uid), zap.Int64("attempts", attempts))
+		return nil, err
+	}
+	_ = rdb.Del(ctx, key, key+":attempts")
+
+	user, err := s.userDAO.GetByID(ctx, uint(uid))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperr.New(constant.UserNotExist)
+		}
+		return nil, err
+	}
+	resp, err := UserService.startSession(ctx, user)
+	if err != nil {
+		return nil, err
+	}
+	appCtx.LogInfo("用户登录成功（两步验证）", zap.String("username", user.Username))
+	return resp, nil
+}
+
+// verify 校验 TOTP 验证码（拒绝重放），或消耗一个恢复码
+func (s *mfaService) verify(ctx context.Context, m *model.UserMFA, code string) error {
+	code = strings.TrimSpace(code)
+	if step, ok := totp.Validate(m.Secret, code, time.Now(), mfaSkew); ok {
+		consumed, err := s.mfaDAO.ConsumeStep(ctx, m.UserID, step)
+		if err != nil {
+			return err
+		}
+		if !consumed {
+			return apperr.New(constant.MFACodeInvalid, "验证码已使用")
+		}
+		return nil
+	}
+
+	// 按查找标识定位，每次尝试只做一次慢散列校验
+	lookup, ok := totp.RecoveryCodeLookup(code)
+	if !ok {
+		return apperr.New(constant.MFACodeInvalid)
+	}
+	codes, err := s.mfaDAO.ListUnusedRecoveryCodes(ctx, m.UserID, lookup)
+	if err != nil {
+		return err
+	}
+	normalized := strings.ToLower(code)
+	for _, rc := range codes {
+		if ok, _ := hasher.GetHasher().Verify(normalized, rc.CodeHash); !ok {
+			continue
+		}
+		consumed, err := s.mfaDAO.ConsumeRecoveryCode(ctx, rc.ID)
+		if err != nil {
+			return err
+		}
+		if consumed {
+			return nil
+		}
+	}
+	return apperr.New(constant.MFACodeInvalid)
+}
+
+// enabledMFA 获取已开启的两步验证设置
+func (s *mfaService) enabledMFA(ctx context.Context, userID uint) (*model.UserMFA, error) {
+	m, err := s.mfaDAO.GetByUserID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperr.New(constant.MFANotEnabled)
+		}
+		return nil, err
+	}
+	if !m.Enabled {
+		return nil, apperr.New(constant.MFANotEnabled)
+	}
+	return m, nil
+}
+
+// confirmCurrentUser 获取当前登录用户并校验其密码；仅凭访问令牌不能修改两步验证设置
+func (s *mfaService) confirmCurrentUser(ctx context.Context, password string) (*model.User, error) {
+	user, err := s.userDAO.GetByID(ctx, CurrentSubject(ctx).UserID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperr.New(constant.UserNotExist)
+		}
+		return nil, err
+	}
+	if err := UserService.confirmPassword(ctx, user, password); err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+func mfaPendingKey(raw string) string {
+	sum := sha256.Sum256([]byte(raw))
+	return mfaPendingPrefix + hex.EncodeToString(sum[:])
+}
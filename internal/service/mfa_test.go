@@ -0,0 +1,164 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/liuchen/gin-craft/internal/constant"
+	dtoUser "github.com/liuchen/gin-craft/internal/dto/user"
+	"github.com/liuchen/gin-craft/internal/model"
+	apperr "github.com/liuchen/gin-craft/internal/pkg/errors"
+	"github.com/liuchen/gin-craft/pkg/totp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const mfaTestPassword = "Passw0rd!"
+
+// testMFA 已开启的两步验证：Step 为开启时消耗的时间步，之后只接受更大的时间步
+type testMFA struct {
+	Secret        string
+	Step          int64
+	RecoveryCodes []string
+}
+
+// code 生成 Step 之后第 offset 个时间步的验证码
+func (m testMFA) code(t *testing.T, offset int64) string {
+	t.Helper()
+	code, err := totp.Code(m.Secret, m.Step+offset)
+	require.NoError(t, err)
+	return code
+}
+
+// enableTestMFA 为用户开启两步验证
+func enableTestMFA(t *testing.T, user *model.User) testMFA {
+	t.Helper()
+	ctx := newTestContext(t, user)
+	enroll, err := MFAService.Enroll(ctx, &dtoUser.MFAEnrollRequest{Password: mfaTestPassword})
+	require.NoError(t, err)
+	m := testMFA{Secret: enroll.Secret, Step: totp.Step(time.Now())}
+	resp, err := MFAService.Enable(ctx, &dtoUser.MFACodeRequest{Password: mfaTestPassword, Code: m.code(t, 0)})
+	require.NoError(t, err)
+	require.Len(t, resp.RecoveryCodes, recoveryCodeCount)
+	m.RecoveryCodes = resp.RecoveryCodes
+	return m
+}
+
+// loginFirstStep 执行登录第一步，断言需要两步验证并返回 mfa_token
+func loginFirstStep(t *testing.T, ctx context.Context, username string) string {
+	t.Helper()
+	resp, err := UserService.Login(ctx, &dtoUser.LoginRequest{Username: username, Password: mfaTestPassword})
+	require.NoError(t, err)
+	require.True(t, resp.MFARequired)
+	assert.Empty(t, resp.Token)
+	assert.Empty(t, resp.RefreshToken)
+	require.NotEmpty(t, resp.MFAToken)
+	return resp.MFAToken
+}
+
+func assertAppErrorCode(t *testing.T, code int, err error) {
+	t.Helper()
+	appErr, ok := apperr.GetAppError(err)
+	require.True(t, ok, "expected AppError, got %v", err)
+	assert.Equal(t, code, appErr.GetCode())
+}
+
+func TestMFATwoPhaseLogin(t *testing.T) {
+	setupServiceEnv(t)
+	user := createTestUser(t, "mfa_login", mfaTestPassword)
+
+	// 未开启两步验证：直接签发令牌
+	ctx := newTestContext(t, nil)
+	resp, err := UserService.Login(ctx, &dtoUser.LoginRequest{Username: user.Username, Password: mfaTestPassword})
+	require.NoError(t, err)
+	assert.False(t, resp.MFARequired)
+	assert.NotEmpty(t, resp.Token)
+
+	m := enableTestMFA(t, user)
+	mfaToken := loginFirstStep(t, ctx, user.Username)
+
+	// 开启时已用掉当前时间步，下一时间步的验证码仍在允许的偏差内
+	code := m.code(t, 1)
+	resp, err = MFAService.Login(ctx, &dtoUser.MFALoginRequest{MFAToken: mfaToken, Code: code})
+	require.NoError(t, err)
+	assert.False(t, resp.MFARequired)
+	assert.NotEmpty(t, resp.Token)
+	assert.NotEmpty(t, resp.RefreshToken)
+
+	// mfa_token 通过后即失效
+	_, err = MFAService.Login(ctx, &dtoUser.MFALoginRequest{MFAToken: mfaToken, Code: code})
+	assertAppErrorCode(t, constant.MFATokenInvalid, err)
+}
+
+func TestMFACodeReplay(t *testing.T) {
+	setupServiceEnv(t)
+	user := createTestUser(t, "mfa_replay", mfaTestPassword)
+	m := enableTestMFA(t, user)
+	ctx := newTestContext(t, nil)
+
+	// 开启时使用的验证码不能再用于登录
+	_, err := MFAService.Login(ctx, &dtoUser.MFALoginRequest{MFAToken: loginFirstStep(t, ctx, user.Username), Code: m.code(t, 0)})
+	assertAppErrorCode(t, constant.MFACodeInvalid, err)
+
+	next := m.code(t, 1)
+	_, err = MFAService.Login(ctx, &dtoUser.MFALoginRequest{MFAToken: loginFirstStep(t, ctx, user.Username), Code: next})
+	require.NoError(t, err)
+
+	// 同一验证码换一个 mfa_token 重放
+	_, err = MFAService.Login(ctx, &dtoUser.MFALoginRequest{MFAToken: loginFirstStep(t, ctx, user.Username), Code: next})
+	assertAppErrorCode(t, constant.MFACodeInvalid, err)
+}
+
+func TestMFAAttemptLimit(t *testing.T) {
+	setupServiceEnv(t)
+	user := createTestUser(t, "mfa_attempts", mfaTestPassword)
+	m := enableTestMFA(t, user)
+	ctx := newTestContext(t, nil)
+	mfaToken := loginFirstStep(t, ctx, user.Username)
+
+	for i := 0; i < mfaMaxAttempts; i++ {
+		_, err := MFAService.Login(ctx, &dtoUser.MFALoginRequest{MFAToken: mfaToken, Code: "000000"})
+		assertAppErrorCode(t, constant.MFACodeInvalid, err)
+	}
+
+	// 超过尝试次数后 mfa_token 作废，正确的验证码也不再接受
+	code := m.code(t, 1)
+	_, err := MFAService.Login(ctx, &dtoUser.MFALoginRequest{MFAToken: mfaToken, Code: code})
+	assertAppErrorCode(t, constant.MFATokenInvalid, err)
+	_, err = MFAService.Login(ctx, &dtoUser.MFALoginRequest{MFAToken: mfaToken, Code: code})
+	assertAppErrorCode(t, constant.MFATokenInvalid, err)
+}
+
+func TestMFARecoveryCodeSingleUse(t *testing.T) {
+	setupServiceEnv(t)
+	user := createTestUser(t, "mfa_recovery", mfaTestPassword)
+	codes := enableTestMFA(t, user).RecoveryCodes
+	ctx := newTestContext(t, nil)
+
+	resp, err := MFAService.Login(ctx, &dtoUser.MFALoginRequest{MFAToken: loginFirstStep(t, ctx, user.Username), Code: codes[0]})
+	require.NoError(t, err)
+	assert.NotEmpty(t, resp.Token)
+
+	_, err = MFAService.Login(ctx, &dtoUser.MFALoginRequest{MFAToken: loginFirstStep(t, ctx, user.Username), Code: codes[0]})
+	assertAppErrorCode(t, constant.MFACodeInvalid, err)
+
+	// 其他恢复码不受影响，且不区分大小写
+	_, err = MFAService.Login(ctx, &dtoUser.MFALoginRequest{MFAToken: loginFirstStep(t, ctx, user.Username), Code: " " + strings.ToUpper(codes[1]) + " "})
+	require.NoError(t, err)
+}
+
+func TestMFASettingsRequirePassword(t *testing.T) {
+	setupServiceEnv(t)
+	user := createTestUser(t, "mfa_password", mfaTestPassword)
+	ctx := newTestContext(t, user)
+
+	_, err := MFAService.Enroll(ctx, &dtoUser.MFAEnrollRequest{Password: "wrong"})
+	assertAppErrorCode(t, constant.PasswordError, err)
+
+	code := enableTestMFA(t, user).code(t, 1)
+	err = MFAService.Disable(ctx, &dtoUser.MFACodeRequest{Password: "wrong", Code: code})
+	assertAppErrorCode(t, constant.PasswordError, err)
+	require.NoError(t, MFAService.Disable(ctx, &dtoUser.MFACodeRequest{Password: mfaTestPassword, Code: code}))
+}
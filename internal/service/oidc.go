@@ -0,0 +1,287 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/liuchen/gin-craft/internal/constant"
+	"github.com/liuchen/gin-craft/internal/dao"
+	dtoUser "github.com/liuchen/gin-craft/internal/dto/user"
+	"github.com/liuchen/gin-craft/internal/model"
+	"github.com/liuchen/gin-craft/internal/pkg/config"
+	pkgCtx "github.com/liuchen/gin-craft/internal/pkg/context"
+	apperr "github.com/liuchen/gin-craft/internal/pkg/errors"
+	"github.com/liuchen/gin-craft/internal/pkg/oidc"
+	"github.com/liuchen/gin-craft/internal/pkg/redis"
+	pkgoidc "github.com/liuchen/gin-craft/pkg/oidc"
+	goredis "github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// oidcStatePrefix 授权请求暂存 key 前缀，完整 key 为 oidc:state:<state>
+const oidcStatePrefix = "oidc:state:"
+
+// 用户名长度限制，与 RegisterRequest 一致
+const (
+	usernameMinLen = 3
+	usernameMaxLen = 20
+)
+
+var usernameInvalidChars = regexp.MustCompile(`[^a-zA-Z0-9_]`)
+
+// oidcPending 发起登录时暂存、回调时一次性取回的授权请求
+type oidcPending struct {
+	Provider string `json:"provider"`
+	Verifier string `json:"verifier"`
+	Nonce    string `json:"nonce"`
+	Binding  string `json:"binding"` // 浏览器绑定值的 SHA-256，只有发起登录的浏览器能完成回调
+}
+
+// oidcService OIDC 登录服务（authorization code + PKCE）
+type oidcService struct {
+	userDAO     *dao.UserDAO
+	identityDAO *dao.IdentityDAO
+	tx          *dao.TxManager
+}
+
+// NewOIDCService 构造函数
+func NewOIDCService() *oidcService {
+	return &oidcService{userDAO: dao.GetUserDAO(), identityDAO: dao.GetIdentityDAO(), tx: dao.GetTxManager()}
+}
+
+// OIDCService 全局默认实例
+var OIDCService = NewOIDCService()
+
+// AuthURL 生成 state、nonce 与 PKCE verifier 暂存到 Redis，返回提供方授权地址；
+// 同时生成浏览器绑定值，由 controller 写入 HttpOnly Cookie，回调时与暂存的摘要比对，防止登录 CSRF
+func (s *oidcService) AuthURL(ctx context.Context, req *dtoUser.OIDCLoginRequest) (*dtoUser.OIDCLoginResponse, error) {
+	provider, err := s.provider(ctx, req.Provider)
+	if err != nil {
+		return nil, err
+	}
+
+	state, err := pkgoidc.RandomString(24)
+	if err != nil {
+		return nil, apperr.New(constant.SystemError, err.Error())
+	}
+	nonce, err := pkgoidc.RandomString(24)
+	if err != nil {
+		return nil, apperr.New(constant.SystemError, err.Error())
+	}
+	verifier, challenge, err := pkgoidc.NewPKCE()
+	if err != nil {
+		return nil, apperr.New(constant.SystemError, err.Error())
+	}
+	binding, err := pkgoidc.RandomString(24)
+	if err != nil {
+		return nil, apperr.New(constant.SystemError, err.Error())
+	}
+
+	ttl := time.Duration(config.Config.OIDC.StateTTL) * time.Second
+	pending := oidcPending{Provider: req.Provider, Verifier: verifier, Nonce: nonce, Binding: oidcBindingHash(binding)}
+	if err := redis.GetRedisClient().SetJSON(ctx, oidcStatePrefix+state, pending, ttl); err != nil {
+		return nil, apperr.New(constant.SystemError, err.Error())
+	}
+	return &dtoUser.OIDCLoginResponse{AuthURL: provider.AuthCodeURL(state, nonce, challenge), Binding: binding}, nil
+}
+
+// Callback 校验 state、换取并校验 ID Token，按外部身份找到或创建本地用户，之后与 Login 相同：
+// 已开启两步验证时返回 mfa_token，否则签发令牌
+func (s *oidcService) Callback(ctx context.Context, req *dtoUser.OIDCCallbackRequest) (*dtoUser.LoginResponse, error) {
+	appCtx := pkgCtx.MustGetContext(ctx)
+
+	// GETDEL 保证 state 只能使用一次
+	data, err := redis.GetClient().GetDel(ctx, oidcStatePrefix+req.State).Bytes()
+	if err != nil {
+		if errors.Is(err, goredis.Nil) {
+			return nil, apperr.New(constant.OIDCStateInvalid)
+		}
+		return nil, apperr.New(constant.SystemError, err.Error())
+	}
+	var pending oidcPending
+	if err := json.Unmarshal(data, &pending); err != nil {
+		return nil, apperr.New(constant.OIDCStateInvalid)
+	}
+	// state 必须由当前浏览器发起：拒绝把他人发起的回调链接投递给受害者
+	if req.Binding == "" || subtle.ConstantTimeCompare([]byte(oidcBindingHash(req.Binding)), []byte(pending.Binding)) != 1 {
+		appCtx.LogWarn("OIDC 回调与发起登录的浏览器不一致", zap.String("provider", pending.Provider))
+		return nil, apperr.New(constant.OIDCStateInvalid)
+	}
+
+	provider, err := s.provider(ctx, pending.Provider)
+	if err != nil {
+		return nil, err
+	}
+	tok, err := provider.Exchange(ctx, req.Code, pending.Verifier)
+	if err != nil {
+		appCtx.LogWarn("OIDC 授权码换取失败", zap.String("provider", pending.Provider), zap.Error(err))
+		return nil, apperr.New(constant.OIDCLoginFailed)
+	}
+	claims, err := provider.VerifyIDToken(ctx, tok.IDToken, pending.Nonce)
+	if err != nil {
+		appCtx.LogWarn("OIDC ID Token 校验失败", zap.String("provider", pending.Provider), zap.Error(err))
+		return nil, apperr.New(constant.OIDCLoginFailed)
+	}
+
+	user, err := s.resolveUser(ctx, pending.Provider, claims)
+	if err != nil {
+		return nil, err
+	}
+	if err := statusError(user.Status); err != nil {
+		appCtx.LogWarn("非正常状态的账号尝试 OIDC 登录", zap.Uint("user_id", user.ID), zap.String("status", user.Status))
+		return nil, err
+	}
+	resp, err := UserService.finishPrimaryAuth(ctx, user)
+	if err != nil {
+		return nil, err
+	}
+	if resp.MFARequired {
+		appCtx.LogInfo("OIDC 校验通过，等待两步验证", zap.String("provider", pending.Provider), zap.Uint("user_id", user.ID))
+		return resp, nil
+	}
+	appCtx.LogInfo("OIDC 登录成功", zap.String("provider", pending.Provider), zap.Uint("user_id", user.ID))
+	return resp, nil
+}
+
+// resolveUser 按外部身份查找本地用户：已绑定直接返回；邮箱已验证且匹配已有用户时绑定该用户；否则创建新用户
+func (s *oidcService) resolveUser(ctx context.Context, provider string, claims *pkgoidc.IDClaims) (*model.User, error) {
+	appCtx := pkgCtx.MustGetContext(ctx)
+
+	identity, err := s.identityDAO.GetByProviderSubject(ctx, provider, claims.Subject)
+	if err == nil {
+		user, err := s.userDAO.GetByID(ctx, identity.UserID)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, apperr.New(constant.UserNotExist)
+			}
+			return nil, err
+		}
+		return user, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	if claims.Email == "" {
+		return nil, apperr.New(constant.OIDCLoginFailed, "提供方未返回邮箱")
+	}
+	identity = &model.UserIdentity{Provider: provider, Subject: claims.Subject, Email: claims.Email}
+
+	existing, err := s.userDAO.GetByEmail(ctx, claims.Email)
+	switch {
+	case err == nil:
+		// 双方都验证过邮箱才自动绑定：提供方未验证时不能据此接管已有账号；
+		// 本地账号未验证时可能是攻击者抢先用受害者邮箱注册的，绑定后受害者的单点登录会进入攻击者的账号
+		if !claims.EmailVerified || existing.EmailVerifiedAt == nil {
+			appCtx.LogWarn("邮箱已被未验证的账号使用，拒绝自动绑定外部身份", zap.String("provider", provider), zap.Uint("user_id", existing.ID))
+			return nil, apperr.New(constant.EmailAlreadyExist, "该邮箱已被本地账号使用，请先使用密码登录并验证邮箱")
+		}
+		identity.UserID = existing.ID
+		if err := s.identityDAO.Create(ctx, identity); err != nil {
+			return nil, err
+		}
+		appCtx.LogInfo("绑定外部身份", zap.String("provider", provider), zap.Uint("user_id", existing.ID))
+		return existing, nil
+	case !errors.Is(err, gorm.ErrRecordNotFound):
+		return nil, err
+	}
+
+	username, err := s.availableUsername(ctx, claims)
+	if err != nil {
+		return nil, err
+	}
+	// 外部登录用户没有本地密码，写入随机密码的散列占位
+	randomPassword, err := pkgoidc.RandomString(32)
+	if err != nil {
+		return nil, apperr.New(constant.SystemError, err.Error())
+	}
+	hashed, err := hashPassword(randomPassword)
+	if err != nil {
+		return nil, apperr.New(constant.SystemError, err.Error())
+	}
+	user := &model.User{Username: username, Password: hashed, Email: claims.Email, Role: constant.RoleUser, Status: constant.UserStatusActive}
+	if claims.EmailVerified {
+		// 提供方已验证的邮箱视为已验证，之后同一邮箱的其他提供方可以自动绑定
+		now := time.Now()
+		user.EmailVerifiedAt = &now
+	}
+	err = s.tx.Do(ctx, func(ctx context.Context) error {
+		if err := s.userDAO.Create(ctx, user); err != nil {
+			return err
+		}
+		identity.UserID = user.ID
+		return s.identityDAO.Create(ctx, identity)
+	})
+	if err != nil {
+		return nil, err
+	}
+	appCtx.LogInfo("外部身份首次登录，创建用户", zap.String("provider", provider), zap.Uint("user_id", user.ID))
+	return user, nil
+}
+
+// availableUsername 由 preferred_username 或邮箱前缀推导合法且未被占用的用户名
+func (s *oidcService) availableUsername(ctx context.Context, claims *pkgoidc.IDClaims) (string, error) {
+	base := claims.PreferredUsername
+	if base == "" {
+		base, _, _ = strings.Cut(claims.Email, "@")
+	}
+	base = sanitizeUsername(base)
+
+	candidate := base
+	for i := 0; i < 5; i++ {
+		exists, err := s.userDAO.ExistsByUsername(ctx, candidate)
+		if err != nil {
+			return "", err
+		}
+		if !exists {
+			return candidate, nil
+		}
+		suffix := fmt.Sprintf("%04x", rand.IntN(0x10000))
+		candidate = truncate(base, usernameMaxLen-len(suffix)-1) + "_" + suffix
+	}
+	return "", apperr.New(constant.UsernameAlreadyExist)
+}
+
+// provider 获取已配置的提供方
+func (s *oidcService) provider(ctx context.Context, name string) (*pkgoidc.Provider, error) {
+	p, err := oidc.GetProvider(ctx, name)
+	if err != nil {
+		if errors.Is(err, oidc.ErrProviderNotFound) {
+			return nil, apperr.New(constant.OIDCProviderNotExist)
+		}
+		pkgCtx.MustGetContext(ctx).LogError("OIDC 提供方初始化失败", zap.String("provider", name), zap.Error(err))
+		return nil, apperr.New(constant.OIDCLoginFailed)
+	}
+	return p, nil
+}
+
+// sanitizeUsername 非法字符替换为下划线，过短时补前缀，过长时截断
+func sanitizeUsername(s string) string {
+	s = strings.Trim(usernameInvalidChars.ReplaceAllString(s, "_"), "_")
+	if len(s) < usernameMinLen {
+		s = "user_" + s
+	}
+	return truncate(s, usernameMaxLen)
+}
+
+func oidcBindingHash(binding string) string {
+	sum := sha256.Sum256([]byte(binding))
+	return hex.EncodeToString(sum[:])
+}
+
+func truncate(s string, n int) string {
+	if len(s) > n {
+		return s[:n]
+	}
+	return s
+}
@@ -0,0 +1,54 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/liuchen/gin-craft/internal/constant"
+	"github.com/liuchen/gin-craft/internal/model"
+	"github.com/liuchen/gin-craft/internal/pkg/database"
+	pkgoidc "github.com/liuchen/gin-craft/pkg/oidc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSanitizeUsername(t *testing.T) {
+	tests := map[string]string{
+		"alice":                       "alice",
+		"john.doe":                    "john_doe",
+		"张三":                          "user_",
+		"ab":                          "user_ab",
+		"-x-":                         "user_x",
+		"a_very_long_username_indeed": "a_very_long_username",
+	}
+	for in, expected := range tests {
+		assert.Equal(t, expected, sanitizeUsername(in), in)
+	}
+}
+
+func TestOIDCResolveUserLinking(t *testing.T) {
+	setupServiceEnv(t)
+	ctx := newTestContext(t, nil)
+
+	// 攻击者抢先用受害者邮箱注册但未验证：受害者的单点登录不能绑定到该账号
+	squatter := createTestUser(t, "squatter", "Passw0rd!")
+	claims := &pkgoidc.IDClaims{Email: squatter.Email, EmailVerified: true, RegisteredClaims: jwt.RegisteredClaims{Subject: "victim-sub"}}
+	_, err := OIDCService.resolveUser(ctx, "google", claims)
+	assertAppErrorCode(t, constant.EmailAlreadyExist, err)
+	var identities int64
+	require.NoError(t, database.GetDB().Model(&model.UserIdentity{}).Count(&identities).Error)
+	assert.Zero(t, identities)
+
+	// 本地邮箱已验证时自动绑定
+	now := time.Now()
+	require.NoError(t, database.GetDB().Model(squatter).Update("email_verified_at", now).Error)
+	user, err := OIDCService.resolveUser(ctx, "google", claims)
+	require.NoError(t, err)
+	assert.Equal(t, squatter.ID, user.ID)
+
+	// 由提供方已验证的邮箱新建的用户视为已验证
+	user, err = OIDCService.resolveUser(ctx, "google", &pkgoidc.IDClaims{Email: "fresh@example.com", EmailVerified: true, PreferredUsername: "fresh", RegisteredClaims: jwt.RegisteredClaims{Subject: "new-sub"}})
+	require.NoError(t, err)
+	assert.NotNil(t, user.EmailVerifiedAt)
+}
@@ -0,0 +1,139 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/liuchen/gin-craft/internal/constant"
+	dtoUser "github.com/liuchen/gin-craft/internal/dto/user"
+	"github.com/liuchen/gin-craft/internal/model"
+	pkgCtx "github.com/liuchen/gin-craft/internal/pkg/context"
+	apperr "github.com/liuchen/gin-craft/internal/pkg/errors"
+	"github.com/liuchen/gin-craft/internal/pkg/hasher"
+	"github.com/liuchen/gin-craft/internal/pkg/password"
+	pkgpassword "github.com/liuchen/gin-craft/pkg/password"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// ChangePassword 修改当前用户密码：校验当前密码与密码策略，成功后吊销该用户全部会话，
+// 并为当前客户端签发新令牌，其他设备需重新登录
+func (s *userService) ChangePassword(ctx context.Context, req *dtoUser.PasswordUpdateRequest) (*dtoUser.LoginResponse, error) {
+	user, err := s.userDAO.GetByID(ctx, CurrentSubject(ctx).UserID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperr.New(constant.UserNotExist)
+		}
+		return nil, err
+	}
+	if err := s.confirmPassword(ctx, user, req.OldPassword); err != nil {
+		return nil, err
+	}
+	if req.Password == req.OldPassword {
+		return nil, apperr.New(constant.PasswordPolicyFailed, "新密码不能与当前密码相同")
+	}
+	if err := checkPasswordPolicy(req.Password, user.Username, user.Email); err != nil {
+		return nil, err
+	}
+
+	hashed, err := hashPassword(req.Password)
+	if err != nil {
+		return nil, apperr.New(constant.SystemError, err.Error())
+	}
+	if err := s.userDAO.UpdatePassword(ctx, user.ID, hashed); err != nil {
+		return nil, err
+	}
+	if err := s.revokeAllSessions(ctx, user.ID); err != nil {
+		return nil, err
+	}
+	resp, err := s.startSession(ctx, user)
+	if err != nil {
+		return nil, err
+	}
+	recordAudit(ctx, auditPasswordChange, userTarget(user.ID), nil, nil)
+	return resp, nil
+}
+
+// confirmPassword 敏感操作前再次校验当前用户的密码，不通过返回 PasswordError。
+// 与登录共用用户名维度的失败计数：持有访问令牌也不能穷举当前密码，锁定期间返回 AccountLocked
+func (s *userService) confirmPassword(ctx context.Context, user *model.User, pw string) error {
+	if err := LockoutService.check(ctx, user.Username, ""); err != nil {
+		return err
+	}
+	if !verifyPassword(ctx, pw, user.Password) {
+		err := LockoutService.fail(ctx, user.Username, "")
+		if appErr, ok := apperr.GetAppError(err); ok && appErr.GetCode() == constant.AccountLocked {
+			return err
+		}
+		return apperr.New(constant.PasswordError)
+	}
+	LockoutService.succeed(ctx, user.Username)
+	return nil
+}
+
+// hashPassword 使用配置的首选算法散列口令
+func hashPassword(pw string) (string, error) {
+	return hasher.GetHasher().Hash(pw)
+}
+
+// verifyPassword 校验口令；散列格式无法识别时按不匹配处理
+func verifyPassword(ctx context.Context, pw, encoded string) bool {
+	ok, err := hasher.GetHasher().Verify(pw, encoded)
+	if err != nil {
+		pkgCtx.MustGetContext(ctx).LogError("密码散列无法识别", zap.Error(err))
+		return false
+	}
+	return ok
+}
+
+// rehashIfNeeded 登录成功后，散列使用旧算法或旧参数时用明文口令重新散列；失败不影响登录
+func (s *userService) rehashIfNeeded(ctx context.Context, user *model.User, pw string) {
+	h := hasher.GetHasher()
+	if !h.NeedsRehash(user.Password) {
+		return
+	}
+	appCtx := pkgCtx.MustGetContext(ctx)
+	hashed, err := h.Hash(pw)
+	if err == nil {
+		err = s.userDAO.UpdatePassword(ctx, user.ID, hashed)
+	}
+	if err != nil {
+		appCtx.LogWarn("密码重新散列失败", zap.Uint("user_id", user.ID), zap.Error(err))
+		return
+	}
+	user.Password = hashed
+	appCtx.LogInfo("密码散列已升级", zap.Uint("user_id", user.ID))
+}
+
+// checkPasswordPolicy 按配置的密码策略校验新密码，inputs 为不允许出现在密码中的个人信息
+func checkPasswordPolicy(pw string, inputs ...string) error {
+	policy := password.GetPolicy()
+	err := policy.Validate(pw, inputs...)
+	if err == nil {
+		return nil
+	}
+
+	var detail string
+	switch {
+	case errors.Is(err, pkgpassword.ErrTooShort):
+		detail = fmt.Sprintf("密码至少 %d 个字符", policy.MinLength)
+	case errors.Is(err, pkgpassword.ErrTooLong):
+		detail = "密码过长"
+	case errors.Is(err, pkgpassword.ErrMissingUpper):
+		detail = "密码需包含大写字母"
+	case errors.Is(err, pkgpassword.ErrMissingLower):
+		detail = "密码需包含小写字母"
+	case errors.Is(err, pkgpassword.ErrMissingDigit):
+		detail = "密码需包含数字"
+	case errors.Is(err, pkgpassword.ErrMissingSymbol):
+		detail = "密码需包含符号"
+	case errors.Is(err, pkgpassword.ErrContainsInput):
+		detail = "密码不能包含用户名或邮箱"
+	case errors.Is(err, pkgpassword.ErrBreached):
+		detail = "该密码已出现在泄露密码库中，请更换"
+	default:
+		detail = err.Error()
+	}
+	return apperr.New(constant.PasswordPolicyFailed, detail)
+}
@@ -0,0 +1,40 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/liuchen/gin-craft/internal/constant"
+	dtoUser "github.com/liuchen/gin-craft/internal/dto/user"
+	"github.com/liuchen/gin-craft/internal/pkg/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChangePasswordLockout(t *testing.T) {
+	setupServiceEnv(t)
+	user := createTestUser(t, "change_pw", "Passw0rd!")
+	ctx := newTestContext(t, user)
+	wrong := &dtoUser.PasswordUpdateRequest{OldPassword: "wrong", Password: "N3wPassw0rd!"}
+
+	threshold := config.Config.Lockout.Account.Threshold
+	for i := 1; i < threshold; i++ {
+		_, err := UserService.ChangePassword(ctx, wrong)
+		assertAppErrorCode(t, constant.PasswordError, err)
+	}
+	// 达到阈值的这次失败触发锁定
+	_, err := UserService.ChangePassword(ctx, wrong)
+	assertAppErrorCode(t, constant.AccountLocked, err)
+
+	// 锁定期间正确的当前密码也被拒绝，登录同样受限
+	_, err = UserService.ChangePassword(ctx, &dtoUser.PasswordUpdateRequest{OldPassword: "Passw0rd!", Password: "N3wPassw0rd!"})
+	assertAppErrorCode(t, constant.AccountLocked, err)
+	_, err = UserService.Login(newTestContext(t, nil), &dtoUser.LoginRequest{Username: user.Username, Password: "Passw0rd!"})
+	assertAppErrorCode(t, constant.AccountLocked, err)
+
+	// 锁定到期后可以修改，成功后清除计数
+	testRedis.FastForward(time.Duration(config.Config.Lockout.Account.MaxDelay) * time.Second)
+	resp, err := UserService.ChangePassword(ctx, &dtoUser.PasswordUpdateRequest{OldPassword: "Passw0rd!", Password: "N3wPassw0rd!"})
+	require.NoError(t, err)
+	assert.NotEmpty(t, resp.Token)
+}
@@ -0,0 +1,110 @@
+package service
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/liuchen/gin-craft/internal/constant"
+	pkgCtx "github.com/liuchen/gin-craft/internal/pkg/context"
+	apperr "github.com/liuchen/gin-craft/internal/pkg/errors"
+	"go.uber.org/zap"
+)
+
+// Action 受策略保护的资源操作
+type Action string
+
+// 用户资源操作
+const (
+	ActionUserUpdate Action = "user:update"
+	ActionUserDelete Action = "user:delete"
+)
+
+// Subject 发起操作的主体，取自 pkgCtx.Context 中的登录身份
+type Subject struct {
+	UserID uint
+	Role   string
+}
+
+// Resource 被操作的资源
+type Resource struct {
+	OwnerID   uint   // 资源归属的用户ID
+	OwnerRole string // 资源归属者的角色，ManagesOwner 据此比较权限
+}
+
+// Rule 策略规则，返回 true 表示放行；同一操作的多条规则任一放行即通过
+type Rule func(ctx context.Context, sub Subject, res Resource) (bool, error)
+
+// IsOwner 主体即资源归属者
+func IsOwner() Rule {
+	return func(_ context.Context, sub Subject, res Resource) (bool, error) {
+		return sub.UserID != 0 && sub.UserID == res.OwnerID, nil
+	}
+}
+
+// HasPermission 主体角色拥有指定权限
+func HasPermission(permission string) Rule {
+	return func(ctx context.Context, sub Subject, _ Resource) (bool, error) {
+		return RBACService.HasPermission(ctx, sub.Role, permission)
+	}
+}
+
+// ManagesOwner 主体角色拥有指定权限，且覆盖资源归属者角色的全部权限；
+// 防止持有 user:admin 的主体修改管理员邮箱后走找回密码接管其账号，或删除权限更高的用户
+func ManagesOwner(permission string) Rule {
+	return func(ctx context.Context, sub Subject, res Resource) (bool, error) {
+		ok, err := RBACService.HasPermission(ctx, sub.Role, permission)
+		if err != nil || !ok {
+			return false, err
+		}
+		_, uncovered, err := uncoveredPermission(ctx, sub.Role, res.OwnerRole)
+		if err != nil {
+			return false, err
+		}
+		return uncovered == "", nil
+	}
+}
+
+// policyService 基于归属与属性的授权策略
+type policyService struct {
+	rules map[Action][]Rule
+}
+
+// NewPolicyService 构造函数，rules 为操作 → 规则列表
+func NewPolicyService(rules map[Action][]Rule) *policyService {
+	return &policyService{rules: rules}
+}
+
+// PolicyService 全局默认实例：用户只能修改、删除自己的记录，除非拥有 user:admin（或 user:delete）
+// 且自身权限覆盖目标用户的角色
+var PolicyService = NewPolicyService(map[Action][]Rule{
+	ActionUserUpdate: {IsOwner(), ManagesOwner(constant.PermUserAdmin)},
+	ActionUserDelete: {IsOwner(), ManagesOwner(constant.PermUserAdmin), ManagesOwner(constant.PermUserDelete)},
+})
+
+// Authorize 判断当前登录主体能否对资源执行操作，拒绝时统一返回 constant.Forbidden。
+// 未声明规则的操作一律拒绝。
+func (s *policyService) Authorize(ctx context.Context, action Action, res Resource) error {
+	appCtx := pkgCtx.MustGetContext(ctx)
+	sub := CurrentSubject(ctx)
+	for _, rule := range s.rules[action] {
+		ok, err := rule(ctx, sub, res)
+		if err != nil {
+			return apperr.New(constant.SystemError, err.Error())
+		}
+		if ok {
+			return nil
+		}
+	}
+	appCtx.LogWarn("操作被策略拒绝",
+		zap.String("action", string(action)),
+		zap.Uint("owner_id", res.OwnerID),
+	)
+	return apperr.New(constant.Forbidden)
+}
+
+// CurrentSubject 从 pkgCtx.Context 中取出当前登录主体；未登录时 UserID 为 0
+func CurrentSubject(ctx context.Context) Subject {
+	appCtx := pkgCtx.MustGetContext(ctx)
+	uid, _ := strconv.ParseUint(appCtx.GetUserID(), 10, 64)
+	return Subject{UserID: uint(uid), Role: appCtx.GetUserRole()}
+}
@@ -0,0 +1,106 @@
+package service
+
+import (
+	"context"
+	stderrors "errors"
+	"testing"
+
+	"github.com/liuchen/gin-craft/internal/constant"
+	dtoUser "github.com/liuchen/gin-craft/internal/dto/user"
+	"github.com/liuchen/gin-craft/internal/model"
+	pkgCtx "github.com/liuchen/gin-craft/internal/pkg/context"
+	"github.com/liuchen/gin-craft/internal/pkg/database"
+	apperr "github.com/liuchen/gin-craft/internal/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// roleIs 测试用规则：按角色放行，避免依赖 Redis 与数据库
+func roleIs(role string) Rule {
+	return func(_ context.Context, sub Subject, _ Resource) (bool, error) {
+		return sub.Role == role, nil
+	}
+}
+
+func newSubjectContext(t *testing.T, userID, role string) context.Context {
+	t.Helper()
+	appCtx := pkgCtx.New(context.Background())
+	t.Cleanup(appCtx.Cancel)
+	if userID != "" {
+		appCtx.SetUser(userID, "tester", role)
+	}
+	return appCtx
+}
+
+func TestPolicyAuthorize(t *testing.T) {
+	policy := NewPolicyService(map[Action][]Rule{
+		ActionUserUpdate: {IsOwner(), roleIs(constant.RoleAdmin)},
+	})
+
+	tests := []struct {
+		name    string
+		userID  string
+		role    string
+		action  Action
+		ownerID uint
+		allowed bool
+	}{
+		{"owner", "7", constant.RoleUser, ActionUserUpdate, 7, true},
+		{"other user", "7", constant.RoleUser, ActionUserUpdate, 8, false},
+		{"admin", "1", constant.RoleAdmin, ActionUserUpdate, 8, true},
+		{"anonymous", "", "", ActionUserUpdate, 0, false},
+		{"undeclared action", "7", constant.RoleAdmin, ActionUserDelete, 7, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := newSubjectContext(t, tt.userID, tt.role)
+			err := policy.Authorize(ctx, tt.action, Resource{OwnerID: tt.ownerID})
+			if tt.allowed {
+				assert.NoError(t, err)
+				return
+			}
+			appErr, ok := apperr.GetAppError(err)
+			require.True(t, ok)
+			assert.Equal(t, constant.Forbidden, appErr.Code)
+		})
+	}
+}
+
+func TestPolicyRuleError(t *testing.T) {
+	failing := func(context.Context, Subject, Resource) (bool, error) {
+		return false, stderrors.New("backend down")
+	}
+	policy := NewPolicyService(map[Action][]Rule{ActionUserUpdate: {failing}})
+
+	err := policy.Authorize(newSubjectContext(t, "7", constant.RoleUser), ActionUserUpdate, Resource{OwnerID: 7})
+	appErr, ok := apperr.GetAppError(err)
+	require.True(t, ok)
+	assert.Equal(t, constant.SystemError, appErr.Code)
+}
+
+func TestPolicyUserAdminCannotTouchHigherRole(t *testing.T) {
+	setupServiceEnv(t)
+	manager, admin := createUserManager(t)
+	target := createTestUser(t, "policy_target", "Passw0rd!")
+	ctx := newTestContext(t, manager)
+
+	// 修改管理员邮箱会清除验证状态，随后即可经找回密码接管其账号
+	err := UserService.UpdateUser(ctx, &dtoUser.UpdateRequest{ID: admin.ID, Email: "attacker@example.com"})
+	assertAppErrorCode(t, constant.Forbidden, err)
+	err = UserService.DeleteUser(ctx, &dtoUser.InfoRequest{ID: admin.ID})
+	assertAppErrorCode(t, constant.Forbidden, err)
+
+	var email string
+	require.NoError(t, database.GetDB().Model(&model.User{}).Where("id = ?", admin.ID).Pluck("email", &email).Error)
+	assert.Equal(t, admin.Email, email)
+
+	// 权限范围内的用户照常管理；不存在的用户返回 UserNotExist
+	require.NoError(t, UserService.UpdateUser(ctx, &dtoUser.UpdateRequest{ID: target.ID, Email: "target2@example.com"}))
+	require.NoError(t, UserService.DeleteUser(ctx, &dtoUser.InfoRequest{ID: target.ID}))
+	err = UserService.DeleteUser(ctx, &dtoUser.InfoRequest{ID: admin.ID + 1000})
+	assertAppErrorCode(t, constant.UserNotExist, err)
+
+	// 普通用户对不存在的用户同样只得到 Forbidden
+	err = UserService.DeleteUser(newTestContext(t, createTestUser(t, "policy_plain", "Passw0rd!")), &dtoUser.InfoRequest{ID: admin.ID + 1000})
+	assertAppErrorCode(t, constant.Forbidden, err)
+}
@@ -0,0 +1,297 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/liuchen/gin-craft/internal/constant"
+	"github.com/liuchen/gin-craft/internal/dao"
+	dtoRBAC "github.com/liuchen/gin-craft/internal/dto/rbac"
+	"github.com/liuchen/gin-craft/internal/model"
+	"github.com/liuchen/gin-craft/internal/pkg/config"
+	pkgCtx "github.com/liuchen/gin-craft/internal/pkg/context"
+	apperr "github.com/liuchen/gin-craft/internal/pkg/errors"
+	"github.com/liuchen/gin-craft/internal/pkg/redis"
+	pkgredis "github.com/liuchen/gin-craft/pkg/redis"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// rbacCacheKeyPrefix 角色权限缓存 key 前缀，完整 key 为 rbac:role:<角色名>
+const rbacCacheKeyPrefix = "rbac:role:"
+
+// builtinPermissions 内置权限，随迁移写入
+var builtinPermissions = []model.Permission{
+	{Code: constant.PermAll, Description: "全部权限"},
+	{Code: constant.PermAdminAccess, Description: "访问管理后台"},
+	{Code: constant.PermUserAdmin, Description: "管理任意用户"},
+	{Code: constant.PermUserDelete, Description: "删除用户"},
+	{Code: constant.PermSessionRevoke, Description: "吊销他人会话"},
+	{Code: constant.PermRoleManage, Description: "管理角色与权限"},
+	{Code: constant.PermAPIKeyManage, Description: "管理API密钥"},
+	{Code: constant.PermLockoutManage, Description: "查看与解除登录锁定"},
+	{Code: constant.PermAuditRead, Description: "查看审计日志"},
+}
+
+// rbacService 角色权限服务
+type rbacService struct {
+	rbacDAO *dao.RBACDAO
+}
+
+// NewRBACService 构造函数
+func NewRBACService() *rbacService {
+	return &rbacService{rbacDAO: dao.GetRBACDAO()}
+}
+
+// RBACService 全局默认实例
+var RBACService = NewRBACService()
+
+// HasPermission 判断角色是否拥有指定权限
+func (s *rbacService) HasPermission(ctx context.Context, role, permission string) (bool, error) {
+	if role == "" {
+		return false, nil
+	}
+	granted, err := s.RolePermissions(ctx, role)
+	if err != nil {
+		return false, err
+	}
+	return MatchPermission(granted, permission), nil
+}
+
+// RolePermissions 解析角色拥有的权限码，优先读取 Redis 缓存；
+// 缓存不可用时直接查库，不影响鉴权结果
+func (s *rbacService) RolePermissions(ctx context.Context, role string) ([]string, error) {
+	appCtx := pkgCtx.MustGetContext(ctx)
+	key := rbacCacheKeyPrefix + role
+
+	var codes []string
+	err := redis.GetRedisClient().GetJSON(ctx, key, &codes)
+	if err == nil {
+		return codes, nil
+	}
+	if !errors.Is(err, pkgredis.ErrRedisKeyNotFound) {
+		appCtx.LogWarn("读取角色权限缓存失败", zap.String("role", role), zap.Error(err))
+	}
+
+	codes, err = s.rbacDAO.PermissionCodesByRole(ctx, role)
+	if err != nil {
+		return nil, err
+	}
+	if codes == nil {
+		codes = []string{}
+	}
+	ttl := time.Duration(config.Config.RBAC.CacheTTL) * time.Second
+	if err := redis.GetRedisClient().SetJSON(ctx, key, codes, ttl); err != nil {
+		appCtx.LogWarn("写入角色权限缓存失败", zap.String("role", role), zap.Error(err))
+	}
+	return codes, nil
+}
+
+// ListRoles 获取全部角色及其权限
+func (s *rbacService) ListRoles(ctx context.Context) ([]dtoRBAC.Role, error) {
+	roles, err := s.rbacDAO.ListRoles(ctx)
+	if err != nil {
+		return nil, err
+	}
+	list := make([]dtoRBAC.Role, 0, len(roles))
+	for _, r := range roles {
+		codes, err := s.rbacDAO.PermissionCodesByRole(ctx, r.Name)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, dtoRBAC.Role{
+			ID:          r.ID,
+			Name:        r.Name,
+			Description: r.Description,
+			Permissions: codes,
+		})
+	}
+	return list, nil
+}
+
+// CreateRole 创建角色。新角色不带任何权限，之后经 Grant 绑定，受调用方自身权限约束
+func (s *rbacService) CreateRole(ctx context.Context, req *dtoRBAC.CreateRoleRequest) (*dtoRBAC.Role, error) {
+	if _, err := s.rbacDAO.GetRoleByName(ctx, req.Name); err == nil {
+		return nil, apperr.New(constant.RoleAlreadyExist)
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	role := &model.Role{Name: req.Name, Description: req.Description}
+	if err := s.rbacDAO.CreateRole(ctx, role); err != nil {
+		return nil, err
+	}
+	recordAudit(ctx, auditRoleCreate, "role:"+role.Name, nil, map[string]interface{}{"description": role.Description})
+	return &dtoRBAC.Role{ID: role.ID, Name: role.Name, Description: role.Description, Permissions: []string{}}, nil
+}
+
+// ListPermissions 获取全部权限
+func (s *rbacService) ListPermissions(ctx context.Context) ([]dtoRBAC.Permission, error) {
+	perms, err := s.rbacDAO.ListPermissions(ctx)
+	if err != nil {
+		return nil, err
+	}
+	list := make([]dtoRBAC.Permission, 0, len(perms))
+	for _, p := range perms {
+		list = append(list, dtoRBAC.Permission{ID: p.ID, Code: p.Code, Description: p.Description})
+	}
+	return list, nil
+}
+
+// CreatePermission 创建权限；只能创建调用方自身已覆盖的权限码，不能借通配权限码扩大授权范围
+func (s *rbacService) CreatePermission(ctx context.Context, req *dtoRBAC.CreatePermissionRequest) (*dtoRBAC.Permission, error) {
+	if !validPermissionCode(req.Code) {
+		return nil, apperr.New(constant.ParamError, "权限码格式应为 资源:操作")
+	}
+	if err := s.checkPermissionsWithinCaller(ctx, req.Code); err != nil {
+		return nil, err
+	}
+	existing, err := s.rbacDAO.GetPermissionsByCodes(ctx, []string{req.Code})
+	if err != nil {
+		return nil, err
+	}
+	if len(existing) > 0 {
+		return nil, apperr.New(constant.PermissionAlreadyExist)
+	}
+
+	perm := &model.Permission{Code: req.Code, Description: req.Description}
+	if err := s.rbacDAO.CreatePermission(ctx, perm); err != nil {
+		return nil, err
+	}
+	recordAudit(ctx, auditPermissionCreate, "permission:"+perm.Code, nil, map[string]interface{}{"description": perm.Description})
+	return &dtoRBAC.Permission{ID: perm.ID, Code: perm.Code, Description: perm.Description}, nil
+}
+
+// Grant 为角色绑定权限。只能授予调用方自身拥有的权限，且不能修改自身角色与权限更高的角色，
+// 否则仅持有 role:manage 即可为自己的角色绑定 * 提升为管理员
+func (s *rbacService) Grant(ctx context.Context, req *dtoRBAC.BindRequest) error {
+	role, permIDs, err := s.resolveBinding(ctx, req)
+	if err != nil {
+		return err
+	}
+	if err := s.checkPermissionsWithinCaller(ctx, req.Permissions...); err != nil {
+		return err
+	}
+	if err := s.rbacDAO.Grant(ctx, role.ID, permIDs); err != nil {
+		return err
+	}
+	recordAudit(ctx, auditRoleGrant, "role:"+role.Name, nil, map[string]interface{}{"permissions": req.Permissions})
+	return s.invalidate(ctx, role.Name)
+}
+
+// Revoke 解除角色的权限绑定；与 Grant 相同，不能修改自身角色与权限更高的角色
+func (s *rbacService) Revoke(ctx context.Context, req *dtoRBAC.BindRequest) error {
+	role, permIDs, err := s.resolveBinding(ctx, req)
+	if err != nil {
+		return err
+	}
+	if err := s.rbacDAO.Revoke(ctx, role.ID, permIDs); err != nil {
+		return err
+	}
+	recordAudit(ctx, auditRoleRevoke, "role:"+role.Name, map[string]interface{}{"permissions": req.Permissions}, nil)
+	return s.invalidate(ctx, role.Name)
+}
+
+// EnsureDefaults 写入内置权限与角色：admin 拥有全部权限，user 默认无额外权限。可重复执行。
+func (s *rbacService) EnsureDefaults(ctx context.Context) error {
+	permIDs := make(map[string]uint, len(builtinPermissions))
+	for _, p := range builtinPermissions {
+		p := p
+		if err := s.rbacDAO.EnsurePermission(ctx, &p); err != nil {
+			return err
+		}
+		permIDs[p.Code] = p.ID
+	}
+
+	admin := &model.Role{Name: constant.RoleAdmin, Description: "管理员"}
+	if err := s.rbacDAO.EnsureRole(ctx, admin); err != nil {
+		return err
+	}
+	if err := s.rbacDAO.Grant(ctx, admin.ID, []uint{permIDs[constant.PermAll]}); err != nil {
+		return err
+	}
+	return s.rbacDAO.EnsureRole(ctx, &model.Role{Name: constant.RoleUser, Description: "普通用户"})
+}
+
+// resolveBinding 校验绑定请求中的角色与权限是否存在，并且角色可由调用方修改：
+// 不是调用方自身的角色，且角色现有权限都被调用方覆盖
+func (s *rbacService) resolveBinding(ctx context.Context, req *dtoRBAC.BindRequest) (*model.Role, []uint, error) {
+	role, err := s.rbacDAO.GetRoleByName(ctx, req.Role)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil, apperr.New(constant.RoleNotExist)
+		}
+		return nil, nil, err
+	}
+	if role.Name == CurrentSubject(ctx).Role {
+		pkgCtx.MustGetContext(ctx).LogWarn("尝试修改自身角色的权限", zap.String("role", role.Name))
+		return nil, nil, apperr.New(constant.Forbidden, "不能修改自身角色的权限")
+	}
+	if err := checkRoleWithinCaller(ctx, role.Name); err != nil {
+		return nil, nil, err
+	}
+	perms, err := s.rbacDAO.GetPermissionsByCodes(ctx, req.Permissions)
+	if err != nil {
+		return nil, nil, err
+	}
+	found := make(map[string]uint, len(perms))
+	for _, p := range perms {
+		found[p.Code] = p.ID
+	}
+	ids := make([]uint, 0, len(req.Permissions))
+	for _, code := range req.Permissions {
+		id, ok := found[code]
+		if !ok {
+			return nil, nil, apperr.New(constant.PermissionNotExist, code)
+		}
+		ids = append(ids, id)
+	}
+	return role, ids, nil
+}
+
+// checkPermissionsWithinCaller 校验 codes 都被调用方角色的权限覆盖
+func (s *rbacService) checkPermissionsWithinCaller(ctx context.Context, codes ...string) error {
+	granted, err := s.RolePermissions(ctx, CurrentSubject(ctx).Role)
+	if err != nil {
+		return err
+	}
+	for _, code := range codes {
+		if !MatchPermission(granted, code) {
+			pkgCtx.MustGetContext(ctx).LogWarn("授予超出自身的权限", zap.String("permission", code))
+			return apperr.New(constant.Forbidden, "不能授予自身未拥有的权限 "+code)
+		}
+	}
+	return nil
+}
+
+// invalidate 清除角色权限缓存，使变更立即对所有实例生效
+func (s *rbacService) invalidate(ctx context.Context, role string) error {
+	if err := redis.GetRedisClient().Del(ctx, rbacCacheKeyPrefix+role); err != nil {
+		return apperr.New(constant.SystemError, err.Error())
+	}
+	return nil
+}
+
+// MatchPermission 判断已授予的权限（或 API 密钥授权范围）是否覆盖所需权限，支持 "*" 与 "资源:*" 通配
+func MatchPermission(granted []string, required string) bool {
+	for _, g := range granted {
+		if g == required || g == constant.PermAll {
+			return true
+		}
+		if resource, ok := strings.CutSuffix(g, ":*"); ok && strings.HasPrefix(required, resource+":") {
+			return true
+		}
+	}
+	return false
+}
+
+// validPermissionCode 权限码须为 "*" 或 "资源:操作"
+func validPermissionCode(code string) bool {
+	if code == constant.PermAll {
+		return true
+	}
+	resource, action, ok := strings.Cut(code, ":")
+	return ok && resource != "" && action != "" && !strings.ContainsAny(code, " \t")
+}
@@ -0,0 +1,76 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/liuchen/gin-craft/internal/constant"
+	dtoRBAC "github.com/liuchen/gin-craft/internal/dto/rbac"
+	"github.com/liuchen/gin-craft/internal/pkg/database"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMatchPermission(t *testing.T) {
+	tests := []struct {
+		name     string
+		granted  []string
+		required string
+		expected bool
+	}{
+		{"exact", []string{"user:delete"}, "user:delete", true},
+		{"all", []string{"*"}, "role:manage", true},
+		{"resource wildcard", []string{"user:*"}, "user:delete", true},
+		{"wildcard other resource", []string{"user:*"}, "role:manage", false},
+		{"wildcard prefix only", []string{"user:*"}, "username:read", false},
+		{"missing", []string{"user:read"}, "user:delete", false},
+		{"none", nil, "admin:access", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, MatchPermission(tt.granted, tt.required))
+		})
+	}
+}
+
+func TestValidPermissionCode(t *testing.T) {
+	for _, code := range []string{"*", "user:delete", "user:*"} {
+		assert.True(t, validPermissionCode(code), code)
+	}
+	for _, code := range []string{"", "user", ":delete", "user:", "user: delete"} {
+		assert.False(t, validPermissionCode(code), code)
+	}
+}
+
+func TestGrantWithinCallerPermissions(t *testing.T) {
+	setupServiceEnv(t)
+	_, admin := createUserManager(t)
+	adminCtx := newTestContext(t, admin)
+	_, err := RBACService.CreateRole(adminCtx, &dtoRBAC.CreateRoleRequest{Name: "role-manager"})
+	require.NoError(t, err)
+	require.NoError(t, RBACService.Grant(adminCtx, &dtoRBAC.BindRequest{Role: "role-manager", Permissions: []string{constant.PermRoleManage}}))
+
+	roleManager := createTestUser(t, "role_manager", "Passw0rd!")
+	require.NoError(t, database.GetDB().Model(roleManager).Update("role", "role-manager").Error)
+	roleManager.Role = "role-manager"
+	ctx := newTestContext(t, roleManager)
+
+	// 只持有 role:manage：不能为自己的角色绑定 *，也不能把未拥有的权限授予其他角色
+	err = RBACService.Grant(ctx, &dtoRBAC.BindRequest{Role: "role-manager", Permissions: []string{constant.PermAll}})
+	assertAppErrorCode(t, constant.Forbidden, err)
+	err = RBACService.Grant(ctx, &dtoRBAC.BindRequest{Role: constant.RoleUser, Permissions: []string{constant.PermAll}})
+	assertAppErrorCode(t, constant.Forbidden, err)
+	err = RBACService.Grant(ctx, &dtoRBAC.BindRequest{Role: constant.RoleUser, Permissions: []string{constant.PermUserAdmin}})
+	assertAppErrorCode(t, constant.Forbidden, err)
+	// 不能修改权限更高的角色，也不能创建超出自身的权限码
+	err = RBACService.Revoke(ctx, &dtoRBAC.BindRequest{Role: constant.RoleAdmin, Permissions: []string{constant.PermAll}})
+	assertAppErrorCode(t, constant.Forbidden, err)
+	_, err = RBACService.CreatePermission(ctx, &dtoRBAC.CreatePermissionRequest{Code: "user:*"})
+	assertAppErrorCode(t, constant.Forbidden, err)
+
+	granted, err := RBACService.RolePermissions(ctx, "role-manager")
+	require.NoError(t, err)
+	assert.Equal(t, []string{constant.PermRoleManage}, granted)
+
+	// 自身拥有的权限可以授予其他权限不高于自己的角色
+	require.NoError(t, RBACService.Grant(ctx, &dtoRBAC.BindRequest{Role: constant.RoleUser, Permissions: []string{constant.PermRoleManage}}))
+}
@@ -0,0 +1,108 @@
+package service
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/liuchen/gin-craft/internal/constant"
+	"github.com/liuchen/gin-craft/internal/model"
+	"github.com/liuchen/gin-craft/internal/pkg/config"
+	pkgCtx "github.com/liuchen/gin-craft/internal/pkg/context"
+	"github.com/liuchen/gin-craft/internal/pkg/database"
+	"github.com/liuchen/gin-craft/internal/pkg/lockout"
+	"github.com/liuchen/gin-craft/internal/pkg/redis"
+	"github.com/liuchen/gin-craft/internal/pkg/token"
+	pkgdb "github.com/liuchen/gin-craft/pkg/database"
+	"github.com/liuchen/gin-craft/pkg/logger"
+	pkgredis "github.com/liuchen/gin-craft/pkg/redis"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	testEnvOnce sync.Once
+	testEnvErr  error
+	testRedis   *miniredis.Miniredis
+)
+
+// setupServiceEnv 初始化服务层测试依赖：miniredis、共享内存 SQLite 与相关配置。
+// 令牌、失败计数器等单例只能初始化一次，因此整个包共用一套环境，每个测试开始时清空数据
+func setupServiceEnv(t *testing.T) {
+	t.Helper()
+	testEnvOnce.Do(func() {
+		config.Config.JWT.Secret = "service-test-secret"
+		config.Config.MFA.Issuer = "GinCraft"
+		config.Config.MFA.PendingTTL = 300
+		config.Config.Lockout.Window = 900
+		config.Config.Lockout.Account = config.LockoutPolicy{Threshold: 5, BaseDelay: 30, MaxDelay: 3600}
+		config.Config.Lockout.IP = config.LockoutPolicy{Threshold: 50, BaseDelay: 30, MaxDelay: 3600}
+		// 测试中使用最低代价的 bcrypt，避免 argon2id 拖慢用例
+		config.Config.Password.Hasher.Algorithm = "bcrypt"
+		config.Config.Password.Hasher.BcryptCost = 4
+
+		// 未初始化日志时模块日志（如 database.log）写到包目录，改为写入临时目录
+		logDir, err := os.MkdirTemp("", "service-test-logs")
+		if testEnvErr = err; err != nil {
+			return
+		}
+		if testEnvErr = logger.InitLogger("error", filepath.Join(logDir, "app.log"), 1, 1, 1, false); testEnvErr != nil {
+			return
+		}
+
+		if testRedis, testEnvErr = miniredis.Run(); testEnvErr != nil {
+			return
+		}
+		port, _ := strconv.Atoi(testRedis.Port())
+		client := pkgredis.NewClient(&pkgredis.Config{Host: testRedis.Host(), Port: port})
+		if testEnvErr = client.Connect(); testEnvErr != nil {
+			return
+		}
+		redis.SetClient(client)
+
+		db := pkgdb.NewSQLiteDatabase("file:service_test?mode=memory&cache=shared")
+		if testEnvErr = db.Connect(); testEnvErr != nil {
+			return
+		}
+		if testEnvErr = db.Migrate(model.Models()...); testEnvErr != nil {
+			return
+		}
+		database.SetDatabase(db)
+
+		if testEnvErr = token.InitToken(); testEnvErr != nil {
+			return
+		}
+		testEnvErr = lockout.InitLockout()
+	})
+	require.NoError(t, testEnvErr)
+
+	testRedis.FlushAll()
+	for _, m := range model.Models() {
+		require.NoError(t, database.GetDB().Unscoped().Where("1 = 1").Delete(m).Error)
+	}
+}
+
+// newTestContext 模拟 ContextMiddleware 注入的应用 Context；user 不为 nil 时视为该用户已登录
+func newTestContext(t *testing.T, user *model.User) context.Context {
+	t.Helper()
+	appCtx := pkgCtx.NewWithTraceID(context.Background(), "test-"+t.Name())
+	t.Cleanup(appCtx.Cancel)
+	appCtx.SetRequestInfo("POST", "/test", "127.0.0.1", "go-test")
+	if user != nil {
+		appCtx.SetUser(strconv.FormatUint(uint64(user.ID), 10), user.Username, user.Role)
+	}
+	return context.WithValue(context.Background(), pkgCtx.CtxKey, appCtx)
+}
+
+// createTestUser 直接写库创建已激活用户（不经过 Register，避免邮件与搜索索引等副作用）
+func createTestUser(t *testing.T, username, password string) *model.User {
+	t.Helper()
+	hashed, err := hashPassword(password)
+	require.NoError(t, err)
+	u := &model.User{Username: username, Password: hashed, Email: username + "@example.com", Role: constant.RoleUser, Status: constant.UserStatusActive}
+	require.NoError(t, database.GetDB().Create(u).Error)
+	return u
+}
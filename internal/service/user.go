@@ -3,15 +3,23 @@ package service
 import (
 	"context"
 	"errors"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/liuchen/gin-craft/internal/constant"
 	"github.com/liuchen/gin-craft/internal/dao"
+	"github.com/liuchen/gin-craft/internal/dto"
 	dtoUser "github.com/liuchen/gin-craft/internal/dto/user"
 	"github.com/liuchen/gin-craft/internal/model"
+	"github.com/liuchen/gin-craft/internal/pkg/config"
 	pkgCtx "github.com/liuchen/gin-craft/internal/pkg/context"
+	"github.com/liuchen/gin-craft/internal/pkg/cursor"
 	apperr "github.com/liuchen/gin-craft/internal/pkg/errors"
-	"github.com/liuchen/gin-craft/pkg/utils"
+	"github.com/liuchen/gin-craft/internal/pkg/token"
+	pkgcursor "github.com/liuchen/gin-craft/pkg/cursor"
+	"github.com/liuchen/gin-craft/pkg/query"
+	pkgtoken "github.com/liuchen/gin-craft/pkg/token"
 	"go.uber.org/zap"
 	"gorm.io/gorm"
 )
@@ -19,82 +27,331 @@ import (
 // userService 用户服务
 type userService struct {
 	userDAO *dao.UserDAO
+	tx      *dao.TxManager
 }
 
 // NewUserService 构造函数
 func NewUserService() *userService {
-	return &userService{userDAO: dao.GetUserDAO()}
+	return &userService{userDAO: dao.GetUserDAO(), tx: dao.GetTxManager()}
 }
 
 // UserService 全局默认实例（兼容旧调用方）
 var UserService = NewUserService()
 
-// Register 用户注册
-func (s *userService) Register(req *dtoUser.RegisterRequest) error {
-	if exists, err := s.userDAO.ExistsByUsername(req.Username); err != nil {
+// Register 用户注册，注册成功后发送邮箱验证邮件（发送失败不影响注册）
+func (s *userService) Register(ctx context.Context, req *dtoUser.RegisterRequest) error {
+	if exists, err := s.userDAO.ExistsByUsername(ctx, req.Username); err != nil {
 		return err
 	} else if exists {
 		return apperr.New(constant.UsernameAlreadyExist)
 	}
-	if exists, err := s.userDAO.ExistsByEmail(req.Email); err != nil {
+	if exists, err := s.userDAO.ExistsByEmail(ctx, req.Email); err != nil {
 		return err
 	} else if exists {
 		return apperr.New(constant.EmailAlreadyExist)
 	}
+	if err := checkPasswordPolicy(req.Password, req.Username, req.Email); err != nil {
+		return err
+	}
 
-	hashed, err := utils.HashPassword(req.Password)
+	hashed, err := hashPassword(req.Password)
 	if err != nil {
 		return apperr.New(constant.SystemError, err.Error())
 	}
-	return s.userDAO.Create(&model.User{
+	user := &model.User{
 		Username: req.Username,
 		Password: hashed,
 		Email:    req.Email,
-	})
+		Role:     constant.RoleUser,
+		Status:   constant.UserStatusActive,
+	}
+	if config.Config.Account.RequireVerification {
+		user.Status = constant.UserStatusPending
+	}
+	if err := s.userDAO.Create(ctx, user); err != nil {
+		return err
+	}
+	if err := AccountService.sendVerification(ctx, user); err != nil {
+		pkgCtx.MustGetContext(ctx).LogWarn("邮箱验证邮件发送失败", zap.Uint("user_id", user.ID), zap.Error(err))
+	}
+	return nil
 }
 
-// Login 用户登录
+// Login 用户登录。用户不存在与密码错误统一返回 InvalidCredentials，
+// 按用户名与客户端 IP 分别累计失败次数，超过阈值后指数退避锁定
 func (s *userService) Login(ctx context.Context, req *dtoUser.LoginRequest) (*dtoUser.LoginResponse, error) {
 	appCtx := pkgCtx.MustGetContext(ctx)
+	ip := appCtx.GetClientIP()
+
+	if err := LockoutService.check(ctx, req.Username, ip); err != nil {
+		return nil, err
+	}
+	user, err := s.userDAO.GetByUsername(ctx, req.Username)
+	if err != nil {
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, err
+		}
+		burnPasswordCheck(req.Password)
+		return nil, LockoutService.fail(ctx, req.Username, ip)
+	}
+	if !verifyPassword(ctx, req.Password, user.Password) {
+		return nil, LockoutService.fail(ctx, req.Username, ip)
+	}
+	LockoutService.succeed(ctx, req.Username)
+	if err := statusError(user.Status); err != nil {
+		appCtx.LogWarn("非正常状态的账号尝试登录", zap.Uint("user_id", user.ID), zap.String("status", user.Status))
+		return nil, err
+	}
+	s.rehashIfNeeded(ctx, user, req.Password)
 
-	user, err := s.userDAO.GetByUsername(req.Username)
+	resp, err := s.finishPrimaryAuth(ctx, user)
+	if err != nil {
+		return nil, err
+	}
+	if resp.MFARequired {
+		appCtx.LogInfo("密码校验通过，等待两步验证", zap.String("username", req.Username))
+		return resp, nil
+	}
+	appCtx.LogInfo("用户登录成功", zap.String("username", req.Username))
+	return resp, nil
+}
+
+// finishPrimaryAuth 第一因素（密码或外部身份）校验通过后的共同出口：
+// 已开启两步验证时只返回 mfa_token，令牌在 /user/login/mfa 校验验证码后签发；否则直接开启会话
+func (s *userService) finishPrimaryAuth(ctx context.Context, user *model.User) (*dtoUser.LoginResponse, error) {
+	pending, err := MFAService.challenge(ctx, user)
+	if err != nil {
+		return nil, err
+	}
+	if pending != nil {
+		return pending, nil
+	}
+	return s.startSession(ctx, user)
+}
+
+// startSession 为已认证的用户开启新会话：签发刷新令牌与访问令牌
+func (s *userService) startSession(ctx context.Context, user *model.User) (*dtoUser.LoginResponse, error) {
+	refresh, session, err := token.GetRefreshStore().Issue(ctx, strconv.FormatUint(uint64(user.ID), 10), user.Username, user.Role)
+	if err != nil {
+		return nil, apperr.New(constant.SystemError, err.Error())
+	}
+	return s.issueTokens(ctx, user, refresh, session)
+}
+
+// Refresh 使用刷新令牌换取新的访问令牌，刷新令牌同时轮换
+func (s *userService) Refresh(ctx context.Context, req *dtoUser.RefreshRequest) (*dtoUser.LoginResponse, error) {
+	appCtx := pkgCtx.MustGetContext(ctx)
+
+	refresh, session, err := token.GetRefreshStore().Rotate(ctx, req.RefreshToken)
+	if err != nil {
+		switch {
+		case errors.Is(err, pkgtoken.ErrRefreshReused):
+			appCtx.LogWarn("刷新令牌被重放，已吊销整个令牌族",
+				zap.String("user_id", session.UserID),
+				zap.String("family_id", session.FamilyID),
+			)
+			return nil, apperr.New(constant.TokenInvalid)
+		case errors.Is(err, pkgtoken.ErrRefreshInvalid):
+			return nil, apperr.New(constant.TokenInvalid)
+		}
+		return nil, apperr.New(constant.SystemError, err.Error())
+	}
+
+	userID, err := strconv.ParseUint(session.UserID, 10, 64)
+	if err != nil {
+		return nil, apperr.New(constant.TokenInvalid)
+	}
+	user, err := s.userDAO.GetByID(ctx, uint(userID))
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, apperr.New(constant.UserNotExist)
 		}
 		return nil, err
 	}
-	if !utils.CheckPassword(req.Password, user.Password) {
-		return nil, apperr.New(constant.PasswordError)
+	return s.issueTokens(ctx, user, refresh, session)
+}
+
+// Logout 注销当前访问令牌；携带刷新令牌时一并吊销其所属令牌族
+func (s *userService) Logout(ctx context.Context, req *dtoUser.LogoutRequest) error {
+	appCtx := pkgCtx.MustGetContext(ctx)
+
+	if err := token.GetDenylist().Revoke(ctx, appCtx.GetTokenID(), appCtx.GetTokenExpiresAt()); err != nil {
+		return apperr.New(constant.SystemError, err.Error())
+	}
+	if req.RefreshToken != "" {
+		session, err := token.GetRefreshStore().Lookup(ctx, req.RefreshToken)
+		switch {
+		case errors.Is(err, pkgtoken.ErrRefreshInvalid):
+			// 刷新令牌已失效，注销保持幂等
+		case err != nil:
+			return apperr.New(constant.SystemError, err.Error())
+		case session.UserID == appCtx.GetUserID():
+			if err := token.GetRefreshStore().RevokeFamily(ctx, session.FamilyID); err != nil {
+				return apperr.New(constant.SystemError, err.Error())
+			}
+		}
 	}
+	appCtx.LogInfo("用户注销")
+	return nil
+}
 
-	token := "mock_token_" + req.Username + "_" + time.Now().Format("20060102150405")
-	appCtx.LogInfo("用户登录成功", zap.String("username", req.Username))
-	return &dtoUser.LoginResponse{Token: token}, nil
+// RevokeSessions 吊销指定用户的全部会话（管理员操作）
+func (s *userService) RevokeSessions(ctx context.Context, req *dtoUser.RevokeSessionsRequest) error {
+	if _, err := s.userDAO.GetByID(ctx, req.UserID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return apperr.New(constant.UserNotExist)
+		}
+		return err
+	}
+	if err := s.revokeAllSessions(ctx, req.UserID); err != nil {
+		return err
+	}
+	recordAudit(ctx, auditUserRevokeSessions, userTarget(req.UserID), nil, nil)
+	return nil
+}
+
+// revokeAllSessions 使用户名下所有访问令牌与刷新令牌立即失效
+func (s *userService) revokeAllSessions(ctx context.Context, userID uint) error {
+	uid := strconv.FormatUint(uint64(userID), 10)
+	if err := token.GetRefreshStore().RevokeUser(ctx, uid); err != nil {
+		return apperr.New(constant.SystemError, err.Error())
+	}
+	if _, err := token.GetDenylist().RevokeUser(ctx, uid); err != nil {
+		return apperr.New(constant.SystemError, err.Error())
+	}
+	return nil
 }
 
-// GetUserList 获取用户列表
-func (s *userService) GetUserList(req *dtoUser.ListRequest) (*dtoUser.ListResponse, error) {
-	users, err := s.userDAO.GetList(req)
+// issueTokens 签发访问令牌并与刷新令牌一起组装响应；非 active 状态的账号拒绝签发
+func (s *userService) issueTokens(ctx context.Context, user *model.User, refresh string, session *pkgtoken.RefreshSession) (*dtoUser.LoginResponse, error) {
+	if err := statusError(user.Status); err != nil {
+		return nil, err
+	}
+	uid := strconv.FormatUint(uint64(user.ID), 10)
+	version, err := token.GetDenylist().UserVersion(ctx, uid)
+	if err != nil {
+		return nil, apperr.New(constant.SystemError, err.Error())
+	}
+	access, claims, err := token.GetManager().Issue(pkgtoken.Identity{
+		UserID:   uid,
+		Username: user.Username,
+		Role:     user.Role,
+		Version:  version,
+	})
+	if err != nil {
+		return nil, apperr.New(constant.SystemError, err.Error())
+	}
+	return &dtoUser.LoginResponse{
+		Token:            access,
+		TokenType:        "Bearer",
+		ExpiresAt:        claims.ExpiresAt.Time,
+		RefreshToken:     refresh,
+		RefreshExpiresAt: session.ExpiresAt,
+	}, nil
+}
+
+// GetUserList 获取用户列表，filter/sort/fields 按 dao.UserQuerySchema 校验，不合法时返回参数错误
+func (s *userService) GetUserList(ctx context.Context, req *dtoUser.ListRequest) (*dtoUser.ListResponse, error) {
+	lq, err := dao.UserQuerySchema.Parse(req.Filter, req.Sort, req.Fields)
+	if err != nil {
+		if errors.Is(err, query.ErrInvalid) {
+			return nil, apperr.New(constant.ParamError, err.Error())
+		}
+		return nil, err
+	}
+	users, err := s.userDAO.GetList(ctx, req, lq)
 	if err != nil {
 		return nil, err
 	}
 	userList := make([]dtoUser.User, 0, len(users))
 	for _, u := range users {
 		userList = append(userList, dtoUser.User{
-			ID:        u.ID,
-			Username:  u.Username,
-			Email:     u.Email,
-			CreatedAt: u.CreatedAt,
-			UpdatedAt: u.UpdatedAt,
+			ID:            u.ID,
+			Username:      u.Username,
+			Email:         u.Email,
+			EmailVerified: u.EmailVerifiedAt != nil,
+			CreatedAt:     u.CreatedAt,
+			UpdatedAt:     u.UpdatedAt,
 		})
 	}
-	return &dtoUser.ListResponse{List: userList, Pagination: req.Pagination}, nil
+	list, err := lq.Project(userList)
+	if err != nil {
+		return nil, err
+	}
+	return &dtoUser.ListResponse{List: list, Pagination: req.Pagination}, nil
+}
+
+// GetUserListByCursor 游标分页获取用户列表。游标由服务端签名，排序方式与签发时不一致或被篡改时返回参数错误
+func (s *userService) GetUserListByCursor(ctx context.Context, req *dtoUser.CursorListRequest) (*dtoUser.CursorListResponse, error) {
+	if req.Sort == "" {
+		req.Sort = "-id"
+	}
+	k, err := userKeyset(req.Sort, req.Cursor)
+	if err != nil {
+		return nil, err
+	}
+
+	size := req.GetPageSize()
+	if !req.WithTotal {
+		req.Total = dto.TotalSkipped
+	}
+	users, err := s.userDAO.GetListByCursor(ctx, req, k, size)
+	if err != nil {
+		return nil, err
+	}
+
+	req.NextCursor, req.HasMore = "", len(users) > size
+	if req.HasMore {
+		users = users[:size]
+		last := users[size-1]
+		next := pkgcursor.Cursor{Sort: req.Sort, ID: uint64(last.ID)}
+		if k.Column == "created_at" {
+			next.Value = last.CreatedAt.UTC().Format(time.RFC3339Nano)
+		}
+		if req.NextCursor, err = cursor.GetCodec().Encode(next); err != nil {
+			return nil, err
+		}
+	}
+
+	userList := make([]dtoUser.User, 0, len(users))
+	for _, u := range users {
+		userList = append(userList, dtoUser.User{
+			ID:            u.ID,
+			Username:      u.Username,
+			Email:         u.Email,
+			EmailVerified: u.EmailVerifiedAt != nil,
+			CreatedAt:     u.CreatedAt,
+			UpdatedAt:     u.UpdatedAt,
+		})
+	}
+	return &dtoUser.CursorListResponse{List: userList, CursorPagination: req.CursorPagination}, nil
+}
+
+// userKeyset 将排序方式与游标转换为 keyset 起始位置，sort 已由请求参数校验限定在 id/created_at
+func userKeyset(sort, raw string) (dao.Keyset, error) {
+	k := dao.Keyset{Column: strings.TrimPrefix(sort, "-"), Desc: strings.HasPrefix(sort, "-")}
+	if raw == "" {
+		return k, nil
+	}
+
+	cur, err := cursor.GetCodec().Decode(raw)
+	if err != nil || cur.Sort != sort {
+		return k, apperr.New(constant.ParamError, "游标无效")
+	}
+	k.After, k.ID = true, uint(cur.ID)
+	if k.Column == "created_at" {
+		at, err := time.Parse(time.RFC3339Nano, cur.Value)
+		if err != nil {
+			return k, apperr.New(constant.ParamError, "游标无效")
+		}
+		k.Value = at
+	}
+	return k, nil
 }
 
 // GetUserInfo 获取用户信息
-func (s *userService) GetUserInfo(req *dtoUser.InfoRequest) (*dtoUser.User, error) {
-	u, err := s.userDAO.GetByID(req.ID)
+func (s *userService) GetUserInfo(ctx context.Context, req *dtoUser.InfoRequest) (*dtoUser.User, error) {
+	u, err := s.userDAO.GetByID(ctx, req.ID)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, apperr.New(constant.UserNotExist)
@@ -102,22 +359,22 @@ func (s *userService) GetUserInfo(req *dtoUser.InfoRequest) (*dtoUser.User, erro
 		return nil, err
 	}
 	return &dtoUser.User{
-		ID:        u.ID,
-		Username:  u.Username,
-		Email:     u.Email,
-		CreatedAt: u.CreatedAt,
-		UpdatedAt: u.UpdatedAt,
+		ID:            u.ID,
+		Username:      u.Username,
+		Email:         u.Email,
+		EmailVerified: u.EmailVerifiedAt != nil,
+		CreatedAt:     u.CreatedAt,
+		UpdatedAt:     u.UpdatedAt,
 	}, nil
 }
 
-// UpdateUser 更新用户信息（白名单字段）
+// UpdateUser 更新用户信息（白名单字段）；ID 为空时更新当前登录用户
 func (s *userService) UpdateUser(ctx context.Context, req *dtoUser.UpdateRequest) error {
-	appCtx := pkgCtx.MustGetContext(ctx)
-
-	if _, err := s.userDAO.GetByID(req.ID); err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return apperr.New(constant.UserNotExist)
-		}
+	if req.ID == 0 {
+		req.ID = CurrentSubject(ctx).UserID
+	}
+	current, err := s.authorizeTarget(ctx, ActionUserUpdate, req.ID)
+	if err != nil {
 		return err
 	}
 
@@ -125,31 +382,50 @@ func (s *userService) UpdateUser(ctx context.Context, req *dtoUser.UpdateRequest
 	if req.Username != "" {
 		updates["username"] = req.Username
 	}
-	if req.Email != "" {
+	if req.Email != "" && req.Email != current.Email {
+		// 新邮箱需要重新验证
 		updates["email"] = req.Email
+		updates["email_verified_at"] = nil
 	}
 	if len(updates) == 0 {
 		return nil
 	}
-	if err := s.userDAO.Update(req.ID, updates); err != nil {
+	if err := s.userDAO.Update(ctx, req.ID, updates); err != nil {
 		return err
 	}
-	appCtx.LogInfo("更新用户信息", zap.Uint("user_id", req.ID))
+	recordAudit(ctx, auditUserUpdate, userTarget(req.ID),
+		map[string]interface{}{"username": current.Username, "email": current.Email},
+		auditFields(updates, "username", "email"),
+	)
 	return nil
 }
 
-// DeleteUser 删除用户
+// DeleteUser 删除用户：状态迁移到 deleted 并软删除，可由管理员恢复
 func (s *userService) DeleteUser(ctx context.Context, req *dtoUser.InfoRequest) error {
-	appCtx := pkgCtx.MustGetContext(ctx)
-	if _, err := s.userDAO.GetByID(req.ID); err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return apperr.New(constant.UserNotExist)
-		}
+	u, err := s.authorizeTarget(ctx, ActionUserDelete, req.ID)
+	if err != nil {
 		return err
 	}
-	if err := s.userDAO.Delete(req.ID); err != nil {
-		return err
+	return s.transition(ctx, u, constant.UserStatusDeleted, "")
+}
+
+// authorizeTarget 加载目标用户并按策略授权，策略需要目标用户的角色；
+// 用户不存在时仍先授权，未授权的调用方得到 Forbidden 而不是 UserNotExist，无法据此探测用户是否存在
+func (s *userService) authorizeTarget(ctx context.Context, action Action, id uint) (*model.User, error) {
+	u, err := s.userDAO.GetByID(ctx, id)
+	notFound := errors.Is(err, gorm.ErrRecordNotFound)
+	if err != nil && !notFound {
+		return nil, err
 	}
-	appCtx.LogInfo("删除用户信息", zap.Uint("user_id", req.ID))
-	return nil
+	res := Resource{OwnerID: id}
+	if !notFound {
+		res.OwnerRole = u.Role
+	}
+	if err := PolicyService.Authorize(ctx, action, res); err != nil {
+		return nil, err
+	}
+	if notFound {
+		return nil, apperr.New(constant.UserNotExist)
+	}
+	return u, nil
 }
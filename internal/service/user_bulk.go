@@ -0,0 +1,261 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin/binding"
+	"github.com/liuchen/gin-craft/internal/constant"
+	"github.com/liuchen/gin-craft/internal/dao"
+	dtoUser "github.com/liuchen/gin-craft/internal/dto/user"
+	"github.com/liuchen/gin-craft/internal/model"
+	"github.com/liuchen/gin-craft/internal/pkg/config"
+	pkgCtx "github.com/liuchen/gin-craft/internal/pkg/context"
+	apperr "github.com/liuchen/gin-craft/internal/pkg/errors"
+	"github.com/liuchen/gin-craft/pkg/bulk"
+	"github.com/liuchen/gin-craft/pkg/query"
+	"go.uber.org/zap"
+)
+
+// userExportColumns 未指定 fields 时导出的字段
+var userExportColumns = []string{"id", "username", "email", "email_verified", "created_at", "updated_at"}
+
+// importRow 通过校验、等待写入的一行
+type importRow struct {
+	line int
+	req  dtoUser.RegisterRequest
+}
+
+// userImport 一次导入的状态：文件内去重与逐行报告
+type userImport struct {
+	resp      *dtoUser.ImportResponse
+	usernames map[string]int // 小写用户名 → 首次出现的行号
+	emails    map[string]int
+	batch     []importRow
+}
+
+func (im *userImport) fail(line int, username, msg string) {
+	im.resp.Failed++
+	im.resp.Errors = append(im.resp.Errors, dtoUser.ImportRowError{Line: line, Username: username, Message: msg})
+}
+
+// Import 批量导入用户：逐行读取 CSV/JSONL（字段 username、email、password），按注册规则校验，
+// 每 bulk.batch_size 行在一个事务中写入，返回逐行错误报告。某一批写入失败时该批全部计为失败，其余批次不受影响。
+// 账号状态与注册一致；开启 account.require_verification 时为每个新用户发送验证邮件
+func (s *adminUserService) Import(ctx context.Context, r io.Reader, format string) (*dtoUser.ImportResponse, error) {
+	dec, err := bulk.NewDecoder(r, format)
+	if err != nil {
+		return nil, apperr.New(constant.ParamError, err.Error())
+	}
+	cfg := config.Config.Bulk
+	im := &userImport{
+		resp:      &dtoUser.ImportResponse{Errors: []dtoUser.ImportRowError{}},
+		usernames: make(map[string]int),
+		emails:    make(map[string]int),
+	}
+
+	for {
+		rec, line, err := dec.Decode()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if im.resp.Total >= cfg.MaxRows {
+			im.resp.Truncated = true
+			break
+		}
+		var rowErr *bulk.RowError
+		if errors.As(err, &rowErr) {
+			im.resp.Total++
+			im.fail(line, "", "格式错误: "+rowErr.Err.Error())
+			continue
+		}
+		if err != nil {
+			// 读取中断：已写入的批次保留，报告截至当前的结果
+			pkgCtx.MustGetContext(ctx).LogWarn("用户导入读取中断", zap.Int("line", line), zap.Error(err))
+			im.resp.Truncated = true
+			break
+		}
+		im.resp.Total++
+		s.checkImportRow(im, line, rec)
+		if len(im.batch) >= cfg.BatchSize {
+			s.flushImport(ctx, im)
+		}
+	}
+	s.flushImport(ctx, im)
+
+	recordAudit(ctx, auditUserImport, "user:*", nil, map[string]interface{}{
+		"format":  format,
+		"total":   im.resp.Total,
+		"created": im.resp.Created,
+		"failed":  im.resp.Failed,
+	})
+	return im.resp, nil
+}
+
+// checkImportRow 按 RegisterRequest 的校验规则与密码策略检查一行，并在文件内去重；通过后加入待写入批次
+func (s *adminUserService) checkImportRow(im *userImport, line int, rec map[string]string) {
+	req := dtoUser.RegisterRequest{Username: rec["username"], Password: rec["password"], Email: rec["email"]}
+	if err := binding.Validator.ValidateStruct(&req); err != nil {
+		im.fail(line, req.Username, err.Error())
+		return
+	}
+	if err := checkPasswordPolicy(req.Password, req.Username, req.Email); err != nil {
+		im.fail(line, req.Username, importErrorMessage(err))
+		return
+	}
+	name, mail := strings.ToLower(req.Username), strings.ToLower(req.Email)
+	if first, ok := im.usernames[name]; ok {
+		im.fail(line, req.Username, fmt.Sprintf("用户名与第 %d 行重复", first))
+		return
+	}
+	if first, ok := im.emails[mail]; ok {
+		im.fail(line, req.Username, fmt.Sprintf("邮箱与第 %d 行重复", first))
+		return
+	}
+	im.usernames[name], im.emails[mail] = line, line
+	im.batch = append(im.batch, importRow{line: line, req: req})
+}
+
+// flushImport 排除已被占用的用户名与邮箱后写入当前批次
+func (s *adminUserService) flushImport(ctx context.Context, im *userImport) {
+	batch := im.batch
+	im.batch = nil
+	if len(batch) == 0 {
+		return
+	}
+
+	usernames, emails := make([]string, 0, len(batch)), make([]string, 0, len(batch))
+	for _, row := range batch {
+		usernames, emails = append(usernames, row.req.Username), append(emails, row.req.Email)
+	}
+	takenNames, takenMails, err := s.userDAO.ExistingNames(ctx, usernames, emails)
+	if err != nil {
+		for _, row := range batch {
+			im.fail(row.line, row.req.Username, constant.GetMsg(constant.DBError))
+		}
+		pkgCtx.MustGetContext(ctx).LogError("用户导入查重失败", zap.Error(err))
+		return
+	}
+
+	status := constant.UserStatusActive
+	if config.Config.Account.RequireVerification {
+		status = constant.UserStatusPending
+	}
+	users := make([]*model.User, 0, len(batch))
+	lines := make([]importRow, 0, len(batch))
+	for _, row := range batch {
+		switch {
+		case takenNames[strings.ToLower(row.req.Username)]:
+			im.fail(row.line, row.req.Username, constant.GetMsg(constant.UsernameAlreadyExist))
+			continue
+		case takenMails[strings.ToLower(row.req.Email)]:
+			im.fail(row.line, row.req.Username, constant.GetMsg(constant.EmailAlreadyExist))
+			continue
+		}
+		hashed, err := hashPassword(row.req.Password)
+		if err != nil {
+			im.fail(row.line, row.req.Username, constant.GetMsg(constant.SystemError))
+			continue
+		}
+		users = append(users, &model.User{
+			Username: row.req.Username,
+			Password: hashed,
+			Email:    row.req.Email,
+			Role:     constant.RoleUser,
+			Status:   status,
+		})
+		lines = append(lines, row)
+	}
+	if len(users) == 0 {
+		return
+	}
+
+	if err := s.userDAO.BatchCreate(ctx, users); err != nil {
+		for _, row := range lines {
+			im.fail(row.line, row.req.Username, constant.GetMsg(constant.UserCreateFailed))
+		}
+		pkgCtx.MustGetContext(ctx).LogError("用户导入写入失败", zap.Int("rows", len(users)), zap.Error(err))
+		return
+	}
+	im.resp.Created += len(users)
+
+	if status == constant.UserStatusPending {
+		for _, u := range users {
+			if err := AccountService.sendVerification(ctx, u); err != nil {
+				pkgCtx.MustGetContext(ctx).LogWarn("邮箱验证邮件发送失败", zap.Uint("user_id", u.ID), zap.Error(err))
+			}
+		}
+	}
+}
+
+// importErrorMessage 行错误的说明：业务错误取错误信息与详情
+func importErrorMessage(err error) string {
+	if e, ok := apperr.GetAppError(err); ok {
+		if e.GetDetail() != "" {
+			return e.GetMessage() + ": " + e.GetDetail()
+		}
+		return e.GetMessage()
+	}
+	return err.Error()
+}
+
+// Export 校验导出参数，返回的函数按用户列表的筛选条件分批读取并写出，每批写完刷新到客户端，
+// 不在内存中保留全部结果。filter/fields 不合法时返回参数错误，此时尚未写出任何内容
+func (s *adminUserService) Export(ctx context.Context, req *dtoUser.ExportRequest) (func(w io.Writer) error, error) {
+	lq, err := dao.UserQuerySchema.Parse(req.Filter, "", req.Fields)
+	if err != nil {
+		if errors.Is(err, query.ErrInvalid) {
+			return nil, apperr.New(constant.ParamError, err.Error())
+		}
+		return nil, err
+	}
+	format := req.Format
+	if format == "" {
+		format = bulk.CSV
+	}
+	columns := lq.Fields
+	if len(columns) == 0 {
+		columns = userExportColumns
+	}
+
+	return func(w io.Writer) error {
+		enc, err := bulk.NewEncoder(w, format, columns)
+		if err != nil {
+			return err
+		}
+		err = s.userDAO.Export(ctx, &req.ListRequest, lq, config.Config.Bulk.BatchSize, func(users []model.User) error {
+			for i := range users {
+				if err := enc.Encode(userRecord(&users[i])); err != nil {
+					return err
+				}
+			}
+			if err := enc.Flush(); err != nil {
+				return err
+			}
+			if f, ok := w.(http.Flusher); ok {
+				f.Flush()
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		return enc.Flush()
+	}, nil
+}
+
+// userRecord 导出的一行，字段名与 dto/user.User 的 JSON 字段一致
+func userRecord(u *model.User) map[string]interface{} {
+	return map[string]interface{}{
+		"id":             u.ID,
+		"username":       u.Username,
+		"email":          u.Email,
+		"email_verified": u.EmailVerifiedAt != nil,
+		"created_at":     u.CreatedAt,
+		"updated_at":     u.UpdatedAt,
+	}
+}
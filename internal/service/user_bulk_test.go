@@ -0,0 +1,35 @@
+package service
+
+import (
+	"testing"
+
+	dtoUser "github.com/liuchen/gin-craft/internal/dto/user"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckImportRow(t *testing.T) {
+	s := NewAdminUserService()
+	im := &userImport{
+		resp:      &dtoUser.ImportResponse{},
+		usernames: make(map[string]int),
+		emails:    make(map[string]int),
+	}
+
+	s.checkImportRow(im, 2, map[string]string{"username": "alice", "email": "alice@example.com", "password": "s3cret-pass"})
+	s.checkImportRow(im, 3, map[string]string{"username": "al", "email": "al@example.com", "password": "s3cret-pass"})
+	s.checkImportRow(im, 4, map[string]string{"username": "bob", "email": "not-an-email", "password": "s3cret-pass"})
+	s.checkImportRow(im, 5, map[string]string{"username": "Alice", "email": "alice2@example.com", "password": "s3cret-pass"})
+	s.checkImportRow(im, 6, map[string]string{"username": "carol", "email": "ALICE@example.com", "password": "s3cret-pass"})
+
+	require.Len(t, im.batch, 1)
+	assert.Equal(t, 2, im.batch[0].line)
+	assert.Equal(t, 4, im.resp.Failed)
+	lines := make([]int, 0, len(im.resp.Errors))
+	for _, e := range im.resp.Errors {
+		lines = append(lines, e.Line)
+	}
+	assert.Equal(t, []int{3, 4, 5, 6}, lines)
+	assert.Equal(t, "用户名与第 2 行重复", im.resp.Errors[2].Message)
+	assert.Equal(t, "邮箱与第 2 行重复", im.resp.Errors[3].Message)
+}
@@ -0,0 +1,131 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/liuchen/gin-craft/internal/constant"
+	"github.com/liuchen/gin-craft/internal/model"
+	"github.com/liuchen/gin-craft/internal/pkg/config"
+	pkgCtx "github.com/liuchen/gin-craft/internal/pkg/context"
+	apperr "github.com/liuchen/gin-craft/internal/pkg/errors"
+	"github.com/liuchen/gin-craft/internal/pkg/redis"
+	goredis "github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// userStatusCacheKeyPrefix 账号状态缓存 key 前缀，完整 key 为 user:status:<用户ID>
+const userStatusCacheKeyPrefix = "user:status:"
+
+// transition 按状态机迁移账号状态，记录原因、时间与操作人并写审计；
+// 迁移到 suspended / deleted 时吊销该用户全部会话。状态未变化时不做任何操作
+func (s *userService) transition(ctx context.Context, u *model.User, to, reason string) error {
+	from := u.Status
+	if from == to {
+		return nil
+	}
+	if !u.CanTransitionTo(to) {
+		return apperr.Newf(constant.UserStatusInvalid, "%s → %s", from, to)
+	}
+	actorID := pkgCtx.MustGetContext(ctx).GetUserID()
+	err := s.tx.Do(ctx, func(ctx context.Context) error {
+		err := s.userDAO.ChangeStatus(ctx, u.ID, from, to, reason, actorID, time.Now())
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return apperr.New(constant.UserStatusInvalid, "账号状态已被修改，请刷新后重试")
+		}
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	u.Status = to
+	s.invalidateStatus(ctx, u.ID)
+	recordAudit(ctx, statusAuditAction(from, to), userTarget(u.ID),
+		map[string]interface{}{"status": from},
+		map[string]interface{}{"status": to, "reason": reason},
+	)
+
+	if to == constant.UserStatusSuspended || to == constant.UserStatusDeleted {
+		return s.revokeAllSessions(ctx, u.ID)
+	}
+	return nil
+}
+
+// CheckStatus 校验账号处于 active 状态，供认证中间件在每个请求上调用，
+// 使停用的账号即使持有未过期的令牌也被拒绝。状态缓存在 Redis，变更时立即失效
+func (s *userService) CheckStatus(ctx context.Context, userID string) error {
+	status, err := s.cachedStatus(ctx, userID)
+	if err != nil {
+		return err
+	}
+	return statusError(status)
+}
+
+// cachedStatus 读取账号状态，优先读取 Redis 缓存；缓存不可用时直接查库
+func (s *userService) cachedStatus(ctx context.Context, userID string) (string, error) {
+	appCtx := pkgCtx.MustGetContext(ctx)
+	key := userStatusCacheKeyPrefix + userID
+
+	status, err := redis.GetRedisClient().Get(ctx, key)
+	if err == nil {
+		return status, nil
+	}
+	if !errors.Is(err, goredis.Nil) {
+		appCtx.LogWarn("读取账号状态缓存失败", zap.String("user_id", userID), zap.Error(err))
+	}
+
+	uid, err := strconv.ParseUint(userID, 10, 64)
+	if err != nil {
+		return "", apperr.New(constant.TokenInvalid)
+	}
+	status, err = s.userDAO.GetStatus(ctx, uint(uid))
+	if err != nil {
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return "", err
+		}
+		// 已彻底删除的用户
+		status = constant.UserStatusDeleted
+	}
+	ttl := time.Duration(config.Config.Account.StatusCacheTTL) * time.Second
+	if err := redis.GetRedisClient().Set(ctx, key, status, ttl); err != nil {
+		appCtx.LogWarn("写入账号状态缓存失败", zap.String("user_id", userID), zap.Error(err))
+	}
+	return status, nil
+}
+
+// invalidateStatus 清除账号状态缓存
+func (s *userService) invalidateStatus(ctx context.Context, userID uint) {
+	key := userStatusCacheKeyPrefix + strconv.FormatUint(uint64(userID), 10)
+	if err := redis.GetRedisClient().Del(ctx, key); err != nil {
+		pkgCtx.MustGetContext(ctx).LogWarn("清除账号状态缓存失败", zap.Uint("user_id", userID), zap.Error(err))
+	}
+}
+
+// statusError 非 active 状态对应的错误；已删除的账号按用户不存在处理
+func statusError(status string) error {
+	switch status {
+	case constant.UserStatusPending:
+		return apperr.New(constant.UserPending)
+	case constant.UserStatusSuspended:
+		return apperr.New(constant.UserSuspended)
+	case constant.UserStatusDeleted:
+		return apperr.New(constant.UserNotExist)
+	}
+	return nil
+}
+
+// statusAuditAction 状态迁移对应的审计动作
+func statusAuditAction(from, to string) string {
+	switch {
+	case to == constant.UserStatusDeleted:
+		return auditUserDelete
+	case from == constant.UserStatusDeleted:
+		return auditUserRestore
+	case to == constant.UserStatusSuspended:
+		return auditUserSuspend
+	}
+	return auditUserActivate
+}
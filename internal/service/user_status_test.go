@@ -0,0 +1,52 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/liuchen/gin-craft/internal/constant"
+	"github.com/liuchen/gin-craft/internal/model"
+	apperr "github.com/liuchen/gin-craft/internal/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUserStatusTransitions(t *testing.T) {
+	cases := []struct {
+		from, to string
+		ok       bool
+	}{
+		{constant.UserStatusPending, constant.UserStatusActive, true},
+		{constant.UserStatusActive, constant.UserStatusSuspended, true},
+		{constant.UserStatusSuspended, constant.UserStatusActive, true},
+		{constant.UserStatusActive, constant.UserStatusDeleted, true},
+		{constant.UserStatusDeleted, constant.UserStatusActive, true},
+		{constant.UserStatusActive, constant.UserStatusPending, false},
+		{constant.UserStatusDeleted, constant.UserStatusSuspended, false},
+		{"", constant.UserStatusActive, false},
+	}
+	for _, c := range cases {
+		u := &model.User{Status: c.from}
+		assert.Equal(t, c.ok, u.CanTransitionTo(c.to), "%s -> %s", c.from, c.to)
+	}
+}
+
+func TestStatusError(t *testing.T) {
+	assert.NoError(t, statusError(constant.UserStatusActive))
+
+	for status, code := range map[string]int{
+		constant.UserStatusPending:   constant.UserPending,
+		constant.UserStatusSuspended: constant.UserSuspended,
+		constant.UserStatusDeleted:   constant.UserNotExist,
+	} {
+		appErr, ok := apperr.GetAppError(statusError(status))
+		require.True(t, ok, status)
+		assert.Equal(t, code, appErr.GetCode(), status)
+	}
+}
+
+func TestStatusAuditAction(t *testing.T) {
+	assert.Equal(t, auditUserSuspend, statusAuditAction(constant.UserStatusActive, constant.UserStatusSuspended))
+	assert.Equal(t, auditUserActivate, statusAuditAction(constant.UserStatusPending, constant.UserStatusActive))
+	assert.Equal(t, auditUserDelete, statusAuditAction(constant.UserStatusSuspended, constant.UserStatusDeleted))
+	assert.Equal(t, auditUserRestore, statusAuditAction(constant.UserStatusDeleted, constant.UserStatusActive))
+}
@@ -0,0 +1,78 @@
+package apikey
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"strings"
+)
+
+// Prefix 密钥前缀，便于在日志与代码仓库扫描中识别泄露的密钥
+const Prefix = "gck"
+
+const (
+	idBytes     = 6  // 公开 ID，12 位十六进制
+	secretBytes = 32 // 密钥主体熵
+)
+
+// ErrMalformed 密钥格式错误
+var ErrMalformed = errors.New("apikey: malformed key")
+
+// Key 新生成的密钥。Raw 仅在创建时返回给调用方，服务端只保存 ID 与 Hash
+type Key struct {
+	Raw  string // 完整密钥：gck_<id>_<secret>
+	ID   string // 公开 ID，用于查找记录与展示
+	Hash string // Raw 的 SHA-256 十六进制摘要
+}
+
+// Generate 生成新密钥
+func Generate() (*Key, error) {
+	id := make([]byte, idBytes)
+	if _, err := rand.Read(id); err != nil {
+		return nil, err
+	}
+	secret := make([]byte, secretBytes)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, err
+	}
+	k := &Key{ID: hex.EncodeToString(id)}
+	k.Raw = Prefix + "_" + k.ID + "_" + base64.RawURLEncoding.EncodeToString(secret)
+	k.Hash = Hash(k.Raw)
+	return k, nil
+}
+
+// Parse 解析密钥中的公开 ID
+func Parse(raw string) (string, error) {
+	parts := strings.SplitN(raw, "_", 3) // secret 为 base64url，可能含 "_"
+	if len(parts) != 3 || parts[0] != Prefix || len(parts[1]) != idBytes*2 || parts[2] == "" {
+		return "", ErrMalformed
+	}
+	if _, err := hex.DecodeString(parts[1]); err != nil {
+		return "", ErrMalformed
+	}
+	return parts[1], nil
+}
+
+// Hash 计算密钥摘要。密钥本身为高熵随机串，无需慢哈希
+func Hash(raw string) string {
+	sum := sha256.Sum256([]byte(raw))
+	return hex.EncodeToString(sum[:])
+}
+
+// SigningSecret 请求签名使用的 HMAC 密钥：HMAC-SHA256(pepper, hash)。
+// pepper 只保存在服务端配置中，仅凭数据库里的摘要无法算出签名密钥；
+// 服务端每次校验时重新计算，创建密钥时与完整密钥一同返回给调用方
+func SigningSecret(pepper []byte, hash string) string {
+	mac := hmac.New(sha256.New, pepper)
+	mac.Write([]byte(hash))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// Verify 以常量时间比较密钥与摘要
+func Verify(raw, hash string) bool {
+	return subtle.ConstantTimeCompare([]byte(Hash(raw)), []byte(hash)) == 1
+}
@@ -0,0 +1,54 @@
+package apikey
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerateAndVerify(t *testing.T) {
+	k, err := Generate()
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(k.Raw, Prefix+"_"+k.ID+"_"))
+
+	id, err := Parse(k.Raw)
+	require.NoError(t, err)
+	assert.Equal(t, k.ID, id)
+
+	assert.True(t, Verify(k.Raw, k.Hash))
+	assert.False(t, Verify(k.Raw+"x", k.Hash))
+
+	other, err := Generate()
+	require.NoError(t, err)
+	assert.NotEqual(t, k.ID, other.ID)
+	assert.False(t, Verify(other.Raw, k.Hash))
+}
+
+func TestParseMalformed(t *testing.T) {
+	for _, raw := range []string{
+		"",
+		"secret",
+		"gck_0123456789ab",
+		"abc_0123456789ab_secret",
+		"gck_0123_secret",
+		"gck_zzzzzzzzzzzz_secret",
+		"gck_0123456789ab_",
+	} {
+		_, err := Parse(raw)
+		assert.ErrorIs(t, err, ErrMalformed, raw)
+	}
+}
+
+func TestSigningSecret(t *testing.T) {
+	k, err := Generate()
+	require.NoError(t, err)
+
+	secret := SigningSecret([]byte("pepper"), k.Hash)
+	assert.Equal(t, secret, SigningSecret([]byte("pepper"), k.Hash))
+	// 只有摘要、没有服务端 pepper 时得不到同一个签名密钥
+	assert.NotEqual(t, secret, SigningSecret(nil, k.Hash))
+	assert.NotEqual(t, secret, SigningSecret([]byte("other"), k.Hash))
+	assert.NotContains(t, secret, k.Hash)
+}
@@ -0,0 +1,193 @@
+package audit
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// ErrClosed 记录器已关闭
+var ErrClosed = errors.New("audit: recorder closed")
+
+// Change 单个字段变更前后的值
+type Change struct {
+	Before interface{} `json:"before"`
+	After  interface{} `json:"after"`
+}
+
+// Entry 一条审计事件
+type Entry struct {
+	ActorID   string            // 操作人ID，匿名操作（如重置密码）为空
+	ActorName string            // 操作人用户名
+	Action    string            // 动作，如 user.disable
+	Target    string            // 目标，如 user:42
+	Changes   map[string]Change // 变更字段，见 Diff
+	TraceID   string
+	IP        string
+	UserAgent string
+	CreatedAt time.Time
+}
+
+// Diff 比较变更前后的字段，只保留取值不同的字段。after 中未出现的字段视为未修改，
+// 便于只传入本次更新的字段；after 为 nil 表示目标被删除，before 中的字段全部记为删除
+func Diff(before, after map[string]interface{}) map[string]Change {
+	changes := make(map[string]Change)
+	if after == nil {
+		for k, b := range before {
+			changes[k] = Change{Before: b}
+		}
+		return changes
+	}
+	for k, a := range after {
+		if b := before[k]; !reflect.DeepEqual(b, a) {
+			changes[k] = Change{Before: b, After: a}
+		}
+	}
+	return changes
+}
+
+// Sink 审计事件的持久化目标
+type Sink interface {
+	Write(ctx context.Context, entries []Entry) error
+}
+
+// SinkFunc 函数适配为 Sink
+type SinkFunc func(ctx context.Context, entries []Entry) error
+
+// Write 实现 Sink
+func (f SinkFunc) Write(ctx context.Context, entries []Entry) error {
+	return f(ctx, entries)
+}
+
+// Options 异步记录器参数，零值使用默认值
+type Options struct {
+	BufferSize    int                    // 缓冲队列长度，默认 1024；队列满时丢弃事件而不阻塞业务请求
+	BatchSize     int                    // 单次写入的最大条数，默认 100
+	FlushInterval time.Duration          // 未攒满一批时的最长等待时间，默认 1s
+	WriteTimeout  time.Duration          // 单次写入超时，默认 5s
+	OnError       func(err error, n int) // 写入失败回调，n 为丢失的条数
+	Now           func() time.Time       // 时间源，测试用
+}
+
+// Recorder 异步审计记录器：Record 只入队，后台协程按批写入 Sink
+type Recorder struct {
+	sink    Sink
+	opts    Options
+	queue   chan Entry
+	done    chan struct{}
+	mu      sync.RWMutex
+	closed  bool
+	dropped atomic.Int64
+}
+
+// NewRecorder 创建并启动异步记录器
+func NewRecorder(sink Sink, opts Options) *Recorder {
+	if opts.BufferSize <= 0 {
+		opts.BufferSize = 1024
+	}
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = 100
+	}
+	if opts.FlushInterval <= 0 {
+		opts.FlushInterval = time.Second
+	}
+	if opts.WriteTimeout <= 0 {
+		opts.WriteTimeout = 5 * time.Second
+	}
+	if opts.Now == nil {
+		opts.Now = time.Now
+	}
+	r := &Recorder{
+		sink:  sink,
+		opts:  opts,
+		queue: make(chan Entry, opts.BufferSize),
+		done:  make(chan struct{}),
+	}
+	go r.run()
+	return r
+}
+
+// Record 提交一条审计事件，不阻塞；队列已满或记录器已关闭时返回 false
+func (r *Recorder) Record(e Entry) bool {
+	if e.CreatedAt.IsZero() {
+		e.CreatedAt = r.opts.Now()
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if r.closed {
+		r.dropped.Add(1)
+		return false
+	}
+	select {
+	case r.queue <- e:
+		return true
+	default:
+		r.dropped.Add(1)
+		return false
+	}
+}
+
+// Dropped 因队列已满或已关闭被丢弃的事件数
+func (r *Recorder) Dropped() int64 {
+	return r.dropped.Load()
+}
+
+// Close 停止接收新事件并写完队列中剩余的事件；ctx 到期时返回 ctx.Err()
+func (r *Recorder) Close(ctx context.Context) error {
+	r.mu.Lock()
+	if r.closed {
+		r.mu.Unlock()
+		return ErrClosed
+	}
+	r.closed = true
+	close(r.queue)
+	r.mu.Unlock()
+
+	select {
+	case <-r.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (r *Recorder) run() {
+	defer close(r.done)
+	ticker := time.NewTicker(r.opts.FlushInterval)
+	defer ticker.Stop()
+
+	batch := make([]Entry, 0, r.opts.BatchSize)
+	for {
+		select {
+		case e, ok := <-r.queue:
+			if !ok {
+				r.flush(batch)
+				return
+			}
+			batch = append(batch, e)
+			if len(batch) >= r.opts.BatchSize {
+				r.flush(batch)
+				batch = make([]Entry, 0, r.opts.BatchSize)
+			}
+		case <-ticker.C:
+			if len(batch) > 0 {
+				r.flush(batch)
+				batch = make([]Entry, 0, r.opts.BatchSize)
+			}
+		}
+	}
+}
+
+func (r *Recorder) flush(batch []Entry) {
+	if len(batch) == 0 {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), r.opts.WriteTimeout)
+	defer cancel()
+	if err := r.sink.Write(ctx, batch); err != nil && r.opts.OnError != nil {
+		r.opts.OnError(err, len(batch))
+	}
+}
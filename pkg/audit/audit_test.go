@@ -0,0 +1,91 @@
+package audit
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type memorySink struct {
+	mu      sync.Mutex
+	batches [][]Entry
+	err     error
+}
+
+func (s *memorySink) Write(_ context.Context, entries []Entry) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.batches = append(s.batches, entries)
+	return s.err
+}
+
+func (s *memorySink) count() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	n := 0
+	for _, b := range s.batches {
+		n += len(b)
+	}
+	return n
+}
+
+func TestDiff(t *testing.T) {
+	before := map[string]interface{}{"role": "user", "status": "active", "email": "a@example.com"}
+
+	// after 中未出现的 email 视为未修改
+	changes := Diff(before, map[string]interface{}{"role": "admin", "status": "active", "reason": "promote"})
+	assert.Equal(t, map[string]Change{
+		"role":   {Before: "user", After: "admin"},
+		"reason": {After: "promote"},
+	}, changes)
+
+	// after 为 nil 表示删除
+	changes = Diff(before, nil)
+	assert.Len(t, changes, 3)
+	assert.Equal(t, Change{Before: "user"}, changes["role"])
+
+	assert.Empty(t, Diff(before, map[string]interface{}{}))
+}
+
+func TestRecorderBatchesAndDrainsOnClose(t *testing.T) {
+	sink := &memorySink{}
+	r := NewRecorder(sink, Options{BatchSize: 2, FlushInterval: time.Hour})
+
+	for i := 0; i < 5; i++ {
+		require.True(t, r.Record(Entry{Action: "user.update"}))
+	}
+	require.NoError(t, r.Close(context.Background()))
+
+	assert.Equal(t, 5, sink.count())
+	assert.Len(t, sink.batches, 3)
+	assert.False(t, sink.batches[0][0].CreatedAt.IsZero())
+
+	assert.False(t, r.Record(Entry{Action: "user.update"}))
+	assert.EqualValues(t, 1, r.Dropped())
+	assert.ErrorIs(t, r.Close(context.Background()), ErrClosed)
+}
+
+func TestRecorderFlushInterval(t *testing.T) {
+	sink := &memorySink{}
+	r := NewRecorder(sink, Options{BatchSize: 100, FlushInterval: 10 * time.Millisecond})
+	defer r.Close(context.Background())
+
+	r.Record(Entry{Action: "user.update"})
+	assert.Eventually(t, func() bool { return sink.count() == 1 }, time.Second, 5*time.Millisecond)
+}
+
+func TestRecorderReportsWriteErrors(t *testing.T) {
+	sink := &memorySink{err: errors.New("db down")}
+	var lost int
+	r := NewRecorder(sink, Options{OnError: func(err error, n int) { lost += n }})
+
+	r.Record(Entry{Action: "user.update"})
+	r.Record(Entry{Action: "user.delete"})
+	require.NoError(t, r.Close(context.Background()))
+	assert.Equal(t, 2, lost)
+}
@@ -0,0 +1,233 @@
+// Package bulk 批量导入导出的流式编解码：CSV（首行为表头）与 JSONL（每行一个 JSON 对象），
+// 逐行读写，不把整个文件载入内存
+package bulk
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// 支持的格式
+const (
+	CSV   = "csv"
+	JSONL = "jsonl"
+)
+
+// ErrUnsupportedFormat 不支持的格式
+var ErrUnsupportedFormat = errors.New("bulk: unsupported format")
+
+// maxLineSize JSONL 单行的最大字节数
+const maxLineSize = 1 << 20
+
+// RowError 单行数据无法解析，调用方可记录后继续读取下一行
+type RowError struct {
+	Line int
+	Err  error
+}
+
+func (e *RowError) Error() string {
+	return fmt.Sprintf("line %d: %v", e.Line, e.Err)
+}
+
+func (e *RowError) Unwrap() error {
+	return e.Err
+}
+
+// Decoder 逐行读取记录，字段名统一转为小写
+type Decoder interface {
+	// Decode 读取下一行，返回记录及其所在行号；读完时返回 io.EOF，
+	// 单行格式错误时返回 *RowError，其他错误表示无法继续读取
+	Decode() (record map[string]string, line int, err error)
+}
+
+// NewDecoder 按格式创建解码器
+func NewDecoder(r io.Reader, format string) (Decoder, error) {
+	switch format {
+	case CSV:
+		cr := csv.NewReader(r)
+		cr.TrimLeadingSpace = true
+		cr.ReuseRecord = true
+		return &csvDecoder{r: cr}, nil
+	case JSONL:
+		s := bufio.NewScanner(r)
+		s.Buffer(make([]byte, 0, 64*1024), maxLineSize)
+		return &jsonlDecoder{s: s}, nil
+	}
+	return nil, fmt.Errorf("%w: %q", ErrUnsupportedFormat, format)
+}
+
+type csvDecoder struct {
+	r      *csv.Reader
+	header []string
+}
+
+func (d *csvDecoder) Decode() (map[string]string, int, error) {
+	if d.header == nil {
+		header, err := d.r.Read()
+		if err != nil {
+			return nil, 0, err
+		}
+		d.header = make([]string, len(header))
+		for i, h := range header {
+			// 去掉 Excel 导出文件开头的 BOM
+			d.header[i] = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(h, "\ufeff")))
+		}
+	}
+
+	rec, err := d.r.Read()
+	line, _ := d.r.FieldPos(0)
+	if err != nil {
+		var pe *csv.ParseError
+		if errors.As(err, &pe) {
+			return nil, pe.StartLine, &RowError{Line: pe.StartLine, Err: pe.Err}
+		}
+		return nil, line, err
+	}
+	out := make(map[string]string, len(d.header))
+	for i, h := range d.header {
+		out[h] = strings.TrimSpace(rec[i])
+	}
+	return out, line, nil
+}
+
+type jsonlDecoder struct {
+	s    *bufio.Scanner
+	line int
+}
+
+func (d *jsonlDecoder) Decode() (map[string]string, int, error) {
+	for d.s.Scan() {
+		d.line++
+		b := d.s.Bytes()
+		if len(strings.TrimSpace(string(b))) == 0 {
+			continue
+		}
+		var obj map[string]interface{}
+		if err := json.Unmarshal(b, &obj); err != nil {
+			return nil, d.line, &RowError{Line: d.line, Err: err}
+		}
+		out := make(map[string]string, len(obj))
+		for k, v := range obj {
+			switch v := v.(type) {
+			case nil:
+			case string:
+				out[strings.ToLower(k)] = strings.TrimSpace(v)
+			default:
+				out[strings.ToLower(k)] = fmt.Sprint(v)
+			}
+		}
+		return out, d.line, nil
+	}
+	if err := d.s.Err(); err != nil {
+		return nil, d.line + 1, err
+	}
+	return nil, d.line, io.EOF
+}
+
+// Encoder 逐行写出记录。只写出 columns 中的字段，CSV 在第一条记录前写表头（没有记录时在 Flush 时写出），
+// 以公式字符开头的字符串前加 ' 防止在电子表格中作为公式执行；
+// 写出的数据先进入缓冲，调用方应定期 Flush 将其发送给客户端
+type Encoder interface {
+	Encode(record map[string]interface{}) error
+	Flush() error
+}
+
+// NewEncoder 按格式创建编码器
+func NewEncoder(w io.Writer, format string, columns []string) (Encoder, error) {
+	switch format {
+	case CSV:
+		return &csvEncoder{w: csv.NewWriter(w), columns: columns}, nil
+	case JSONL:
+		bw := bufio.NewWriter(w)
+		return &jsonlEncoder{w: bw, enc: json.NewEncoder(bw), columns: columns}, nil
+	}
+	return nil, fmt.Errorf("%w: %q", ErrUnsupportedFormat, format)
+}
+
+type csvEncoder struct {
+	w          *csv.Writer
+	columns    []string
+	headerDone bool
+}
+
+func (e *csvEncoder) Encode(record map[string]interface{}) error {
+	if !e.headerDone {
+		if err := e.w.Write(e.columns); err != nil {
+			return err
+		}
+		e.headerDone = true
+	}
+	row := make([]string, len(e.columns))
+	for i, c := range e.columns {
+		row[i] = formatValue(record[c])
+		if _, ok := record[c].(string); ok {
+			row[i] = escapeFormula(row[i])
+		}
+	}
+	return e.w.Write(row)
+}
+
+// escapeFormula 以 = + - @ 制表符或回车开头的单元格在电子表格中会被当作公式执行（CSV 注入），前加 ' 作为纯文本
+func escapeFormula(s string) string {
+	if s != "" && strings.ContainsRune("=+-@\t\r", rune(s[0])) {
+		return "'" + s
+	}
+	return s
+}
+
+func (e *csvEncoder) Flush() error {
+	if !e.headerDone {
+		// 没有任何记录时也输出表头
+		if err := e.w.Write(e.columns); err != nil {
+			return err
+		}
+		e.headerDone = true
+	}
+	e.w.Flush()
+	return e.w.Error()
+}
+
+type jsonlEncoder struct {
+	w       *bufio.Writer
+	enc     *json.Encoder
+	columns []string
+}
+
+func (e *jsonlEncoder) Encode(record map[string]interface{}) error {
+	picked := make(map[string]interface{}, len(e.columns))
+	for _, c := range e.columns {
+		picked[c] = record[c]
+	}
+	return e.enc.Encode(picked)
+}
+
+func (e *jsonlEncoder) Flush() error {
+	return e.w.Flush()
+}
+
+func formatValue(v interface{}) string {
+	switch v := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case time.Time:
+		return v.Format(time.RFC3339)
+	case *time.Time:
+		if v == nil {
+			return ""
+		}
+		return v.Format(time.RFC3339)
+	case bool:
+		return strconv.FormatBool(v)
+	default:
+		return fmt.Sprint(v)
+	}
+}
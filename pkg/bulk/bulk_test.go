@@ -0,0 +1,128 @@
+package bulk
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type decoded struct {
+	record map[string]string
+	line   int
+	err    error
+}
+
+func decodeAll(t *testing.T, d Decoder) []decoded {
+	var out []decoded
+	for {
+		rec, line, err := d.Decode()
+		if errors.Is(err, io.EOF) {
+			return out
+		}
+		var re *RowError
+		if err != nil && !errors.As(err, &re) {
+			require.NoError(t, err)
+		}
+		out = append(out, decoded{rec, line, err})
+	}
+}
+
+func TestCSVDecoder(t *testing.T) {
+	in := "\ufeffUsername, Email ,password\n" +
+		"alice,alice@example.com,secret1\n" +
+		"bob,bob@example.com\n" +
+		"\"carol\",carol@example.com,\"se,cret\"\n"
+	d, err := NewDecoder(strings.NewReader(in), CSV)
+	require.NoError(t, err)
+
+	rows := decodeAll(t, d)
+	require.Len(t, rows, 3)
+	assert.Equal(t, map[string]string{"username": "alice", "email": "alice@example.com", "password": "secret1"}, rows[0].record)
+	assert.Equal(t, 2, rows[0].line)
+	var re *RowError
+	require.ErrorAs(t, rows[1].err, &re)
+	assert.Equal(t, 3, re.Line)
+	assert.Equal(t, "se,cret", rows[2].record["password"])
+	assert.Equal(t, 4, rows[2].line)
+}
+
+func TestJSONLDecoder(t *testing.T) {
+	in := `{"username":"alice","Email":"alice@example.com","age":3}` + "\n\n" +
+		`{"username":` + "\n" +
+		`{"username":"bob","email":null}` + "\n"
+	d, err := NewDecoder(strings.NewReader(in), JSONL)
+	require.NoError(t, err)
+
+	rows := decodeAll(t, d)
+	require.Len(t, rows, 3)
+	assert.Equal(t, map[string]string{"username": "alice", "email": "alice@example.com", "age": "3"}, rows[0].record)
+	assert.Equal(t, 1, rows[0].line)
+	assert.Error(t, rows[1].err)
+	assert.Equal(t, 3, rows[1].line)
+	assert.Equal(t, map[string]string{"username": "bob"}, rows[2].record)
+	assert.Equal(t, 4, rows[2].line)
+
+	_, err = NewDecoder(strings.NewReader(""), "xlsx")
+	assert.ErrorIs(t, err, ErrUnsupportedFormat)
+}
+
+func TestEncoder(t *testing.T) {
+	at := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	records := []map[string]interface{}{
+		{"id": uint(1), "username": "alice", "verified": true, "created_at": at, "secret": "x"},
+		{"id": uint(2), "username": "b,ob", "verified": false, "created_at": at},
+	}
+	columns := []string{"id", "username", "verified", "created_at"}
+
+	var buf bytes.Buffer
+	enc, err := NewEncoder(&buf, CSV, columns)
+	require.NoError(t, err)
+	for _, r := range records {
+		require.NoError(t, enc.Encode(r))
+	}
+	require.NoError(t, enc.Flush())
+	assert.Equal(t, "id,username,verified,created_at\n"+
+		"1,alice,true,2024-01-02T03:04:05Z\n"+
+		"2,\"b,ob\",false,2024-01-02T03:04:05Z\n", buf.String())
+
+	buf.Reset()
+	enc, err = NewEncoder(&buf, JSONL, []string{"id", "username"})
+	require.NoError(t, err)
+	require.NoError(t, enc.Encode(records[0]))
+	require.NoError(t, enc.Flush())
+	assert.Equal(t, `{"id":1,"username":"alice"}`+"\n", buf.String())
+
+	// 没有记录时 CSV 仍输出表头
+	buf.Reset()
+	enc, err = NewEncoder(&buf, CSV, columns)
+	require.NoError(t, err)
+	require.NoError(t, enc.Flush())
+	assert.Equal(t, "id,username,verified,created_at\n", buf.String())
+}
+
+func TestCSVEncoderEscapesFormulas(t *testing.T) {
+	var buf bytes.Buffer
+	enc, err := NewEncoder(&buf, CSV, []string{"id", "username"})
+	require.NoError(t, err)
+	for i, name := range []string{`=HYPERLINK("http://evil","x")`, "+1", "-1", "@SUM(A1)", "\tx", "\rx", "alice", ""} {
+		require.NoError(t, enc.Encode(map[string]interface{}{"id": -(i + 1), "username": name}))
+	}
+	require.NoError(t, enc.Flush())
+
+	rows, err := csv.NewReader(&buf).ReadAll()
+	require.NoError(t, err)
+	got := make([]string, 0, len(rows)-1)
+	for _, row := range rows[1:] {
+		got = append(got, row[1])
+	}
+	assert.Equal(t, []string{`'=HYPERLINK("http://evil","x")`, "'+1", "'-1", "'@SUM(A1)", "'\tx", "'\rx", "alice", ""}, got)
+	// 非字符串的数值不转义
+	assert.Equal(t, "-1", rows[1][0])
+}
@@ -0,0 +1,39 @@
+package cursor
+
+import (
+	"encoding/base64"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCodecRoundTrip(t *testing.T) {
+	c := NewCodec([]byte("secret"))
+	cur := Cursor{Sort: "-created_at", Value: "2024-01-01T00:00:00Z", ID: 42}
+
+	raw, err := c.Encode(cur)
+	require.NoError(t, err)
+	got, err := c.Decode(raw)
+	require.NoError(t, err)
+	assert.Equal(t, cur, got)
+}
+
+func TestCodecRejectsTampered(t *testing.T) {
+	c := NewCodec([]byte("secret"))
+	raw, err := c.Encode(Cursor{Sort: "-id", ID: 42})
+	require.NoError(t, err)
+
+	_, err = NewCodec([]byte("other")).Decode(raw)
+	assert.ErrorIs(t, err, ErrInvalid)
+
+	// 修改 payload 后签名不匹配
+	payload, sig, _ := strings.Cut(raw, ".")
+	forged := base64.RawURLEncoding.EncodeToString([]byte(`{"s":"-id","i":1}`))
+	assert.NotEqual(t, payload, forged)
+	for _, bad := range []string{"", payload, forged + "." + sig, raw + "x"} {
+		_, err = c.Decode(bad)
+		assert.ErrorIs(t, err, ErrInvalid, bad)
+	}
+}
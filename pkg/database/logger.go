@@ -12,11 +12,34 @@ import (
 	gormlogger "gorm.io/gorm/logger"
 )
 
+// ContextFieldsFunc 从查询的 ctx 中取出随 SQL 日志输出的字段，如链路ID、用户ID与路由
+type ContextFieldsFunc func(ctx context.Context) []zap.Field
+
+// contextFields 新建连接的日志使用的字段提取函数
+var contextFields ContextFieldsFunc = traceIDField
+
+// SetContextFields 设置 SQL 日志的上下文字段提取函数，须在 Connect 之前调用；
+// 默认只读取 ctx 中以 logger.TraceIDKey 存放的链路ID
+func SetContextFields(fn ContextFieldsFunc) {
+	if fn == nil {
+		fn = traceIDField
+	}
+	contextFields = fn
+}
+
+func traceIDField(ctx context.Context) []zap.Field {
+	if v, ok := ctx.Value(logger.TraceIDKey).(string); ok && v != "" {
+		return []zap.Field{zap.String("trace_id", v)}
+	}
+	return nil
+}
+
 // GormLogger 实现gorm的日志接口
 type GormLogger struct {
 	SlowThreshold         time.Duration
 	SourceField           string
 	SkipErrRecordNotFound bool
+	ContextFields         ContextFieldsFunc
 	logger                *zap.Logger // 使用模块化logger
 }
 
@@ -25,6 +48,7 @@ func NewGormLogger() *GormLogger {
 	return &GormLogger{
 		SlowThreshold:         time.Second,                // 慢查询阈值
 		SkipErrRecordNotFound: true,                       // 是否跳过记录未找到错误
+		ContextFields:         contextFields,              // 从 ctx 中取关联字段
 		logger:                logger.GetDatabaseLogger(), // 使用数据库模块logger
 	}
 }
@@ -37,17 +61,25 @@ func (l *GormLogger) LogMode(_ gormlogger.LogLevel) gormlogger.Interface {
 
 // Info 打印信息
 func (l *GormLogger) Info(ctx context.Context, msg string, data ...interface{}) {
-	l.logger.Info(fmt.Sprintf(msg, data...))
+	l.logger.Info(fmt.Sprintf(msg, data...), l.requestFields(ctx)...)
 }
 
 // Warn 打印警告
 func (l *GormLogger) Warn(ctx context.Context, msg string, data ...interface{}) {
-	l.logger.Warn(fmt.Sprintf(msg, data...))
+	l.logger.Warn(fmt.Sprintf(msg, data...), l.requestFields(ctx)...)
 }
 
 // Error 打印错误
 func (l *GormLogger) Error(ctx context.Context, msg string, data ...interface{}) {
-	l.logger.Error(fmt.Sprintf(msg, data...))
+	l.logger.Error(fmt.Sprintf(msg, data...), l.requestFields(ctx)...)
+}
+
+// requestFields 从 ctx 中取出关联请求的日志字段
+func (l *GormLogger) requestFields(ctx context.Context) []zap.Field {
+	if ctx == nil || l.ContextFields == nil {
+		return nil
+	}
+	return l.ContextFields(ctx)
 }
 
 // Trace 记录SQL执行
@@ -55,14 +87,6 @@ func (l *GormLogger) Trace(ctx context.Context, begin time.Time, fc func() (stri
 	elapsed := time.Since(begin)
 	sql, rows := fc()
 
-	// 获取跟踪ID
-	var traceID string
-	if ctx != nil {
-		if v, ok := ctx.Value(logger.TraceIDKey).(string); ok {
-			traceID = v
-		}
-	}
-
 	// 构建日志字段
 	fields := []zap.Field{
 		zap.String("sql", sql),
@@ -70,9 +94,8 @@ func (l *GormLogger) Trace(ctx context.Context, begin time.Time, fc func() (stri
 		zap.Duration("elapsed", elapsed),
 	}
 
-	if traceID != "" {
-		fields = append(fields, zap.String("trace_id", traceID))
-	}
+	// 关联请求：链路ID、用户ID、路由等
+	fields = append(fields, l.requestFields(ctx)...)
 
 	// 记录慢查询
 	if elapsed > l.SlowThreshold {
@@ -0,0 +1,50 @@
+package database
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/liuchen/gin-craft/pkg/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+type ctxKey struct{}
+
+func TestGormLoggerContextFields(t *testing.T) {
+	core, logs := observer.New(zapcore.DebugLevel)
+	l := &GormLogger{
+		SlowThreshold: time.Second,
+		ContextFields: func(ctx context.Context) []zap.Field {
+			if v, ok := ctx.Value(ctxKey{}).(string); ok {
+				return []zap.Field{zap.String("trace_id", v), zap.String("route", "GET /users")}
+			}
+			return nil
+		},
+		logger: zap.New(core),
+	}
+	sql := func() (string, int64) { return "SELECT 1", 1 }
+
+	l.Trace(context.WithValue(context.Background(), ctxKey{}, "t-1"), time.Now(), sql, nil)
+	l.Trace(context.Background(), time.Now(), sql, nil)
+
+	entries := logs.AllUntimed()
+	require.Len(t, entries, 2)
+	fields := entries[0].ContextMap()
+	assert.Equal(t, "t-1", fields["trace_id"])
+	assert.Equal(t, "GET /users", fields["route"])
+	assert.Equal(t, "SELECT 1", fields["sql"])
+	assert.NotContains(t, entries[1].ContextMap(), "trace_id")
+}
+
+func TestTraceIDField(t *testing.T) {
+	ctx := context.WithValue(context.Background(), logger.TraceIDKey, "t-2")
+	fields := traceIDField(ctx)
+	require.Len(t, fields, 1)
+	assert.Equal(t, "t-2", fields[0].String)
+	assert.Empty(t, traceIDField(context.Background()))
+}
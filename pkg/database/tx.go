@@ -0,0 +1,81 @@
+package database
+
+import (
+	"context"
+	"sync"
+
+	"gorm.io/gorm"
+)
+
+// txKey 事务在 context 中的 key
+type txKey struct{}
+
+// txState 进行中的事务及提交后要执行的回调
+type txState struct {
+	db    *gorm.DB
+	mu    sync.Mutex
+	hooks []func()
+}
+
+func (s *txState) addHook(fn func()) {
+	s.mu.Lock()
+	s.hooks = append(s.hooks, fn)
+	s.mu.Unlock()
+}
+
+// Transaction 在事务中执行 fn，传给 fn 的 ctx 携带该事务，通过 Conn 取连接的查询都在事务内执行。
+// ctx 已在事务中时以保存点嵌套：fn 失败只回滚到保存点，外层事务可以继续。
+// fn 返回错误或 panic 时回滚，否则提交；最外层提交成功后按注册顺序执行 AfterCommit 回调
+func Transaction(ctx context.Context, db *gorm.DB, fn func(ctx context.Context) error) error {
+	parent, _ := ctx.Value(txKey{}).(*txState)
+	if parent != nil {
+		db = parent.db
+	}
+	state := &txState{}
+	err := db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		state.db = tx
+		return fn(context.WithValue(ctx, txKey{}, state))
+	})
+	if err != nil {
+		return err
+	}
+
+	state.mu.Lock()
+	hooks := state.hooks
+	state.mu.Unlock()
+	if parent != nil {
+		// 保存点释放后回调仍取决于外层事务能否提交
+		for _, h := range hooks {
+			parent.addHook(h)
+		}
+		return nil
+	}
+	for _, h := range hooks {
+		h()
+	}
+	return nil
+}
+
+// Conn ctx 在事务中时返回事务连接，否则返回 db；两者都带上 ctx
+func Conn(ctx context.Context, db *gorm.DB) *gorm.DB {
+	if state, ok := ctx.Value(txKey{}).(*txState); ok {
+		return state.db.WithContext(ctx)
+	}
+	return db.WithContext(ctx)
+}
+
+// InTransaction ctx 是否在事务中
+func InTransaction(ctx context.Context) bool {
+	_, ok := ctx.Value(txKey{}).(*txState)
+	return ok
+}
+
+// AfterCommit ctx 在事务中时登记 fn，待最外层事务提交后执行，回滚则丢弃；否则立即执行。
+// 用于更新缓存、索引等不能随事务回滚的副作用
+func AfterCommit(ctx context.Context, fn func()) {
+	if state, ok := ctx.Value(txKey{}).(*txState); ok {
+		state.addHook(fn)
+		return
+	}
+	fn()
+}
@@ -0,0 +1,94 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+type txRow struct {
+	ID   uint
+	Name string
+}
+
+func newTxTestDB(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open(filepath.Join(t.TempDir(), "tx.db")), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&txRow{}))
+	return db
+}
+
+func txNames(t *testing.T, db *gorm.DB) []string {
+	var names []string
+	require.NoError(t, db.Model(&txRow{}).Order("id").Pluck("name", &names).Error)
+	return names
+}
+
+func TestTransaction(t *testing.T) {
+	db := newTxTestDB(t)
+	ctx := context.Background()
+	errFail := errors.New("fail")
+	var committed []string
+
+	err := Transaction(ctx, db, func(ctx context.Context) error {
+		assert.True(t, InTransaction(ctx))
+		require.NoError(t, Conn(ctx, db).Create(&txRow{Name: "a"}).Error)
+		AfterCommit(ctx, func() { committed = append(committed, "a") })
+
+		// 内层失败只回滚到保存点，回调随之丢弃
+		err := Transaction(ctx, db, func(ctx context.Context) error {
+			require.NoError(t, Conn(ctx, db).Create(&txRow{Name: "b"}).Error)
+			AfterCommit(ctx, func() { committed = append(committed, "b") })
+			return errFail
+		})
+		assert.ErrorIs(t, err, errFail)
+
+		// 内层成功的回调要等外层提交
+		require.NoError(t, Transaction(ctx, db, func(ctx context.Context) error {
+			AfterCommit(ctx, func() { committed = append(committed, "c") })
+			return Conn(ctx, db).Create(&txRow{Name: "c"}).Error
+		}))
+		assert.Empty(t, committed)
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a", "c"}, txNames(t, db))
+	assert.Equal(t, []string{"a", "c"}, committed)
+
+	// 外层回滚时内层已释放的保存点一并回滚
+	committed = nil
+	err = Transaction(ctx, db, func(ctx context.Context) error {
+		require.NoError(t, Transaction(ctx, db, func(ctx context.Context) error {
+			AfterCommit(ctx, func() { committed = append(committed, "d") })
+			return Conn(ctx, db).Create(&txRow{Name: "d"}).Error
+		}))
+		return errFail
+	})
+	assert.ErrorIs(t, err, errFail)
+	assert.Equal(t, []string{"a", "c"}, txNames(t, db))
+	assert.Empty(t, committed)
+
+	// 不在事务中：回调立即执行
+	assert.False(t, InTransaction(ctx))
+	AfterCommit(ctx, func() { committed = append(committed, "e") })
+	assert.Equal(t, []string{"e"}, committed)
+}
+
+func TestTransactionPanic(t *testing.T) {
+	db := newTxTestDB(t)
+	ctx := context.Background()
+
+	assert.Panics(t, func() {
+		_ = Transaction(ctx, db, func(ctx context.Context) error {
+			require.NoError(t, Conn(ctx, db).Create(&txRow{Name: "a"}).Error)
+			panic("boom")
+		})
+	})
+	assert.Empty(t, txNames(t, db))
+}
@@ -0,0 +1,109 @@
+package hasher
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/argon2"
+)
+
+const argon2ID = "argon2id"
+
+// Argon2id argon2id 散列，编码串为 PHC 字符串格式：
+//
+//	$argon2id$v=19$m=65536,t=3,p=2$<salt>$<hash>
+//
+// salt 与 hash 为无填充的标准 base64。
+type Argon2id struct {
+	Memory      uint32 // KiB
+	Iterations  uint32
+	Parallelism uint8
+	SaltLength  uint32
+	KeyLength   uint32
+}
+
+// DefaultArgon2id 默认参数：64 MiB、3 次迭代、2 路并行
+func DefaultArgon2id() *Argon2id {
+	return &Argon2id{Memory: 64 * 1024, Iterations: 3, Parallelism: 2, SaltLength: 16, KeyLength: 32}
+}
+
+// argon2Params 从编码串解析出的参数
+type argon2Params struct {
+	version     int
+	memory      uint32
+	iterations  uint32
+	parallelism uint8
+	salt, key   []byte
+}
+
+// Hash 实现 Hasher
+func (a *Argon2id) Hash(password string) (string, error) {
+	salt := make([]byte, a.SaltLength)
+	if _, err := rand.Read(salt); err != nil {
+		return "", fmt.Errorf("hasher: generate salt: %w", err)
+	}
+	key := argon2.IDKey([]byte(password), salt, a.Iterations, a.Memory, a.Parallelism, a.KeyLength)
+	return fmt.Sprintf("$%s$v=%d$m=%d,t=%d,p=%d$%s$%s",
+		argon2ID, argon2.Version, a.Memory, a.Iterations, a.Parallelism,
+		base64.RawStdEncoding.EncodeToString(salt),
+		base64.RawStdEncoding.EncodeToString(key),
+	), nil
+}
+
+// Verify 实现 Hasher，使用编码串中记录的参数重新计算
+func (a *Argon2id) Verify(password, encoded string) (bool, error) {
+	p, err := parseArgon2(encoded)
+	if err != nil {
+		return false, err
+	}
+	key := argon2.IDKey([]byte(password), p.salt, p.iterations, p.memory, p.parallelism, uint32(len(p.key)))
+	return subtle.ConstantTimeCompare(key, p.key) == 1, nil
+}
+
+// Match 实现 Hasher
+func (a *Argon2id) Match(encoded string) bool {
+	return algorithmID(encoded) == argon2ID
+}
+
+// NeedsRehash 实现 Hasher
+func (a *Argon2id) NeedsRehash(encoded string) bool {
+	p, err := parseArgon2(encoded)
+	if err != nil {
+		return true
+	}
+	return p.version != argon2.Version ||
+		p.memory != a.Memory ||
+		p.iterations != a.Iterations ||
+		p.parallelism != a.Parallelism ||
+		uint32(len(p.salt)) != a.SaltLength ||
+		uint32(len(p.key)) != a.KeyLength
+}
+
+func parseArgon2(encoded string) (*argon2Params, error) {
+	// ["", "argon2id", "v=19", "m=..,t=..,p=..", salt, hash]
+	parts := strings.Split(encoded, "$")
+	if len(parts) != 6 || parts[1] != argon2ID {
+		return nil, ErrMalformedHash
+	}
+	var p argon2Params
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &p.version); err != nil {
+		return nil, ErrMalformedHash
+	}
+	if _, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &p.memory, &p.iterations, &p.parallelism); err != nil {
+		return nil, ErrMalformedHash
+	}
+	var err error
+	if p.salt, err = base64.RawStdEncoding.DecodeString(parts[4]); err != nil {
+		return nil, ErrMalformedHash
+	}
+	if p.key, err = base64.RawStdEncoding.DecodeString(parts[5]); err != nil || len(p.key) == 0 {
+		return nil, ErrMalformedHash
+	}
+	if p.iterations == 0 || p.parallelism == 0 {
+		return nil, ErrMalformedHash
+	}
+	return &p, nil
+}
@@ -0,0 +1,57 @@
+package hasher
+
+import (
+	"errors"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// Bcrypt bcrypt 散列，编码串形如 $2a$10$<salt+hash>
+type Bcrypt struct {
+	Cost int
+}
+
+// NewBcrypt 创建 bcrypt 散列器，cost 超出范围时使用 bcrypt.DefaultCost
+func NewBcrypt(cost int) *Bcrypt {
+	if cost < bcrypt.MinCost || cost > bcrypt.MaxCost {
+		cost = bcrypt.DefaultCost
+	}
+	return &Bcrypt{Cost: cost}
+}
+
+// Hash 实现 Hasher
+func (b *Bcrypt) Hash(password string) (string, error) {
+	h, err := bcrypt.GenerateFromPassword([]byte(password), b.Cost)
+	if err != nil {
+		return "", err
+	}
+	return string(h), nil
+}
+
+// Verify 实现 Hasher
+func (b *Bcrypt) Verify(password, encoded string) (bool, error) {
+	err := bcrypt.CompareHashAndPassword([]byte(encoded), []byte(password))
+	switch {
+	case err == nil:
+		return true, nil
+	case errors.Is(err, bcrypt.ErrMismatchedHashAndPassword):
+		return false, nil
+	default:
+		return false, ErrMalformedHash
+	}
+}
+
+// Match 实现 Hasher，兼容 2a / 2b / 2y 三种版本前缀
+func (b *Bcrypt) Match(encoded string) bool {
+	switch algorithmID(encoded) {
+	case "2a", "2b", "2y":
+		return true
+	}
+	return false
+}
+
+// NeedsRehash 实现 Hasher
+func (b *Bcrypt) NeedsRehash(encoded string) bool {
+	cost, err := bcrypt.Cost([]byte(encoded))
+	return err != nil || cost != b.Cost
+}
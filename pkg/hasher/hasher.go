@@ -0,0 +1,68 @@
+package hasher
+
+import (
+	"errors"
+	"strings"
+)
+
+var (
+	// ErrUnknownAlgorithm 编码串不属于任何已注册的算法
+	ErrUnknownAlgorithm = errors.New("hasher: unknown algorithm")
+	// ErrMalformedHash 编码串格式错误
+	ErrMalformedHash = errors.New("hasher: malformed hash")
+)
+
+// Hasher 口令散列算法。Hash 输出自描述的编码串（PHC 字符串格式或 bcrypt 的 $2a$ 格式），
+// 算法与参数都保存在编码串中，调整参数不影响已有散列的校验。
+type Hasher interface {
+	Hash(password string) (string, error)
+	// Verify 校验口令，编码串格式错误时返回 ErrMalformedHash
+	Verify(password, encoded string) (bool, error)
+	// Match 编码串是否由本算法生成
+	Match(encoded string) bool
+	// NeedsRehash 编码串使用的参数与当前配置不一致
+	NeedsRehash(encoded string) bool
+}
+
+// Manager 按编码串分派到对应算法：新口令使用首选算法，历史算法的散列仍可校验
+type Manager struct {
+	preferred Hasher
+	hashers   []Hasher
+}
+
+// NewManager 创建散列管理器，preferred 用于新散列，others 仅用于校验历史散列
+func NewManager(preferred Hasher, others ...Hasher) *Manager {
+	return &Manager{preferred: preferred, hashers: append([]Hasher{preferred}, others...)}
+}
+
+// Hash 使用首选算法散列口令
+func (m *Manager) Hash(password string) (string, error) {
+	return m.preferred.Hash(password)
+}
+
+// Verify 找到生成编码串的算法并校验口令
+func (m *Manager) Verify(password, encoded string) (bool, error) {
+	for _, h := range m.hashers {
+		if h.Match(encoded) {
+			return h.Verify(password, encoded)
+		}
+	}
+	return false, ErrUnknownAlgorithm
+}
+
+// NeedsRehash 编码串不是首选算法生成的，或参数已过时
+func (m *Manager) NeedsRehash(encoded string) bool {
+	return !m.preferred.Match(encoded) || m.preferred.NeedsRehash(encoded)
+}
+
+// algorithmID 取出 "$<id>$..." 中的 id
+func algorithmID(encoded string) string {
+	if !strings.HasPrefix(encoded, "$") {
+		return ""
+	}
+	id, _, ok := strings.Cut(encoded[1:], "$")
+	if !ok {
+		return ""
+	}
+	return id
+}
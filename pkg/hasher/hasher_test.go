@@ -0,0 +1,89 @@
+package hasher
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fastArgon2id 测试用的小参数，避免拖慢测试
+func fastArgon2id() *Argon2id {
+	return &Argon2id{Memory: 64, Iterations: 1, Parallelism: 1, SaltLength: 16, KeyLength: 32}
+}
+
+func TestArgon2id(t *testing.T) {
+	a := fastArgon2id()
+	encoded, err := a.Hash("secret")
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(encoded, "$argon2id$v=19$m=64,t=1,p=1$"))
+
+	ok, err := a.Verify("secret", encoded)
+	require.NoError(t, err)
+	assert.True(t, ok)
+	ok, err = a.Verify("wrong", encoded)
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	// 同一口令两次散列盐不同
+	again, err := a.Hash("secret")
+	require.NoError(t, err)
+	assert.NotEqual(t, encoded, again)
+
+	assert.False(t, a.NeedsRehash(encoded))
+	stronger := fastArgon2id()
+	stronger.Iterations = 2
+	assert.True(t, stronger.NeedsRehash(encoded))
+	// 旧参数的散列在新参数下仍可校验
+	ok, err = stronger.Verify("secret", encoded)
+	require.NoError(t, err)
+	assert.True(t, ok)
+
+	for _, bad := range []string{"$argon2id$v=19$m=64,t=1,p=1$salt", "$argon2id$v=x$m=64,t=1,p=1$c2FsdA$aGFzaA", "$argon2id$v=19$m=64,t=0,p=1$c2FsdA$aGFzaA"} {
+		_, err := a.Verify("secret", bad)
+		assert.ErrorIs(t, err, ErrMalformedHash, bad)
+	}
+}
+
+func TestBcrypt(t *testing.T) {
+	b := NewBcrypt(4)
+	encoded, err := b.Hash("secret")
+	require.NoError(t, err)
+	assert.True(t, b.Match(encoded))
+
+	ok, err := b.Verify("secret", encoded)
+	require.NoError(t, err)
+	assert.True(t, ok)
+	ok, err = b.Verify("wrong", encoded)
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	assert.False(t, b.NeedsRehash(encoded))
+	assert.True(t, NewBcrypt(5).NeedsRehash(encoded))
+
+	_, err = b.Verify("secret", "$2a$broken")
+	assert.ErrorIs(t, err, ErrMalformedHash)
+}
+
+func TestManagerMigratesAlgorithm(t *testing.T) {
+	legacy := NewBcrypt(4)
+	old, err := legacy.Hash("secret")
+	require.NoError(t, err)
+
+	m := NewManager(fastArgon2id(), legacy)
+	ok, err := m.Verify("secret", old)
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.True(t, m.NeedsRehash(old))
+
+	fresh, err := m.Hash("secret")
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(fresh, "$argon2id$"))
+	assert.False(t, m.NeedsRehash(fresh))
+
+	_, err = m.Verify("secret", "plaintext")
+	assert.ErrorIs(t, err, ErrUnknownAlgorithm)
+	_, err = m.Verify("secret", "$scrypt$ln=15$abc$def")
+	assert.ErrorIs(t, err, ErrUnknownAlgorithm)
+}
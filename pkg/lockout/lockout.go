@@ -0,0 +1,162 @@
+package lockout
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	pkgredis "github.com/liuchen/gin-craft/pkg/redis"
+	"github.com/redis/go-redis/v9"
+)
+
+// Policy 失败计数与锁定策略：窗口内连续失败达到 Threshold 次后开始锁定，
+// 锁定时长从 BaseDelay 起每多失败一次翻倍，最长 MaxDelay
+type Policy struct {
+	Threshold int
+	BaseDelay time.Duration
+	MaxDelay  time.Duration
+	Window    time.Duration // 失败计数的保留时长，每次失败后重新计时
+}
+
+// Validate 校验策略参数
+func (p Policy) Validate() error {
+	if p.Threshold <= 0 || p.BaseDelay <= 0 || p.Window <= 0 {
+		return fmt.Errorf("lockout: threshold, base delay and window must be > 0")
+	}
+	if p.MaxDelay < p.BaseDelay {
+		return fmt.Errorf("lockout: max delay must be >= base delay")
+	}
+	return nil
+}
+
+// Delay 第 failures 次失败后的锁定时长，未达到阈值时为 0
+func (p Policy) Delay(failures int64) time.Duration {
+	if failures < int64(p.Threshold) {
+		return 0
+	}
+	delay := p.BaseDelay
+	for i := int64(p.Threshold); i < failures; i++ {
+		delay *= 2
+		if delay >= p.MaxDelay {
+			return p.MaxDelay
+		}
+	}
+	return delay
+}
+
+// Status 某个维度的当前状态
+type Status struct {
+	Key        string
+	Failures   int64
+	RetryAfter time.Duration // 剩余锁定时长，0 表示未锁定
+}
+
+// Locked 是否处于锁定中
+func (s Status) Locked() bool {
+	return s.RetryAfter > 0
+}
+
+// Guard 基于 Redis 的失败计数器，多实例共享。
+// key 由调用方决定维度，如 "user:john"、"ip:1.2.3.4"。
+type Guard struct {
+	client *pkgredis.Client
+	prefix string
+	policy Policy
+}
+
+// NewGuard 创建失败计数器，prefix 用于区分不同维度的计数器
+func NewGuard(client *pkgredis.Client, prefix string, policy Policy) (*Guard, error) {
+	if err := policy.Validate(); err != nil {
+		return nil, err
+	}
+	return &Guard{client: client, prefix: prefix, policy: policy}, nil
+}
+
+// Check 查询当前状态，不修改计数
+func (g *Guard) Check(ctx context.Context, key string) (Status, error) {
+	rdb := g.client.GetClient()
+	pipe := rdb.Pipeline()
+	failures := pipe.Get(ctx, g.failKey(key))
+	ttl := pipe.PTTL(ctx, g.lockKey(key))
+	if _, err := pipe.Exec(ctx); err != nil && !errors.Is(err, redis.Nil) {
+		return Status{}, err
+	}
+
+	st := Status{Key: key}
+	if v, err := failures.Result(); err == nil {
+		st.Failures, _ = strconv.ParseInt(v, 10, 64)
+	}
+	if d := ttl.Val(); d > 0 {
+		st.RetryAfter = d
+	}
+	return st, nil
+}
+
+// Fail 记录一次失败，达到阈值后锁定
+func (g *Guard) Fail(ctx context.Context, key string) (Status, error) {
+	rdb := g.client.GetClient()
+	failures, err := rdb.Incr(ctx, g.failKey(key)).Result()
+	if err != nil {
+		return Status{}, err
+	}
+
+	st := Status{Key: key, Failures: failures, RetryAfter: g.policy.Delay(failures)}
+	// 计数至少保留到锁定结束，解锁后再失败会继续翻倍
+	keep := g.policy.Window
+	if st.RetryAfter > keep {
+		keep = st.RetryAfter
+	}
+	pipe := rdb.Pipeline()
+	pipe.PExpire(ctx, g.failKey(key), keep)
+	if st.Locked() {
+		pipe.Set(ctx, g.lockKey(key), failures, st.RetryAfter)
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		return Status{}, err
+	}
+	return st, nil
+}
+
+// Reset 清除失败计数与锁定
+func (g *Guard) Reset(ctx context.Context, key string) error {
+	return g.client.Del(ctx, g.failKey(key), g.lockKey(key))
+}
+
+// Locked 列出当前处于锁定中的全部 key（SCAN 遍历，供管理后台使用）
+func (g *Guard) Locked(ctx context.Context) ([]Status, error) {
+	rdb := g.client.GetClient()
+	prefix := g.lockKey("")
+	var (
+		list   []Status
+		cursor uint64
+	)
+	for {
+		keys, next, err := rdb.Scan(ctx, cursor, prefix+"*", 100).Result()
+		if err != nil {
+			return nil, err
+		}
+		for _, k := range keys {
+			st, err := g.Check(ctx, strings.TrimPrefix(k, prefix))
+			if err != nil {
+				return nil, err
+			}
+			if st.Locked() {
+				list = append(list, st)
+			}
+		}
+		if cursor = next; cursor == 0 {
+			return list, nil
+		}
+	}
+}
+
+func (g *Guard) failKey(key string) string {
+	return g.prefix + "fail:" + key
+}
+
+func (g *Guard) lockKey(key string) string {
+	return g.prefix + "lock:" + key
+}
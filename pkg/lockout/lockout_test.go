@@ -0,0 +1,94 @@
+package lockout
+
+import (
+	"context"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	pkgredis "github.com/liuchen/gin-craft/pkg/redis"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestGuard(t *testing.T, policy Policy) (*miniredis.Miniredis, *Guard) {
+	t.Helper()
+	mr := miniredis.RunT(t)
+	port, err := strconv.Atoi(mr.Port())
+	require.NoError(t, err)
+	client := pkgredis.NewClient(&pkgredis.Config{Host: mr.Host(), Port: port})
+	require.NoError(t, client.Connect())
+	t.Cleanup(func() { _ = client.Close() })
+
+	g, err := NewGuard(client, "test:lockout:", policy)
+	require.NoError(t, err)
+	return mr, g
+}
+
+func TestPolicyDelay(t *testing.T) {
+	p := Policy{Threshold: 3, BaseDelay: time.Second, MaxDelay: 10 * time.Second, Window: time.Minute}
+	expected := []time.Duration{0, 0, 0, time.Second, 2 * time.Second, 4 * time.Second, 8 * time.Second, 10 * time.Second, 10 * time.Second}
+	for failures, d := range expected {
+		assert.Equal(t, d, p.Delay(int64(failures)), "failures=%d", failures)
+	}
+
+	assert.Error(t, Policy{}.Validate())
+	assert.Error(t, Policy{Threshold: 1, BaseDelay: time.Minute, MaxDelay: time.Second, Window: time.Minute}.Validate())
+}
+
+func TestGuardLockAndReset(t *testing.T) {
+	mr, g := newTestGuard(t, Policy{Threshold: 2, BaseDelay: 30 * time.Second, MaxDelay: time.Hour, Window: 15 * time.Minute})
+	ctx := context.Background()
+
+	st, err := g.Fail(ctx, "user:john")
+	require.NoError(t, err)
+	assert.False(t, st.Locked())
+
+	st, err = g.Fail(ctx, "user:john")
+	require.NoError(t, err)
+	assert.True(t, st.Locked())
+	assert.Equal(t, 30*time.Second, st.RetryAfter)
+
+	st, err = g.Check(ctx, "user:john")
+	require.NoError(t, err)
+	assert.EqualValues(t, 2, st.Failures)
+	assert.True(t, st.Locked())
+
+	// 锁定到期后再次失败，锁定时长翻倍
+	mr.FastForward(31 * time.Second)
+	st, err = g.Check(ctx, "user:john")
+	require.NoError(t, err)
+	assert.False(t, st.Locked())
+	st, err = g.Fail(ctx, "user:john")
+	require.NoError(t, err)
+	assert.Equal(t, time.Minute, st.RetryAfter)
+
+	_, err = g.Fail(ctx, "ip:1.2.3.4")
+	require.NoError(t, err)
+	locked, err := g.Locked(ctx)
+	require.NoError(t, err)
+	require.Len(t, locked, 1)
+	assert.Equal(t, "user:john", locked[0].Key)
+
+	require.NoError(t, g.Reset(ctx, "user:john"))
+	st, err = g.Check(ctx, "user:john")
+	require.NoError(t, err)
+	assert.Equal(t, Status{Key: "user:john"}, st)
+}
+
+func TestGuardWindowExpiry(t *testing.T) {
+	mr, g := newTestGuard(t, Policy{Threshold: 3, BaseDelay: time.Second, MaxDelay: time.Minute, Window: time.Minute})
+	ctx := context.Background()
+
+	for i := 0; i < 2; i++ {
+		_, err := g.Fail(ctx, "user:john")
+		require.NoError(t, err)
+	}
+	mr.FastForward(2 * time.Minute)
+
+	st, err := g.Fail(ctx, "user:john")
+	require.NoError(t, err)
+	assert.EqualValues(t, 1, st.Failures)
+	assert.False(t, st.Locked())
+}
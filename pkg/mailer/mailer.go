@@ -0,0 +1,115 @@
+package mailer
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"mime"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/liuchen/gin-craft/pkg/logger"
+	"go.uber.org/zap"
+)
+
+// ErrNoRecipient 邮件没有收件人
+var ErrNoRecipient = errors.New("mailer: no recipient")
+
+// Message 待发送的纯文本邮件
+type Message struct {
+	From    string
+	To      []string
+	Subject string
+	Body    string
+}
+
+// Mailer 邮件发送器。业务只依赖该接口，SMTP、第三方邮件服务等实现可自行接入
+type Mailer interface {
+	Send(ctx context.Context, msg *Message) error
+}
+
+// LogMailer 只把邮件写入日志，用于本地开发
+type LogMailer struct {
+	From string
+}
+
+// NewLogMailer 创建日志邮件发送器
+func NewLogMailer(from string) *LogMailer {
+	return &LogMailer{From: from}
+}
+
+// Send 实现 Mailer
+func (m *LogMailer) Send(_ context.Context, msg *Message) error {
+	if len(msg.To) == 0 {
+		return ErrNoRecipient
+	}
+	logger.Info("Mail sent (log driver)",
+		zap.String("from", from(msg, m.From)),
+		zap.Strings("to", msg.To),
+		zap.String("subject", msg.Subject),
+		zap.String("body", msg.Body),
+	)
+	return nil
+}
+
+// FileMailer 把每封邮件保存为目录下的 .eml 文件，便于本地测试时查看邮件内容与链接
+type FileMailer struct {
+	From string
+	Dir  string
+
+	now func() time.Time
+}
+
+// NewFileMailer 创建文件邮件发送器，目录不存在时自动创建
+func NewFileMailer(from, dir string) (*FileMailer, error) {
+	if dir == "" {
+		return nil, errors.New("mailer: file driver requires a directory")
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("mailer: mkdir %s: %w", dir, err)
+	}
+	return &FileMailer{From: from, Dir: dir, now: time.Now}, nil
+}
+
+// Send 实现 Mailer
+func (m *FileMailer) Send(_ context.Context, msg *Message) error {
+	if len(msg.To) == 0 {
+		return ErrNoRecipient
+	}
+	suffix := make([]byte, 4)
+	if _, err := rand.Read(suffix); err != nil {
+		return err
+	}
+	now := m.now()
+	name := fmt.Sprintf("%s-%s.eml", now.Format("20060102T150405.000"), hex.EncodeToString(suffix))
+	path := filepath.Join(m.Dir, name)
+	if err := os.WriteFile(path, Render(msg, from(msg, m.From), now), 0o600); err != nil {
+		return fmt.Errorf("mailer: write %s: %w", path, err)
+	}
+	return nil
+}
+
+// Render 按 RFC 5322 格式渲染邮件（UTF-8 纯文本），主题按 RFC 2047 编码
+func Render(msg *Message, from string, date time.Time) []byte {
+	var b strings.Builder
+	b.WriteString("From: " + from + "\r\n")
+	b.WriteString("To: " + strings.Join(msg.To, ", ") + "\r\n")
+	b.WriteString("Subject: " + mime.QEncoding.Encode("utf-8", msg.Subject) + "\r\n")
+	b.WriteString("Date: " + date.Format(time.RFC1123Z) + "\r\n")
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=UTF-8\r\n")
+	b.WriteString("\r\n")
+	b.WriteString(strings.ReplaceAll(msg.Body, "\n", "\r\n"))
+	return []byte(b.String())
+}
+
+func from(msg *Message, fallback string) string {
+	if msg.From != "" {
+		return msg.From
+	}
+	return fallback
+}
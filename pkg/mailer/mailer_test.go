@@ -0,0 +1,50 @@
+package mailer
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileMailer(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "mail")
+	m, err := NewFileMailer("no-reply@example.com", dir)
+	require.NoError(t, err)
+	m.now = func() time.Time { return time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC) }
+
+	err = m.Send(context.Background(), &Message{
+		To:      []string{"john@example.com"},
+		Subject: "重置密码",
+		Body:    "line1\nline2",
+	})
+	require.NoError(t, err)
+
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.True(t, strings.HasPrefix(entries[0].Name(), "20240102T030405.000-"))
+
+	data, err := os.ReadFile(filepath.Join(dir, entries[0].Name()))
+	require.NoError(t, err)
+	content := string(data)
+	assert.Contains(t, content, "From: no-reply@example.com\r\n")
+	assert.Contains(t, content, "To: john@example.com\r\n")
+	assert.Contains(t, content, "Subject: =?utf-8?q?")
+	assert.True(t, strings.HasSuffix(content, "\r\n\r\nline1\r\nline2"))
+}
+
+func TestNoRecipient(t *testing.T) {
+	m, err := NewFileMailer("", t.TempDir())
+	require.NoError(t, err)
+	assert.ErrorIs(t, m.Send(context.Background(), &Message{Subject: "x"}), ErrNoRecipient)
+	assert.ErrorIs(t, NewLogMailer("").Send(context.Background(), &Message{}), ErrNoRecipient)
+
+	_, err = NewFileMailer("", "")
+	assert.Error(t, err)
+}
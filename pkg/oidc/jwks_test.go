@@ -0,0 +1,39 @@
+package oidc
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/liuchen/gin-craft/pkg/oidc/oidctest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKeyRotation(t *testing.T) {
+	idp := oidctest.NewServer("gincraft")
+	defer idp.Close()
+	p, err := NewProvider(context.Background(), Config{Issuer: idp.URL, ClientID: "gincraft"}, idp.Client())
+	require.NoError(t, err)
+
+	now := time.Now()
+	p.keys.now = func() time.Time { return now }
+	sign := func() string {
+		return idp.SignIDToken(jwt.MapClaims{"iss": idp.URL, "sub": "u-1", "aud": "gincraft", "exp": time.Now().Add(time.Minute).Unix()})
+	}
+	ctx := context.Background()
+
+	_, err = p.VerifyIDToken(ctx, sign(), "")
+	require.NoError(t, err)
+
+	// 刚刷新过，未知 kid 不立即回源
+	idp.RotateKey()
+	_, err = p.VerifyIDToken(ctx, sign(), "")
+	assert.ErrorIs(t, err, ErrInvalidIDToken)
+
+	// 超过最小刷新间隔后遇到未知 kid 重新拉取 JWKS
+	now = now.Add(minRefreshInterval)
+	_, err = p.VerifyIDToken(ctx, sign(), "")
+	assert.NoError(t, err)
+}
@@ -0,0 +1,121 @@
+package oidc_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/liuchen/gin-craft/pkg/oidc"
+	"github.com/liuchen/gin-craft/pkg/oidc/oidctest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newProvider(t *testing.T) (*oidc.Provider, *oidctest.Server) {
+	t.Helper()
+	idp := oidctest.NewServer("gincraft")
+	t.Cleanup(idp.Close)
+
+	p, err := oidc.NewProvider(context.Background(), oidc.Config{
+		Issuer:      idp.URL,
+		ClientID:    "gincraft",
+		RedirectURL: "http://app.local/callback",
+	}, idp.Client())
+	require.NoError(t, err)
+	return p, idp
+}
+
+// login 走完一次授权码 + PKCE 流程，返回校验后的声明
+func login(t *testing.T, p *oidc.Provider, idp *oidctest.Server) (*oidc.IDClaims, error) {
+	t.Helper()
+	ctx := context.Background()
+	state, err := oidc.RandomString(16)
+	require.NoError(t, err)
+	nonce, err := oidc.RandomString(16)
+	require.NoError(t, err)
+	verifier, challenge, err := oidc.NewPKCE()
+	require.NoError(t, err)
+
+	code, gotState, err := idp.Authorize(p.AuthCodeURL(state, nonce, challenge))
+	require.NoError(t, err)
+	require.Equal(t, state, gotState)
+
+	tok, err := p.Exchange(ctx, code, verifier)
+	require.NoError(t, err)
+	return p.VerifyIDToken(ctx, tok.IDToken, nonce)
+}
+
+func TestAuthorizationCodeFlow(t *testing.T) {
+	p, idp := newProvider(t)
+	idp.SetUser(oidctest.User{Subject: "u-1", Email: "alice@corp.example", EmailVerified: true, PreferredUsername: "alice"})
+
+	claims, err := login(t, p, idp)
+	require.NoError(t, err)
+	assert.Equal(t, "u-1", claims.Subject)
+	assert.Equal(t, "alice@corp.example", claims.Email)
+	assert.True(t, claims.EmailVerified)
+	assert.Equal(t, "alice", claims.PreferredUsername)
+}
+
+func TestExchangeRejectsWrongVerifier(t *testing.T) {
+	p, idp := newProvider(t)
+	idp.SetUser(oidctest.User{Subject: "u-1"})
+
+	_, challenge, err := oidc.NewPKCE()
+	require.NoError(t, err)
+	code, _, err := idp.Authorize(p.AuthCodeURL("s", "n", challenge))
+	require.NoError(t, err)
+
+	_, err = p.Exchange(context.Background(), code, "not-the-verifier")
+	assert.ErrorIs(t, err, oidc.ErrExchange)
+}
+
+func TestVerifyIDTokenRejects(t *testing.T) {
+	p, idp := newProvider(t)
+	ctx := context.Background()
+	now := time.Now()
+	base := func() jwt.MapClaims {
+		return jwt.MapClaims{"iss": idp.URL, "sub": "u-1", "aud": "gincraft", "exp": now.Add(time.Minute).Unix(), "nonce": "n"}
+	}
+
+	tests := map[string]func(jwt.MapClaims){
+		"wrong audience": func(c jwt.MapClaims) { c["aud"] = "other" },
+		"wrong issuer":   func(c jwt.MapClaims) { c["iss"] = "https://evil.example" },
+		"expired":        func(c jwt.MapClaims) { c["exp"] = now.Add(-time.Hour).Unix() },
+		"wrong nonce":    func(c jwt.MapClaims) { c["nonce"] = "other" },
+		"missing sub":    func(c jwt.MapClaims) { delete(c, "sub") },
+		"multiple audiences without azp": func(c jwt.MapClaims) {
+			c["aud"] = []string{"gincraft", "other"}
+		},
+		"azp of another client": func(c jwt.MapClaims) {
+			c["aud"] = []string{"gincraft", "other"}
+			c["azp"] = "other"
+		},
+	}
+	for name, mutate := range tests {
+		t.Run(name, func(t *testing.T) {
+			c := base()
+			mutate(c)
+			_, err := p.VerifyIDToken(ctx, idp.SignIDToken(c), "n")
+			assert.ErrorIs(t, err, oidc.ErrInvalidIDToken)
+		})
+	}
+
+	_, err := p.VerifyIDToken(ctx, idp.SignIDToken(base()), "n")
+	assert.NoError(t, err)
+
+	c := base()
+	c["aud"] = []string{"gincraft", "other"}
+	c["azp"] = "gincraft"
+	_, err = p.VerifyIDToken(ctx, idp.SignIDToken(c), "n")
+	assert.NoError(t, err)
+}
+
+func TestDiscoveryIssuerMismatch(t *testing.T) {
+	idp := oidctest.NewServer("gincraft")
+	defer idp.Close()
+
+	_, err := oidc.NewProvider(context.Background(), oidc.Config{Issuer: idp.URL + "/other", ClientID: "gincraft"}, idp.Client())
+	assert.Error(t, err)
+}
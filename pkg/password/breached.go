@@ -0,0 +1,92 @@
+package password
+
+import (
+	"bufio"
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// BreachedList 已泄露密码列表，全部载入内存，适用于 Top-N 常见/泄露密码文件。
+// 文件每行一条，支持两种格式：
+//   - 明文密码
+//   - SHA-1 十六进制摘要，可带 ":次数" 后缀（Have I Been Pwned 下载格式）
+//
+// 空行与 # 开头的行被忽略。内存中统一保存 SHA-1 摘要。
+type BreachedList struct {
+	hashes map[string]struct{}
+}
+
+// LoadBreachedFile 从本地文件加载泄露密码列表
+func LoadBreachedFile(path string) (*BreachedList, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("password: open breached list: %w", err)
+	}
+	defer f.Close()
+	return ReadBreachedList(f)
+}
+
+// ReadBreachedList 从 r 读取泄露密码列表
+func ReadBreachedList(r io.Reader) (*BreachedList, error) {
+	l := &BreachedList{hashes: make(map[string]struct{})}
+	sc := bufio.NewScanner(r)
+	for sc.Scan() {
+		line := strings.TrimRight(sc.Text(), "\r")
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if h, ok := parseSHA1Line(line); ok {
+			l.hashes[h] = struct{}{}
+			continue
+		}
+		l.hashes[sha1Hex(line)] = struct{}{}
+	}
+	if err := sc.Err(); err != nil {
+		return nil, fmt.Errorf("password: read breached list: %w", err)
+	}
+	return l, nil
+}
+
+// Len 列表条目数
+func (l *BreachedList) Len() int {
+	if l == nil {
+		return 0
+	}
+	return len(l.hashes)
+}
+
+// Contains 判断密码是否在列表中；常见列表多为小写，因此同时检查小写形式
+func (l *BreachedList) Contains(pw string) bool {
+	if l == nil {
+		return false
+	}
+	if _, ok := l.hashes[sha1Hex(pw)]; ok {
+		return true
+	}
+	if lower := strings.ToLower(pw); lower != pw {
+		_, ok := l.hashes[sha1Hex(lower)]
+		return ok
+	}
+	return false
+}
+
+// parseSHA1Line 识别 "<40 位十六进制>[:次数]" 格式的行
+func parseSHA1Line(line string) (string, bool) {
+	h, _, _ := strings.Cut(line, ":")
+	if len(h) != 2*sha1.Size {
+		return "", false
+	}
+	if _, err := hex.DecodeString(h); err != nil {
+		return "", false
+	}
+	return strings.ToUpper(h), true
+}
+
+func sha1Hex(s string) string {
+	sum := sha1.Sum([]byte(s))
+	return strings.ToUpper(hex.EncodeToString(sum[:]))
+}
@@ -0,0 +1,88 @@
+package password
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// 策略校验失败的原因，可用 errors.Is 判断
+var (
+	ErrTooShort      = errors.New("password: too short")
+	ErrTooLong       = errors.New("password: too long")
+	ErrMissingUpper  = errors.New("password: missing upper-case letter")
+	ErrMissingLower  = errors.New("password: missing lower-case letter")
+	ErrMissingDigit  = errors.New("password: missing digit")
+	ErrMissingSymbol = errors.New("password: missing symbol")
+	ErrContainsInput = errors.New("password: contains user information")
+	ErrBreached      = errors.New("password: found in breached password list")
+)
+
+// bcryptMaxBytes bcrypt 只使用前 72 字节，超出部分被忽略
+const bcryptMaxBytes = 72
+
+// Policy 密码策略
+type Policy struct {
+	MinLength     int // 最少字符数
+	MaxLength     int // 最多字节数，0 或超过 72 时按 72（bcrypt 上限）
+	RequireUpper  bool
+	RequireLower  bool
+	RequireDigit  bool
+	RequireSymbol bool
+	Breached      *BreachedList // 为空时跳过泄露库检查
+}
+
+// Validate 校验密码，返回第一个不满足的条件。
+// inputs 为用户名、邮箱等个人信息，密码包含其中任一项（忽略大小写，长度 >= 3）时拒绝。
+func (p *Policy) Validate(pw string, inputs ...string) error {
+	if n := utf8.RuneCountInString(pw); n < p.MinLength {
+		return fmt.Errorf("%w: at least %d characters", ErrTooShort, p.MinLength)
+	}
+	if max := p.maxLength(); len(pw) > max {
+		return fmt.Errorf("%w: at most %d bytes", ErrTooLong, max)
+	}
+
+	var upper, lower, digit, symbol bool
+	for _, r := range pw {
+		switch {
+		case unicode.IsUpper(r):
+			upper = true
+		case unicode.IsLower(r):
+			lower = true
+		case unicode.IsDigit(r):
+			digit = true
+		case unicode.IsPunct(r) || unicode.IsSymbol(r) || unicode.IsSpace(r):
+			symbol = true
+		}
+	}
+	switch {
+	case p.RequireUpper && !upper:
+		return ErrMissingUpper
+	case p.RequireLower && !lower:
+		return ErrMissingLower
+	case p.RequireDigit && !digit:
+		return ErrMissingDigit
+	case p.RequireSymbol && !symbol:
+		return ErrMissingSymbol
+	}
+
+	lowered := strings.ToLower(pw)
+	for _, in := range inputs {
+		if in = strings.ToLower(strings.TrimSpace(in)); len(in) >= 3 && strings.Contains(lowered, in) {
+			return ErrContainsInput
+		}
+	}
+	if p.Breached.Contains(pw) {
+		return ErrBreached
+	}
+	return nil
+}
+
+func (p *Policy) maxLength() int {
+	if p.MaxLength <= 0 || p.MaxLength > bcryptMaxBytes {
+		return bcryptMaxBytes
+	}
+	return p.MaxLength
+}
@@ -0,0 +1,66 @@
+package password
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPolicyValidate(t *testing.T) {
+	breached, err := ReadBreachedList(strings.NewReader(strings.Join([]string{
+		"# top passwords",
+		"password1",
+		"",
+		"Summer2024!",
+	}, "\n")))
+	require.NoError(t, err)
+
+	p := &Policy{
+		MinLength:    8,
+		RequireUpper: true,
+		RequireLower: true,
+		RequireDigit: true,
+		Breached:     breached,
+	}
+
+	tests := map[string]error{
+		"Abcdefg1":                nil,
+		"Abc1":                    ErrTooShort,
+		strings.Repeat("Aa1", 30): ErrTooLong,
+		"abcdefg1":                ErrMissingUpper,
+		"ABCDEFG1":                ErrMissingLower,
+		"Abcdefgh":                ErrMissingDigit,
+		"Password1":               ErrBreached, // 小写形式命中明文条目
+		"Summer2024!":             ErrBreached,
+		"xJohn_Doe99":             ErrContainsInput,
+	}
+	for pw, expected := range tests {
+		err := p.Validate(pw, "john_doe", "john@example.com")
+		if expected == nil {
+			assert.NoError(t, err, pw)
+			continue
+		}
+		assert.ErrorIs(t, err, expected, pw)
+	}
+
+	p.RequireSymbol = true
+	assert.ErrorIs(t, p.Validate("Abcdefg1"), ErrMissingSymbol)
+	assert.NoError(t, p.Validate("Abcdef g1"))
+	assert.NoError(t, p.Validate("Abcdef-g1"))
+}
+
+func TestBreachedList(t *testing.T) {
+	l, err := ReadBreachedList(strings.NewReader("123456\r\n5BAA61E4C9B93F3F0682250B6CF8331B7EE68FD8:3730471\nnot-a-hash:1\n"))
+	require.NoError(t, err)
+	assert.Equal(t, 3, l.Len())
+	assert.True(t, l.Contains("123456"))
+	assert.True(t, l.Contains("password")) // sha1("password")
+	assert.True(t, l.Contains("not-a-hash:1"))
+	assert.False(t, l.Contains("correct horse battery staple"))
+
+	var empty *BreachedList
+	assert.False(t, empty.Contains("123456"))
+	assert.Equal(t, 0, empty.Len())
+}
@@ -0,0 +1,287 @@
+// Package query 列表接口通用查询语言：过滤、排序与字段选择。
+//
+//	filter=email:like:foo,created_at:gte:2024-01-01&sort=-created_at,id&fields=id,username
+//
+// 每个模型通过 Schema 声明允许过滤、排序与返回的字段，列名只取自 Schema，
+// 值一律作为 SQL 参数传递，客户端无法注入列名或表达式。
+package query
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ErrInvalid 查询参数格式错误，或字段、操作符不在白名单内
+var ErrInvalid = errors.New("query: invalid query")
+
+const (
+	maxFilters  = 10  // 单次查询最多的过滤条件数
+	maxInValues = 100 // in 操作符最多的取值个数
+)
+
+// Type 字段值类型，决定过滤值的解析方式与可用的操作符
+type Type int
+
+const (
+	String Type = iota
+	Int
+	Time
+	Bool
+)
+
+// Op 过滤操作符
+type Op string
+
+const (
+	Eq   Op = "eq"
+	Ne   Op = "ne"
+	Gt   Op = "gt"
+	Gte  Op = "gte"
+	Lt   Op = "lt"
+	Lte  Op = "lte"
+	Like Op = "like" // 包含匹配，仅用于 String
+	In   Op = "in"   // 多个取值以 | 分隔
+)
+
+var opSQL = map[Op]string{Eq: "=", Ne: "<>", Gt: ">", Gte: ">=", Lt: "<", Lte: "<="}
+
+// Field 字段定义
+type Field struct {
+	Column     string // 数据库列名，为空时与字段名相同
+	Type       Type
+	Filterable bool
+	Sortable   bool
+}
+
+// Schema 模型可查询的字段，key 为对外字段名（与响应 JSON 字段名一致）；
+// 未列出的字段不可过滤、排序，也不可出现在 fields 中
+type Schema map[string]Field
+
+// Filter 一个过滤条件
+type Filter struct {
+	Column string
+	Op     Op
+	Value  interface{} // In 时为 []interface{}
+}
+
+// Sort 一个排序字段
+type Sort struct {
+	Column string
+	Desc   bool
+}
+
+// Query 解析并校验后的查询
+type Query struct {
+	Filters []Filter
+	Sorts   []Sort
+	Fields  []string // 对外字段名，为空表示返回全部字段
+	columns []string // Fields 对应的列名
+}
+
+// Parse 解析 filter、sort、fields 参数，任一参数为空表示不限制。
+// 字段不在 schema 中、操作符与字段类型不匹配或值无法解析时返回包装了 ErrInvalid 的错误
+func (s Schema) Parse(filter, sort, fields string) (*Query, error) {
+	q := &Query{}
+	for _, raw := range splitList(filter) {
+		f, err := s.parseFilter(raw)
+		if err != nil {
+			return nil, err
+		}
+		q.Filters = append(q.Filters, f)
+	}
+	if len(q.Filters) > maxFilters {
+		return nil, fmt.Errorf("%w: at most %d filters", ErrInvalid, maxFilters)
+	}
+
+	for _, name := range splitList(sort) {
+		desc := strings.HasPrefix(name, "-")
+		name = strings.TrimPrefix(name, "-")
+		field, ok := s[name]
+		if !ok || !field.Sortable {
+			return nil, fmt.Errorf("%w: unsortable field %q", ErrInvalid, name)
+		}
+		q.Sorts = append(q.Sorts, Sort{Column: field.column(name), Desc: desc})
+	}
+
+	for _, name := range splitList(fields) {
+		field, ok := s[name]
+		if !ok {
+			return nil, fmt.Errorf("%w: unknown field %q", ErrInvalid, name)
+		}
+		q.Fields = append(q.Fields, name)
+		q.columns = append(q.columns, field.column(name))
+	}
+	return q, nil
+}
+
+func (s Schema) parseFilter(raw string) (Filter, error) {
+	// 值中可能含有冒号（如 RFC3339 时间），只切前两段
+	parts := strings.SplitN(raw, ":", 3)
+	if len(parts) != 3 {
+		return Filter{}, fmt.Errorf("%w: filter %q must be field:op:value", ErrInvalid, raw)
+	}
+	name, op, value := parts[0], Op(parts[1]), parts[2]
+	field, ok := s[name]
+	if !ok || !field.Filterable {
+		return Filter{}, fmt.Errorf("%w: unfilterable field %q", ErrInvalid, name)
+	}
+
+	f := Filter{Column: field.column(name), Op: op}
+	switch {
+	case op == In:
+		values := strings.Split(value, "|")
+		if len(values) > maxInValues {
+			return Filter{}, fmt.Errorf("%w: at most %d values for in", ErrInvalid, maxInValues)
+		}
+		list := make([]interface{}, 0, len(values))
+		for _, v := range values {
+			pv, err := field.Type.parse(v)
+			if err != nil {
+				return Filter{}, fmt.Errorf("%w: field %q: %v", ErrInvalid, name, err)
+			}
+			list = append(list, pv)
+		}
+		f.Value = list
+		return f, nil
+	case op == Like:
+		if field.Type != String {
+			return Filter{}, fmt.Errorf("%w: like is only allowed on string field %q", ErrInvalid, name)
+		}
+		f.Value = LikeContains(value)
+		return f, nil
+	case opSQL[op] == "":
+		return Filter{}, fmt.Errorf("%w: unknown operator %q", ErrInvalid, op)
+	case op != Eq && op != Ne && field.Type != Int && field.Type != Time:
+		return Filter{}, fmt.Errorf("%w: %s is not allowed on field %q", ErrInvalid, op, name)
+	}
+
+	pv, err := field.Type.parse(value)
+	if err != nil {
+		return Filter{}, fmt.Errorf("%w: field %q: %v", ErrInvalid, name, err)
+	}
+	f.Value = pv
+	return f, nil
+}
+
+// Where 过滤条件 scope，不含排序与字段选择，可用于 COUNT
+func (q *Query) Where() func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		for _, f := range q.Filters {
+			switch f.Op {
+			case Like:
+				db = db.Where(f.Column+" LIKE ? ESCAPE '!'", f.Value)
+			case In:
+				db = db.Where(f.Column+" IN ?", f.Value)
+			default:
+				db = db.Where(f.Column+" "+opSQL[f.Op]+" ?", f.Value)
+			}
+		}
+		return db
+	}
+}
+
+// Select 字段选择 scope，未指定 fields 时不限制
+func (q *Query) Select() func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if len(q.columns) == 0 {
+			return db
+		}
+		return db.Select(q.columns)
+	}
+}
+
+// Order 排序 scope，未指定 sort 时使用 fallback（可为 nil）
+func (q *Query) Order(fallback func(db *gorm.DB) *gorm.DB) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if len(q.Sorts) == 0 {
+			if fallback != nil {
+				return fallback(db)
+			}
+			return db
+		}
+		for _, s := range q.Sorts {
+			dir := " ASC"
+			if s.Desc {
+				dir = " DESC"
+			}
+			db = db.Order(s.Column + dir)
+		}
+		return db
+	}
+}
+
+// Project 将 list（结构体切片）中每个元素按 JSON 字段名裁剪为只含 Fields 的对象；
+// 未指定 fields 时原样返回 list
+func (q *Query) Project(list interface{}) (interface{}, error) {
+	if len(q.Fields) == 0 {
+		return list, nil
+	}
+	b, err := json.Marshal(list)
+	if err != nil {
+		return nil, err
+	}
+	var rows []map[string]json.RawMessage
+	if err := json.Unmarshal(b, &rows); err != nil {
+		return nil, err
+	}
+	out := make([]map[string]json.RawMessage, 0, len(rows))
+	for _, row := range rows {
+		picked := make(map[string]json.RawMessage, len(q.Fields))
+		for _, name := range q.Fields {
+			if v, ok := row[name]; ok {
+				picked[name] = v
+			}
+		}
+		out = append(out, picked)
+	}
+	return out, nil
+}
+
+func (f Field) column(name string) string {
+	if f.Column != "" {
+		return f.Column
+	}
+	return name
+}
+
+func (t Type) parse(v string) (interface{}, error) {
+	switch t {
+	case Int:
+		return strconv.ParseInt(v, 10, 64)
+	case Bool:
+		return strconv.ParseBool(v)
+	case Time:
+		if at, err := time.Parse(time.RFC3339, v); err == nil {
+			return at, nil
+		}
+		return time.ParseInLocation(time.DateOnly, v, time.Local)
+	default:
+		return v, nil
+	}
+}
+
+// LikeContains 返回匹配包含 s 的 LIKE 模式，须配合 "LIKE ? ESCAPE '!'" 使用
+func LikeContains(s string) string {
+	return "%" + escapeLike(s) + "%"
+}
+
+// escapeLike 转义 LIKE 通配符，配合 ESCAPE '!' 使用（MySQL 与 SQLite 通用）
+func escapeLike(s string) string {
+	return strings.NewReplacer("!", "!!", "%", "!%", "_", "!_").Replace(s)
+}
+
+func splitList(s string) []string {
+	var out []string
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			out = append(out, item)
+		}
+	}
+	return out
+}
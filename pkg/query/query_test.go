@@ -0,0 +1,98 @@
+package query
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+var testSchema = Schema{
+	"id":         {Type: Int, Filterable: true, Sortable: true},
+	"name":       {Type: String, Filterable: true, Sortable: true},
+	"created_at": {Type: Time, Filterable: true, Sortable: true},
+	"active":     {Column: "is_active", Type: Bool, Filterable: true},
+	"note":       {Type: String},
+}
+
+type row struct {
+	ID        uint      `json:"id"`
+	Name      string    `json:"name"`
+	CreatedAt time.Time `json:"created_at"`
+	IsActive  bool      `json:"active"`
+	Note      string    `json:"note"`
+}
+
+func TestParse(t *testing.T) {
+	q, err := testSchema.Parse("name:like:a_b,created_at:gte:2024-01-01T08:00:00Z,id:in:1|2", "-created_at,id", "id,name")
+	require.NoError(t, err)
+	require.Len(t, q.Filters, 3)
+	assert.Equal(t, "%a!_b%", q.Filters[0].Value)
+	assert.Equal(t, time.Date(2024, 1, 1, 8, 0, 0, 0, time.UTC), q.Filters[1].Value)
+	assert.Equal(t, []interface{}{int64(1), int64(2)}, q.Filters[2].Value)
+	assert.Equal(t, []Sort{{Column: "created_at", Desc: true}, {Column: "id"}}, q.Sorts)
+	assert.Equal(t, []string{"id", "name"}, q.Fields)
+
+	for _, tc := range []struct{ filter, sort, fields string }{
+		{filter: "password:eq:x"},
+		{filter: "note:eq:x"},
+		{filter: "name"},
+		{filter: "name:regexp:x"},
+		{filter: "id:like:1"},
+		{filter: "name:gt:x"},
+		{filter: "id:eq:abc"},
+		{filter: "created_at:gte:yesterday"},
+		{sort: "note"},
+		{sort: "id;DROP TABLE users"},
+		{fields: "password"},
+	} {
+		_, err := testSchema.Parse(tc.filter, tc.sort, tc.fields)
+		assert.ErrorIs(t, err, ErrInvalid, "%+v", tc)
+	}
+}
+
+func TestQueryScopes(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&row{}))
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i, name := range []string{"alice", "a_b", "bob", "axb"} {
+		require.NoError(t, db.Create(&row{ID: uint(i + 1), Name: name, CreatedAt: base.AddDate(0, 0, i), IsActive: i%2 == 0}).Error)
+	}
+
+	ids := func(filter, sort string) []uint {
+		q, err := testSchema.Parse(filter, sort, "")
+		require.NoError(t, err)
+		var rows []row
+		require.NoError(t, db.Scopes(q.Where(), q.Order(nil)).Find(&rows).Error)
+		out := make([]uint, 0, len(rows))
+		for _, r := range rows {
+			out = append(out, r.ID)
+		}
+		return out
+	}
+
+	// _ 按字面量匹配，不作为通配符
+	assert.Equal(t, []uint{2}, ids("name:like:a_", ""))
+	assert.Equal(t, []uint{4, 3}, ids("created_at:gte:2024-01-03T00:00:00Z", "-id"))
+	assert.Equal(t, []uint{1, 3}, ids("active:eq:true", "id"))
+	assert.Equal(t, []uint{3, 1}, ids("id:in:1|3", "-name"))
+	assert.Equal(t, []uint{2, 4}, ids("id:ne:1,id:ne:3", "id"))
+
+	q, err := testSchema.Parse("id:eq:1", "", "id,name")
+	require.NoError(t, err)
+	var rows []row
+	require.NoError(t, db.Scopes(q.Where(), q.Select()).Find(&rows).Error)
+	require.Len(t, rows, 1)
+	assert.Empty(t, rows[0].Note)
+
+	out, err := q.Project(rows)
+	require.NoError(t, err)
+	b, err := json.Marshal(out)
+	require.NoError(t, err)
+	assert.JSONEq(t, `[{"id":1,"name":"alice"}]`, string(b))
+}
@@ -0,0 +1,158 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+)
+
+// sweepEvery 每处理多少次请求清理一次过期状态
+const sweepEvery = 1024
+
+// MemoryLimiter 进程内限流器，适用于单实例部署
+type MemoryLimiter struct {
+	algorithm Algorithm
+	now       func() time.Time
+
+	mu      sync.Mutex
+	entries map[string]*memoryEntry
+	calls   int
+}
+
+type memoryEntry struct {
+	expireAt time.Time
+
+	// 令牌桶
+	tokens float64
+	last   time.Time
+	// 滑动窗口日志
+	log []time.Time
+	// GCRA 理论到达时间
+	tat time.Time
+}
+
+// NewMemoryLimiter 创建进程内限流器
+func NewMemoryLimiter(algorithm Algorithm) (*MemoryLimiter, error) {
+	if !ValidAlgorithm(algorithm) {
+		return nil, fmt.Errorf("ratelimit: unsupported algorithm %q", algorithm)
+	}
+	return &MemoryLimiter{
+		algorithm: algorithm,
+		now:       time.Now,
+		entries:   make(map[string]*memoryEntry),
+	}, nil
+}
+
+// Allow 实现 Limiter
+func (m *MemoryLimiter) Allow(_ context.Context, key string, limit Limit) (*Result, error) {
+	if err := checkLimit(limit); err != nil {
+		return nil, err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := m.now()
+	m.calls++
+	if m.calls%sweepEvery == 0 {
+		m.sweep(now)
+	}
+
+	e, ok := m.entries[key]
+	if !ok || now.After(e.expireAt) {
+		e = &memoryEntry{}
+		m.entries[key] = e
+	}
+
+	switch m.algorithm {
+	case TokenBucket:
+		return m.tokenBucket(e, limit, now), nil
+	case SlidingWindow:
+		return m.slidingWindow(e, limit, now), nil
+	default:
+		return m.gcra(e, limit, now), nil
+	}
+}
+
+func (m *MemoryLimiter) tokenBucket(e *memoryEntry, limit Limit, now time.Time) *Result {
+	capacity := float64(limit.burst())
+	interval := limit.interval()
+
+	if e.last.IsZero() {
+		e.tokens = capacity
+		e.last = now
+	}
+	if now.After(e.last) {
+		e.tokens = math.Min(capacity, e.tokens+float64(now.Sub(e.last))/float64(interval))
+		e.last = now
+	}
+
+	res := &Result{Limit: limit.burst()}
+	if e.tokens >= 1 {
+		e.tokens--
+		res.Allowed = true
+	} else {
+		res.RetryAfter = time.Duration(math.Ceil((1 - e.tokens) * float64(interval)))
+	}
+	res.Remaining = int(e.tokens)
+	res.ResetAfter = time.Duration(math.Ceil((capacity - e.tokens) * float64(interval)))
+	e.expireAt = now.Add(time.Duration(capacity) * interval)
+	return res
+}
+
+func (m *MemoryLimiter) slidingWindow(e *memoryEntry, limit Limit, now time.Time) *Result {
+	boundary := now.Add(-limit.Period)
+	i := 0
+	for i < len(e.log) && !e.log[i].After(boundary) {
+		i++
+	}
+	e.log = e.log[i:]
+
+	res := &Result{Limit: limit.Rate}
+	if len(e.log) < limit.Rate {
+		e.log = append(e.log, now)
+		res.Allowed = true
+		res.Remaining = limit.Rate - len(e.log)
+	} else {
+		res.RetryAfter = e.log[0].Add(limit.Period).Sub(now)
+	}
+	res.ResetAfter = e.log[len(e.log)-1].Add(limit.Period).Sub(now)
+	e.expireAt = now.Add(limit.Period)
+	return res
+}
+
+func (m *MemoryLimiter) gcra(e *memoryEntry, limit Limit, now time.Time) *Result {
+	emission := limit.interval()
+	offset := emission * time.Duration(limit.burst())
+
+	tat := e.tat
+	if tat.Before(now) {
+		tat = now
+	}
+	newTat := tat.Add(emission)
+	diff := now.Sub(newTat.Add(-offset))
+
+	res := &Result{Limit: limit.burst()}
+	if diff < 0 {
+		res.RetryAfter = -diff
+		res.ResetAfter = tat.Sub(now)
+		return res
+	}
+	e.tat = newTat
+	e.expireAt = newTat
+	res.Allowed = true
+	res.Remaining = int(diff / emission)
+	res.ResetAfter = newTat.Sub(now)
+	return res
+}
+
+// sweep 清理过期状态，避免 key 无限增长（调用方持锁）
+func (m *MemoryLimiter) sweep(now time.Time) {
+	for k, e := range m.entries {
+		if now.After(e.expireAt) {
+			delete(m.entries, k)
+		}
+	}
+}
@@ -0,0 +1,151 @@
+package ratelimit
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// KeyType 限流维度
+type KeyType string
+
+// 支持的限流维度
+const (
+	KeyByIP     KeyType = "ip"
+	KeyByUser   KeyType = "user"
+	KeyByAPIKey KeyType = "api_key"
+	KeyByRoute  KeyType = "route"
+)
+
+// Policy 限流策略：命中 Routes 的请求按 Key 维度计数，Roles 可按角色覆盖默认配额
+type Policy struct {
+	Name   string
+	Routes []string // 形如 "/api/v1/user/login"、"POST /api/v1/user/login"、"/api/v1/admin/*"
+	Key    KeyType
+	Limit  Limit
+	Roles  map[string]Limit
+}
+
+// LimitFor 返回角色对应的配额，未配置时使用默认配额
+func (p *Policy) LimitFor(role string) Limit {
+	if l, ok := p.Roles[role]; ok {
+		return l
+	}
+	return p.Limit
+}
+
+// routePattern 解析后的路由模式
+type routePattern struct {
+	method string // 空表示任意方法
+	path   string
+	prefix bool // 以 /* 结尾，匹配整个路由组
+}
+
+func parseRoutePattern(s string) (routePattern, error) {
+	var rp routePattern
+	s = strings.TrimSpace(s)
+	if i := strings.IndexByte(s, ' '); i > 0 {
+		rp.method = strings.ToUpper(s[:i])
+		s = strings.TrimSpace(s[i+1:])
+	}
+	if !strings.HasPrefix(s, "/") {
+		return rp, fmt.Errorf("ratelimit: route pattern %q must start with /", s)
+	}
+	if strings.HasSuffix(s, "/*") {
+		rp.prefix = true
+		s = strings.TrimSuffix(s, "/*")
+	}
+	rp.path = s
+	return rp, nil
+}
+
+func (rp routePattern) match(method, path string) bool {
+	if rp.method != "" && rp.method != method {
+		return false
+	}
+	if !rp.prefix {
+		return rp.path == path
+	}
+	return path == rp.path || strings.HasPrefix(path, rp.path+"/")
+}
+
+// PolicyTable 不可变的策略表，按声明顺序匹配，先命中者生效。
+// 热更新时整体替换，无需加锁。
+type PolicyTable struct {
+	policies []*Policy
+	patterns [][]routePattern
+	cache    sync.Map // method+" "+path → *Policy（未命中存 nil）
+}
+
+// NewPolicyTable 校验并构造策略表
+func NewPolicyTable(policies []*Policy) (*PolicyTable, error) {
+	t := &PolicyTable{policies: policies, patterns: make([][]routePattern, len(policies))}
+	names := make(map[string]struct{}, len(policies))
+	for i, p := range policies {
+		if p.Name == "" {
+			return nil, fmt.Errorf("ratelimit: policy #%d has no name", i)
+		}
+		if _, dup := names[p.Name]; dup {
+			return nil, fmt.Errorf("ratelimit: duplicate policy %q", p.Name)
+		}
+		names[p.Name] = struct{}{}
+
+		switch p.Key {
+		case KeyByIP, KeyByUser, KeyByAPIKey, KeyByRoute:
+		default:
+			return nil, fmt.Errorf("ratelimit: policy %q has unsupported key %q", p.Name, p.Key)
+		}
+		if err := checkLimit(p.Limit); err != nil {
+			return nil, fmt.Errorf("policy %q: %w", p.Name, err)
+		}
+		for role, l := range p.Roles {
+			if err := checkLimit(l); err != nil {
+				return nil, fmt.Errorf("policy %q role %q: %w", p.Name, role, err)
+			}
+		}
+		if len(p.Routes) == 0 {
+			return nil, fmt.Errorf("ratelimit: policy %q has no routes", p.Name)
+		}
+		for _, r := range p.Routes {
+			rp, err := parseRoutePattern(r)
+			if err != nil {
+				return nil, fmt.Errorf("policy %q: %w", p.Name, err)
+			}
+			t.patterns[i] = append(t.patterns[i], rp)
+		}
+	}
+	return t, nil
+}
+
+// Match 查找路由对应的策略，未命中返回 nil
+func (t *PolicyTable) Match(method, path string) *Policy {
+	if t == nil {
+		return nil
+	}
+	cacheKey := method + " " + path
+	if v, ok := t.cache.Load(cacheKey); ok {
+		return v.(*Policy)
+	}
+	var matched *Policy
+	for i, patterns := range t.patterns {
+		for _, rp := range patterns {
+			if rp.match(method, path) {
+				matched = t.policies[i]
+				break
+			}
+		}
+		if matched != nil {
+			break
+		}
+	}
+	t.cache.Store(cacheKey, matched)
+	return matched
+}
+
+// Len 策略数量
+func (t *PolicyTable) Len() int {
+	if t == nil {
+		return 0
+	}
+	return len(t.policies)
+}
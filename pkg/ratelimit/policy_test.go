@@ -0,0 +1,79 @@
+package ratelimit
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPolicyTableMatch(t *testing.T) {
+	login := &Policy{
+		Name:   "login",
+		Routes: []string{"POST /api/v1/user/login"},
+		Key:    KeyByIP,
+		Limit:  PerMinute(10),
+	}
+	admin := &Policy{
+		Name:   "admin",
+		Routes: []string{"/api/v1/admin/*"},
+		Key:    KeyByUser,
+		Limit:  PerMinute(100),
+		Roles:  map[string]Limit{"admin": PerMinute(1000)},
+	}
+	table, err := NewPolicyTable([]*Policy{login, admin})
+	require.NoError(t, err)
+
+	tests := []struct {
+		method, path string
+		expected     *Policy
+	}{
+		{"POST", "/api/v1/user/login", login},
+		{"GET", "/api/v1/user/login", nil},
+		{"POST", "/api/v1/user/register", nil},
+		{"GET", "/api/v1/admin", admin},
+		{"GET", "/api/v1/admin/users", admin},
+		{"POST", "/api/v1/admin/users/revoke-sessions", admin},
+		{"GET", "/api/v1/administrator", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.method+" "+tt.path, func(t *testing.T) {
+			assert.Same(t, tt.expected, table.Match(tt.method, tt.path))
+			// 第二次命中缓存，结果一致
+			assert.Same(t, tt.expected, table.Match(tt.method, tt.path))
+		})
+	}
+
+	assert.Equal(t, 1000, admin.LimitFor("admin").Rate)
+	assert.Equal(t, 100, admin.LimitFor("user").Rate)
+}
+
+func TestPolicyTableFirstMatchWins(t *testing.T) {
+	specific := &Policy{Name: "specific", Routes: []string{"/api/v1/admin/users"}, Key: KeyByUser, Limit: PerSecond(1)}
+	group := &Policy{Name: "group", Routes: []string{"/api/v1/admin/*"}, Key: KeyByUser, Limit: PerSecond(10)}
+	table, err := NewPolicyTable([]*Policy{specific, group})
+	require.NoError(t, err)
+
+	assert.Same(t, specific, table.Match("GET", "/api/v1/admin/users"))
+	assert.Same(t, group, table.Match("GET", "/api/v1/admin/roles"))
+}
+
+func TestPolicyTableValidation(t *testing.T) {
+	valid := Limit{Rate: 1, Period: time.Second}
+	tests := map[string][]*Policy{
+		"missing name":  {{Routes: []string{"/a"}, Key: KeyByIP, Limit: valid}},
+		"duplicate":     {{Name: "a", Routes: []string{"/a"}, Key: KeyByIP, Limit: valid}, {Name: "a", Routes: []string{"/b"}, Key: KeyByIP, Limit: valid}},
+		"bad key":       {{Name: "a", Routes: []string{"/a"}, Key: "cookie", Limit: valid}},
+		"zero limit":    {{Name: "a", Routes: []string{"/a"}, Key: KeyByIP}},
+		"bad role":      {{Name: "a", Routes: []string{"/a"}, Key: KeyByIP, Limit: valid, Roles: map[string]Limit{"admin": {}}}},
+		"no routes":     {{Name: "a", Key: KeyByIP, Limit: valid}},
+		"relative path": {{Name: "a", Routes: []string{"api/v1"}, Key: KeyByIP, Limit: valid}},
+	}
+	for name, policies := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := NewPolicyTable(policies)
+			assert.Error(t, err)
+		})
+	}
+}
@@ -0,0 +1,87 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// Algorithm 限流算法
+type Algorithm string
+
+// 支持的限流算法
+const (
+	TokenBucket   Algorithm = "token_bucket"   // 令牌桶：按速率补充，允许 Burst 大小的突发
+	SlidingWindow Algorithm = "sliding_window" // 滑动窗口日志：精确统计任意 Period 内的请求数
+	GCRA          Algorithm = "gcra"           // 通用信元速率算法：单值状态，平滑且内存占用最小
+)
+
+// Limit 配额：每 Period 允许 Rate 次请求，Burst 为突发容量（<=0 时等于 Rate）。
+// 滑动窗口算法不区分突发，只看 Rate。
+type Limit struct {
+	Rate   int
+	Period time.Duration
+	Burst  int
+}
+
+// PerSecond 每秒 n 次
+func PerSecond(n int) Limit { return Limit{Rate: n, Period: time.Second} }
+
+// PerMinute 每分钟 n 次
+func PerMinute(n int) Limit { return Limit{Rate: n, Period: time.Minute} }
+
+// PerHour 每小时 n 次
+func PerHour(n int) Limit { return Limit{Rate: n, Period: time.Hour} }
+
+// String 形如 "60/1m0s"，便于日志展示
+func (l Limit) String() string {
+	return fmt.Sprintf("%d/%s", l.Rate, l.Period)
+}
+
+// IsZero 未配置配额
+func (l Limit) IsZero() bool {
+	return l.Rate <= 0 || l.Period <= 0
+}
+
+func (l Limit) burst() int {
+	if l.Burst <= 0 {
+		return l.Rate
+	}
+	return l.Burst
+}
+
+// interval 补充一个令牌所需时间
+func (l Limit) interval() time.Duration {
+	return l.Period / time.Duration(l.Rate)
+}
+
+// Result 单次判定结果
+type Result struct {
+	Allowed    bool
+	Limit      int           // 当前配额上限（用于 RateLimit-Limit 头）
+	Remaining  int           // 剩余可用次数
+	RetryAfter time.Duration // 被拒绝时距下次可用的时间；放行时为 0
+	ResetAfter time.Duration // 配额完全恢复所需时间
+}
+
+// Limiter 限流器
+type Limiter interface {
+	// Allow 对 key 消耗一次配额并返回判定结果
+	Allow(ctx context.Context, key string, limit Limit) (*Result, error)
+}
+
+// ValidAlgorithm 判断算法名是否受支持
+func ValidAlgorithm(a Algorithm) bool {
+	switch a {
+	case TokenBucket, SlidingWindow, GCRA:
+		return true
+	}
+	return false
+}
+
+func checkLimit(limit Limit) error {
+	if limit.IsZero() {
+		return fmt.Errorf("ratelimit: invalid limit %s", limit)
+	}
+	return nil
+}
@@ -0,0 +1,114 @@
+package ratelimit
+
+import (
+	"context"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	pkgredis "github.com/liuchen/gin-craft/pkg/redis"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeClock 可手动推进的时钟
+type fakeClock struct{ t time.Time }
+
+func (c *fakeClock) Now() time.Time          { return c.t }
+func (c *fakeClock) Advance(d time.Duration) { c.t = c.t.Add(d) }
+
+// newLimiters 为同一算法构造内存与 Redis 两种后端，共用一个时钟
+func newLimiters(t *testing.T, alg Algorithm) (map[string]Limiter, *fakeClock) {
+	t.Helper()
+	clock := &fakeClock{t: time.Unix(1700000000, 0)}
+
+	mem, err := NewMemoryLimiter(alg)
+	require.NoError(t, err)
+	mem.now = clock.Now
+
+	mr := miniredis.RunT(t)
+	port, err := strconv.Atoi(mr.Port())
+	require.NoError(t, err)
+	client := pkgredis.NewClient(&pkgredis.Config{Host: mr.Host(), Port: port})
+	require.NoError(t, client.Connect())
+	t.Cleanup(func() { _ = client.Close() })
+
+	rl, err := NewRedisLimiter(client, alg, "test:")
+	require.NoError(t, err)
+	rl.now = clock.Now
+
+	return map[string]Limiter{"memory": mem, "redis": rl}, clock
+}
+
+func TestAlgorithms(t *testing.T) {
+	ctx := context.Background()
+	limit := Limit{Rate: 3, Period: 3 * time.Second}
+
+	for _, alg := range []Algorithm{TokenBucket, SlidingWindow, GCRA} {
+		limiters, clock := newLimiters(t, alg)
+		for backend, l := range limiters {
+			t.Run(string(alg)+"/"+backend, func(t *testing.T) {
+				key := "user:" + backend
+
+				for i := 0; i < 3; i++ {
+					res, err := l.Allow(ctx, key, limit)
+					require.NoError(t, err)
+					assert.True(t, res.Allowed, "request %d", i)
+					assert.Equal(t, 2-i, res.Remaining)
+					assert.Equal(t, 3, res.Limit)
+				}
+
+				res, err := l.Allow(ctx, key, limit)
+				require.NoError(t, err)
+				assert.False(t, res.Allowed)
+				assert.Equal(t, 0, res.Remaining)
+				assert.Greater(t, res.RetryAfter, time.Duration(0))
+				assert.LessOrEqual(t, res.RetryAfter, limit.Period)
+
+				// 等待 RetryAfter 后应当恢复
+				clock.Advance(res.RetryAfter)
+				res, err = l.Allow(ctx, key, limit)
+				require.NoError(t, err)
+				assert.True(t, res.Allowed)
+
+				// 其他 key 互不影响
+				res, err = l.Allow(ctx, "other:"+backend, limit)
+				require.NoError(t, err)
+				assert.True(t, res.Allowed)
+			})
+		}
+	}
+}
+
+func TestBurst(t *testing.T) {
+	ctx := context.Background()
+	limit := Limit{Rate: 1, Period: time.Second, Burst: 5}
+
+	for _, alg := range []Algorithm{TokenBucket, GCRA} {
+		limiters, _ := newLimiters(t, alg)
+		for backend, l := range limiters {
+			t.Run(string(alg)+"/"+backend, func(t *testing.T) {
+				allowed := 0
+				for i := 0; i < 10; i++ {
+					res, err := l.Allow(ctx, "burst", limit)
+					require.NoError(t, err)
+					if res.Allowed {
+						allowed++
+					}
+				}
+				assert.Equal(t, 5, allowed)
+			})
+		}
+	}
+}
+
+func TestInvalidInput(t *testing.T) {
+	_, err := NewMemoryLimiter("leaky")
+	assert.Error(t, err)
+
+	l, err := NewMemoryLimiter(GCRA)
+	require.NoError(t, err)
+	_, err = l.Allow(context.Background(), "k", Limit{})
+	assert.Error(t, err)
+}
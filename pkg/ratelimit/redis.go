@@ -0,0 +1,166 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	pkgredis "github.com/liuchen/gin-craft/pkg/redis"
+	"github.com/redis/go-redis/v9"
+)
+
+// 各脚本统一返回 {allowed, remaining, retry_after_us, reset_after_us}，时间单位均为微秒。
+// 当前时间由调用方传入，多实例部署需保证时钟同步（NTP）。
+
+var tokenBucketScript = redis.NewScript(`
+local capacity = tonumber(ARGV[1])
+local interval = tonumber(ARGV[2])
+local now = tonumber(ARGV[3])
+
+local state = redis.call('HMGET', KEYS[1], 'tokens', 'ts')
+local tokens = tonumber(state[1])
+local ts = tonumber(state[2])
+if tokens == nil then
+  tokens = capacity
+  ts = now
+end
+if now > ts then
+  tokens = math.min(capacity, tokens + (now - ts) / interval)
+  ts = now
+end
+
+local allowed = 0
+local retry = 0
+if tokens >= 1 then
+  tokens = tokens - 1
+  allowed = 1
+else
+  retry = math.ceil((1 - tokens) * interval)
+end
+local reset = math.ceil((capacity - tokens) * interval)
+
+redis.call('HSET', KEYS[1], 'tokens', tostring(tokens), 'ts', string.format('%d', ts))
+redis.call('PEXPIRE', KEYS[1], math.ceil(capacity * interval / 1000))
+return {allowed, math.floor(tokens), retry, reset}
+`)
+
+var slidingWindowScript = redis.NewScript(`
+local limit = tonumber(ARGV[1])
+local window = tonumber(ARGV[2])
+local now = tonumber(ARGV[3])
+
+redis.call('ZREMRANGEBYSCORE', KEYS[1], '-inf', string.format('%d', now - window))
+local count = redis.call('ZCARD', KEYS[1])
+
+local allowed = 0
+local remaining = 0
+local retry = 0
+if count < limit then
+  redis.call('ZADD', KEYS[1], string.format('%d', now), ARGV[4])
+  redis.call('PEXPIRE', KEYS[1], math.ceil(window / 1000))
+  allowed = 1
+  remaining = limit - count - 1
+else
+  local oldest = redis.call('ZRANGE', KEYS[1], 0, 0, 'WITHSCORES')
+  retry = tonumber(oldest[2]) + window - now
+end
+local newest = redis.call('ZRANGE', KEYS[1], -1, -1, 'WITHSCORES')
+return {allowed, remaining, retry, tonumber(newest[2]) + window - now}
+`)
+
+var gcraScript = redis.NewScript(`
+local emission = tonumber(ARGV[1])
+local burst = tonumber(ARGV[2])
+local now = tonumber(ARGV[3])
+
+local tat = tonumber(redis.call('GET', KEYS[1]))
+if tat == nil or tat < now then
+  tat = now
+end
+local new_tat = tat + emission
+local diff = now - (new_tat - emission * burst)
+if diff < 0 then
+  return {0, 0, -diff, tat - now}
+end
+
+redis.call('SET', KEYS[1], string.format('%d', new_tat), 'PX', math.ceil((new_tat - now) / 1000))
+return {1, math.floor(diff / emission), 0, new_tat - now}
+`)
+
+// RedisLimiter 基于 Redis Lua 脚本的分布式限流器，判定与状态更新在一次脚本调用内原子完成
+type RedisLimiter struct {
+	client    *pkgredis.Client
+	algorithm Algorithm
+	prefix    string
+	now       func() time.Time
+}
+
+// NewRedisLimiter 创建分布式限流器，prefix 为 key 前缀
+func NewRedisLimiter(client *pkgredis.Client, algorithm Algorithm, prefix string) (*RedisLimiter, error) {
+	if !ValidAlgorithm(algorithm) {
+		return nil, fmt.Errorf("ratelimit: unsupported algorithm %q", algorithm)
+	}
+	if prefix == "" {
+		prefix = "ratelimit:"
+	}
+	return &RedisLimiter{
+		client:    client,
+		algorithm: algorithm,
+		prefix:    prefix,
+		now:       time.Now,
+	}, nil
+}
+
+// Allow 实现 Limiter
+func (r *RedisLimiter) Allow(ctx context.Context, key string, limit Limit) (*Result, error) {
+	if err := checkLimit(limit); err != nil {
+		return nil, err
+	}
+	rdb := r.client.GetClient()
+	if rdb == nil {
+		return nil, fmt.Errorf("redis not connected")
+	}
+
+	now := r.now().UnixMicro()
+	keys := []string{r.prefix + string(r.algorithm) + ":" + key}
+	res := &Result{Limit: limit.burst()}
+
+	var (
+		values []interface{}
+		err    error
+	)
+	switch r.algorithm {
+	case TokenBucket:
+		values, err = tokenBucketScript.Run(ctx, rdb, keys,
+			limit.burst(), limit.interval().Microseconds(), now).Slice()
+	case SlidingWindow:
+		res.Limit = limit.Rate
+		values, err = slidingWindowScript.Run(ctx, rdb, keys,
+			limit.Rate, limit.Period.Microseconds(), now, strconv.FormatInt(now, 10)+"-"+uuid.NewString()).Slice()
+	default:
+		values, err = gcraScript.Run(ctx, rdb, keys,
+			limit.interval().Microseconds(), limit.burst(), now).Slice()
+	}
+	if err != nil {
+		return nil, fmt.Errorf("ratelimit: run script: %w", err)
+	}
+	if len(values) != 4 {
+		return nil, fmt.Errorf("ratelimit: unexpected script result %v", values)
+	}
+
+	nums := make([]int64, 4)
+	for i, v := range values {
+		n, ok := v.(int64)
+		if !ok {
+			return nil, fmt.Errorf("ratelimit: unexpected script result %v", values)
+		}
+		nums[i] = n
+	}
+	res.Allowed = nums[0] == 1
+	res.Remaining = int(nums[1])
+	res.RetryAfter = time.Duration(nums[2]) * time.Microsecond
+	res.ResetAfter = time.Duration(nums[3]) * time.Microsecond
+	return res, nil
+}
@@ -0,0 +1,198 @@
+package search
+
+import (
+	"context"
+	"sort"
+	"sync"
+)
+
+const (
+	gramSize  = 3  // 子串匹配使用的 n-gram 长度
+	maxPrefix = 20 // 索引的最长前缀（字符数），更长的查询词退化为子串匹配
+)
+
+// 各匹配方式的得分倍数：完整词 > 前缀 > 子串
+const (
+	exactBoost  = 4
+	prefixBoost = 2
+	gramBoost   = 1
+)
+
+// Memory 进程内倒排索引。每个词索引完整词、各级前缀与 3-gram，
+// 查询词依次尝试完整匹配、前缀匹配与子串匹配（查询词的全部 3-gram 均出现即视为命中）。
+// 索引只存在于当前进程，多实例部署时各实例的写入互不可见
+type Memory struct {
+	mu       sync.RWMutex
+	postings map[string]map[uint64]float64 // token → 文档 → 权重
+	docs     map[uint64][]string           // 文档 → 已索引的 token，用于覆盖与删除
+}
+
+// NewMemory 创建空的内存索引
+func NewMemory() *Memory {
+	return &Memory{
+		postings: make(map[string]map[uint64]float64),
+		docs:     make(map[uint64][]string),
+	}
+}
+
+// Index 写入或覆盖文档
+func (m *Memory) Index(_ context.Context, docs ...Document) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, doc := range docs {
+		m.remove(doc.ID)
+		weights := make(map[string]float64)
+		for _, f := range doc.Fields {
+			w := f.Weight
+			if w <= 0 {
+				w = 1
+			}
+			for _, term := range Tokenize(f.Text) {
+				for token, boost := range termTokens(term) {
+					weights[token] = max(weights[token], w*boost)
+				}
+			}
+		}
+		tokens := make([]string, 0, len(weights))
+		for token, w := range weights {
+			if m.postings[token] == nil {
+				m.postings[token] = make(map[uint64]float64)
+			}
+			m.postings[token][doc.ID] = w
+			tokens = append(tokens, token)
+		}
+		m.docs[doc.ID] = tokens
+	}
+	return nil
+}
+
+// Remove 删除文档
+func (m *Memory) Remove(_ context.Context, ids ...uint64) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, id := range ids {
+		m.remove(id)
+	}
+	return nil
+}
+
+// Len 已索引的文档数
+func (m *Memory) Len() int {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return len(m.docs)
+}
+
+// Search 检索，得分为各查询词最佳匹配得分之和
+func (m *Memory) Search(_ context.Context, text string, limit int) ([]Hit, error) {
+	terms := Tokenize(text)
+	if len(terms) == 0 || limit <= 0 {
+		return nil, nil
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	var scores map[uint64]float64
+	for _, term := range terms {
+		matched := m.match(term)
+		if scores == nil {
+			scores = matched
+			continue
+		}
+		// 文档须匹配全部查询词
+		for id, s := range scores {
+			if ms, ok := matched[id]; ok {
+				scores[id] = s + ms
+			} else {
+				delete(scores, id)
+			}
+		}
+	}
+
+	hits := make([]Hit, 0, len(scores))
+	for id, s := range scores {
+		hits = append(hits, Hit{ID: id, Score: s})
+	}
+	sort.Slice(hits, func(i, j int) bool {
+		if hits[i].Score != hits[j].Score {
+			return hits[i].Score > hits[j].Score
+		}
+		return hits[i].ID < hits[j].ID
+	})
+	if len(hits) > limit {
+		hits = hits[:limit]
+	}
+	return hits, nil
+}
+
+// match 单个查询词命中的文档及得分，调用方持有读锁
+func (m *Memory) match(term string) map[uint64]float64 {
+	out := make(map[uint64]float64)
+	keep := func(id uint64, s float64) {
+		out[id] = max(out[id], s)
+	}
+	for id, w := range m.postings["="+term] {
+		keep(id, w)
+	}
+	for id, w := range m.postings["^"+term] {
+		keep(id, w)
+	}
+
+	grams := ngrams([]rune(term))
+	if len(grams) == 0 {
+		return out
+	}
+	// 以第一个 gram 的倒排表为候选，要求其余 gram 同样出现，得分取各 gram 权重的最小值
+	for id, w := range m.postings["#"+grams[0]] {
+		s := w
+		for _, g := range grams[1:] {
+			gw, ok := m.postings["#"+g][id]
+			if !ok {
+				s = 0
+				break
+			}
+			s = min(s, gw)
+		}
+		if s > 0 {
+			keep(id, s)
+		}
+	}
+	return out
+}
+
+func (m *Memory) remove(id uint64) {
+	for _, token := range m.docs[id] {
+		delete(m.postings[token], id)
+		if len(m.postings[token]) == 0 {
+			delete(m.postings, token)
+		}
+	}
+	delete(m.docs, id)
+}
+
+// termTokens 一个词需要索引的 token 及其得分倍数：
+// "=词" 完整词，"^前缀" 真前缀，"#gram" 子串
+func termTokens(term string) map[string]float64 {
+	runes := []rune(term)
+	out := map[string]float64{"=" + term: exactBoost}
+	for i := 1; i < len(runes) && i <= maxPrefix; i++ {
+		out["^"+string(runes[:i])] = prefixBoost
+	}
+	for _, g := range ngrams(runes) {
+		if _, ok := out["#"+g]; !ok {
+			out["#"+g] = gramBoost
+		}
+	}
+	return out
+}
+
+func ngrams(runes []rune) []string {
+	if len(runes) < gramSize {
+		return nil
+	}
+	out := make([]string, 0, len(runes)-gramSize+1)
+	for i := 0; i+gramSize <= len(runes); i++ {
+		out = append(out, string(runes[i:i+gramSize]))
+	}
+	return out
+}
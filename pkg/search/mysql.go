@@ -0,0 +1,70 @@
+package search
+
+import (
+	"context"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// MySQL 基于 FULLTEXT 索引（ngram 解析器）的检索，索引由 MySQL 随表数据维护，
+// Index/Remove 为空操作。命中结果可能包含软删除的行，由调用方按主表过滤
+type MySQL struct {
+	db      *gorm.DB
+	table   string
+	columns []string
+}
+
+// NewMySQL 创建 MySQL 检索，columns 须与 FULLTEXT 索引的列完全一致
+func NewMySQL(db *gorm.DB, table string, columns ...string) *MySQL {
+	return &MySQL{db: db, table: table, columns: columns}
+}
+
+// Index 空操作
+func (m *MySQL) Index(context.Context, ...Document) error { return nil }
+
+// Remove 空操作
+func (m *MySQL) Remove(context.Context, ...uint64) error { return nil }
+
+// Search 以 BOOLEAN MODE 检索，每个查询词都必须出现，并按前缀匹配
+func (m *MySQL) Search(ctx context.Context, text string, limit int) ([]Hit, error) {
+	q := booleanQuery(text)
+	if q == "" || limit <= 0 {
+		return nil, nil
+	}
+	match := "MATCH(" + strings.Join(m.columns, ",") + ") AGAINST(? IN BOOLEAN MODE)"
+	var hits []Hit
+	err := m.db.WithContext(ctx).Table(m.table).
+		Select("id, "+match+" AS score", q).
+		Where(match, q).
+		Order("score DESC, id ASC").
+		Limit(limit).
+		Scan(&hits).Error
+	return hits, err
+}
+
+// EnsureIndex 索引不存在时创建 FULLTEXT 索引，使用 ngram 解析器以支持中文与子串检索
+func (m *MySQL) EnsureIndex(ctx context.Context) error {
+	name := "ft_" + m.table
+	var cnt int64
+	err := m.db.WithContext(ctx).Raw(
+		"SELECT COUNT(*) FROM information_schema.statistics WHERE table_schema = DATABASE() AND table_name = ? AND index_name = ?",
+		m.table, name,
+	).Scan(&cnt).Error
+	if err != nil || cnt > 0 {
+		return err
+	}
+	return m.db.WithContext(ctx).Exec(
+		"CREATE FULLTEXT INDEX " + name + " ON " + m.table + " (" + strings.Join(m.columns, ",") + ") WITH PARSER ngram",
+	).Error
+}
+
+// booleanQuery 将检索文本转换为 "+词1* +词2*"。Tokenize 只保留字母与数字，
+// 用户输入中的布尔运算符不会进入查询
+func booleanQuery(text string) string {
+	terms := Tokenize(text)
+	for i, t := range terms {
+		terms[i] = "+" + t + "*"
+	}
+	return strings.Join(terms, " ")
+}
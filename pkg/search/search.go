@@ -0,0 +1,44 @@
+// Package search 全文检索：Searcher 接口及进程内倒排索引（Memory）与 MySQL FULLTEXT 两种实现
+package search
+
+import (
+	"context"
+	"strings"
+	"unicode"
+)
+
+// Field 文档中的一个可检索字段，Weight 为排序权重，0 按 1 处理
+type Field struct {
+	Text   string
+	Weight float64
+}
+
+// Document 待索引的文档
+type Document struct {
+	ID     uint64
+	Fields []Field
+}
+
+// Hit 一条检索结果，按 Score 从高到低排列
+type Hit struct {
+	ID    uint64  `gorm:"column:id"`
+	Score float64 `gorm:"column:score"`
+}
+
+// Searcher 全文检索。text 按 Tokenize 切分为多个词，文档须匹配全部词（前缀或子串）才会命中；
+// 由数据库维护索引的实现中 Index/Remove 为空操作
+type Searcher interface {
+	// Index 写入或覆盖文档
+	Index(ctx context.Context, docs ...Document) error
+	// Remove 删除文档，不存在时忽略
+	Remove(ctx context.Context, ids ...uint64) error
+	// Search 检索，最多返回 limit 条
+	Search(ctx context.Context, text string, limit int) ([]Hit, error)
+}
+
+// Tokenize 转小写后按字母、数字以外的字符切词，"John.Doe@Example.com" → john doe example com
+func Tokenize(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
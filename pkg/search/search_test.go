@@ -0,0 +1,69 @@
+package search
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTokenize(t *testing.T) {
+	assert.Equal(t, []string{"john", "doe", "example", "com"}, Tokenize("John.Doe@Example.com"))
+	assert.Equal(t, []string{"张三", "a1"}, Tokenize(" 张三 _a1 "))
+	assert.Empty(t, Tokenize("+-*\"()"))
+}
+
+func TestMemorySearch(t *testing.T) {
+	ctx := context.Background()
+	m := NewMemory()
+	user := func(id uint64, username, email string) Document {
+		return Document{ID: id, Fields: []Field{{Text: username, Weight: 2}, {Text: email}}}
+	}
+	require.NoError(t, m.Index(ctx,
+		user(1, "john", "john@example.com"),
+		user(2, "johnny", "jj@corp.io"),
+		user(3, "alice", "alice.johnson@example.com"),
+		user(4, "bob", "bob@corp.io"),
+	))
+	assert.Equal(t, 4, m.Len())
+
+	ids := func(text string) []uint64 {
+		hits, err := m.Search(ctx, text, 10)
+		require.NoError(t, err)
+		out := make([]uint64, 0, len(hits))
+		for _, h := range hits {
+			out = append(out, h.ID)
+		}
+		return out
+	}
+
+	// 用户名完整匹配 > 用户名前缀 > 邮箱前缀
+	assert.Equal(t, []uint64{1, 2, 3}, ids("john"))
+	// 子串匹配
+	assert.Equal(t, []uint64{3}, ids("lic"))
+	// 多个词须全部命中
+	assert.Equal(t, []uint64{2, 4}, ids("corp"))
+	assert.Equal(t, []uint64{4}, ids("bob corp"))
+	assert.Empty(t, ids("nobody"))
+	assert.Empty(t, ids("!!"))
+
+	// 覆盖与删除
+	require.NoError(t, m.Index(ctx, user(4, "robert", "robert@corp.io")))
+	assert.Empty(t, ids("bob"))
+	assert.Equal(t, []uint64{4}, ids("rob"))
+	require.NoError(t, m.Remove(ctx, 2, 4))
+	assert.Equal(t, []uint64{1, 3}, ids("john"))
+	assert.Empty(t, ids("corp"))
+	assert.Equal(t, 2, m.Len())
+
+	hits, err := m.Search(ctx, "john", 1)
+	require.NoError(t, err)
+	assert.Len(t, hits, 1)
+}
+
+func TestBooleanQuery(t *testing.T) {
+	assert.Equal(t, "+john* +example*", booleanQuery("John @example"))
+	assert.Equal(t, "+a* +or* +b*", booleanQuery(`a") OR ("b`))
+	assert.Empty(t, booleanQuery("-+~"))
+}
@@ -0,0 +1,105 @@
+package signature
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// Signer 客户端签名器：以创建密钥时返回的签名密钥作为 HMAC 密钥，请求中只携带公开的密钥 ID
+type Signer struct {
+	keyID  string
+	secret []byte
+	now    func() time.Time
+}
+
+// NewSigner 使用密钥的公开 ID 与签名密钥（创建 API 密钥时一同返回的 signing_secret）创建签名器
+func NewSigner(keyID, signingSecret string) (*Signer, error) {
+	if keyID == "" || signingSecret == "" {
+		return nil, errors.New("signature: key id and signing secret are required")
+	}
+	return &Signer{keyID: keyID, secret: []byte(signingSecret), now: time.Now}, nil
+}
+
+// Sign 为请求写入签名头。请求体会被完整读取后还原
+func (s *Signer) Sign(r *http.Request) error {
+	var body []byte
+	if r.Body != nil && r.Body != http.NoBody {
+		b, err := io.ReadAll(r.Body)
+		if err != nil {
+			return err
+		}
+		_ = r.Body.Close()
+		body = b
+		r.Body = io.NopCloser(bytes.NewReader(body))
+		r.GetBody = func() (io.ReadCloser, error) {
+			return io.NopCloser(bytes.NewReader(body)), nil
+		}
+	}
+
+	nonce := make([]byte, 16)
+	if _, err := rand.Read(nonce); err != nil {
+		return err
+	}
+	ts := strconv.FormatInt(s.now().Unix(), 10)
+	n := hex.EncodeToString(nonce)
+
+	r.Header.Set(HeaderKeyID, s.keyID)
+	r.Header.Set(HeaderTimestamp, ts)
+	r.Header.Set(HeaderNonce, n)
+	r.Header.Set(HeaderSignature, Sign(s.secret, StringToSign(r.Method, r.URL.EscapedPath(), r.URL.Query(), body, ts, n)))
+	return nil
+}
+
+// Transport 自动为出站请求签名的 http.RoundTripper：
+//
+//	signer, _ := signature.NewSigner(keyID, signingSecret)
+//	client := &http.Client{Transport: &signature.Transport{Signer: signer}}
+type Transport struct {
+	Signer *Signer
+	Base   http.RoundTripper // 为空时使用 http.DefaultTransport
+}
+
+// RoundTrip 克隆请求并签名，不修改调用方请求的头部。Clone 与原请求共用 Body，
+// 因此签名读取的是副本：优先通过 GetBody 取得，否则缓冲原请求体并为原请求还原可重复读取的 Body 与 GetBody
+func (t *Transport) RoundTrip(r *http.Request) (*http.Response, error) {
+	signed := r.Clone(r.Context())
+	if r.Body != nil && r.Body != http.NoBody {
+		body, err := t.bodyCopy(r)
+		if err != nil {
+			return nil, err
+		}
+		signed.Body = body
+	}
+	if err := t.Signer.Sign(signed); err != nil {
+		return nil, err
+	}
+	base := t.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return base.RoundTrip(signed)
+}
+
+// bodyCopy 返回请求体的独立副本。按 RoundTripper 约定原请求的 Body 由本次调用关闭
+func (t *Transport) bodyCopy(r *http.Request) (io.ReadCloser, error) {
+	if r.GetBody != nil {
+		_ = r.Body.Close()
+		return r.GetBody()
+	}
+	b, err := io.ReadAll(r.Body)
+	_ = r.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	r.GetBody = func() (io.ReadCloser, error) {
+		return io.NopCloser(bytes.NewReader(b)), nil
+	}
+	r.Body, _ = r.GetBody()
+	return r.GetBody()
+}
@@ -0,0 +1,24 @@
+package signature
+
+import (
+	"context"
+	"time"
+
+	pkgredis "github.com/liuchen/gin-craft/pkg/redis"
+)
+
+// RedisNonceStore 基于 Redis SETNX 的 nonce 存储，多实例共享
+type RedisNonceStore struct {
+	client *pkgredis.Client
+	prefix string
+}
+
+// NewRedisNonceStore 创建 nonce 存储，key 为 prefix+nonce
+func NewRedisNonceStore(client *pkgredis.Client, prefix string) *RedisNonceStore {
+	return &RedisNonceStore{client: client, prefix: prefix}
+}
+
+// Claim 占用 nonce
+func (s *RedisNonceStore) Claim(ctx context.Context, nonce string, ttl time.Duration) (bool, error) {
+	return s.client.SetWithNX(ctx, s.prefix+nonce, 1, ttl)
+}
@@ -0,0 +1,163 @@
+package signature
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/liuchen/gin-craft/pkg/apikey"
+	pkgredis "github.com/liuchen/gin-craft/pkg/redis"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestVerifier(t *testing.T) *Verifier {
+	t.Helper()
+	mr := miniredis.RunT(t)
+	port, err := strconv.Atoi(mr.Port())
+	require.NoError(t, err)
+	client := pkgredis.NewClient(&pkgredis.Config{Host: mr.Host(), Port: port})
+	require.NoError(t, client.Connect())
+	t.Cleanup(func() { _ = client.Close() })
+	return NewVerifier(5*time.Minute, NewRedisNonceStore(client, "test:nonce:"))
+}
+
+// signedServer 使用 Verifier 校验请求，把结果通过 errs 传回测试
+func signedServer(t *testing.T, v *Verifier, secret []byte) (*httptest.Server, chan error) {
+	errs := make(chan error, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		errs <- v.Verify(r.Context(), r, body, secret, r.Header.Get(HeaderKeyID))
+	}))
+	t.Cleanup(srv.Close)
+	return srv, errs
+}
+
+func TestSignAndVerify(t *testing.T) {
+	key, err := apikey.Generate()
+	require.NoError(t, err)
+	secret := apikey.SigningSecret([]byte("pepper"), key.Hash)
+	signer, err := NewSigner(key.ID, secret)
+	require.NoError(t, err)
+
+	v := newTestVerifier(t)
+	srv, errs := signedServer(t, v, []byte(secret))
+	client := &http.Client{Transport: &Transport{Signer: signer}}
+
+	resp, err := client.Post(srv.URL+"/api/v1/api/data?b=2&a=1&a=0", "application/json", strings.NewReader(`{"x":1}`))
+	require.NoError(t, err)
+	_ = resp.Body.Close()
+	assert.NoError(t, <-errs)
+
+	resp, err = client.Get(srv.URL + "/api/v1/api/data")
+	require.NoError(t, err)
+	_ = resp.Body.Close()
+	assert.NoError(t, <-errs)
+}
+
+// roundTripFunc 把函数适配为 http.RoundTripper
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(r *http.Request) (*http.Response, error) { return f(r) }
+
+func TestTransportKeepsCallerBody(t *testing.T) {
+	signer, err := NewSigner("kid", "secret")
+	require.NoError(t, err)
+	var sent string
+	tr := &Transport{Signer: signer, Base: roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		b, err := io.ReadAll(r.Body)
+		sent = string(b)
+		assert.NotEmpty(t, r.Header.Get(HeaderKeyID))
+		return &http.Response{StatusCode: http.StatusOK, Body: http.NoBody, Request: r}, err
+	})}
+
+	cases := map[string]io.Reader{
+		"with GetBody":    strings.NewReader(`{"x":1}`),
+		"without GetBody": io.MultiReader(strings.NewReader(`{"x":1}`)),
+	}
+	for name, body := range cases {
+		t.Run(name, func(t *testing.T) {
+			req, err := http.NewRequest(http.MethodPost, "http://example.com/api", body)
+			require.NoError(t, err)
+			_, err = tr.RoundTrip(req)
+			require.NoError(t, err)
+			assert.Equal(t, `{"x":1}`, sent)
+			assert.Empty(t, req.Header.Get(HeaderKeyID))
+
+			require.NotNil(t, req.GetBody)
+			again, err := req.GetBody()
+			require.NoError(t, err)
+			b, _ := io.ReadAll(again)
+			assert.Equal(t, `{"x":1}`, string(b))
+		})
+	}
+}
+
+func TestVerifyRejects(t *testing.T) {
+	key, err := apikey.Generate()
+	require.NoError(t, err)
+	secret := []byte(apikey.SigningSecret([]byte("pepper"), key.Hash))
+	signer, err := NewSigner(key.ID, string(secret))
+	require.NoError(t, err)
+	v := newTestVerifier(t)
+	ctx := context.Background()
+
+	newSigned := func(body string) (*http.Request, []byte) {
+		r := httptest.NewRequest(http.MethodPost, "/api/v1/api/data?x=1", strings.NewReader(body))
+		require.NoError(t, signer.Sign(r))
+		b, _ := io.ReadAll(r.Body)
+		return r, b
+	}
+
+	t.Run("replayed nonce", func(t *testing.T) {
+		r, body := newSigned("{}")
+		require.NoError(t, v.Verify(ctx, r, body, secret, signer.keyID))
+		assert.ErrorIs(t, v.Verify(ctx, r, body, secret, signer.keyID), ErrReplayed)
+	})
+
+	t.Run("tampered body", func(t *testing.T) {
+		r, _ := newSigned(`{"amount":1}`)
+		assert.ErrorIs(t, v.Verify(ctx, r, []byte(`{"amount":100}`), secret, signer.keyID), ErrMismatch)
+	})
+
+	t.Run("tampered query", func(t *testing.T) {
+		r, body := newSigned("{}")
+		r.URL.RawQuery = url.Values{"x": {"2"}}.Encode()
+		assert.ErrorIs(t, v.Verify(ctx, r, body, secret, signer.keyID), ErrMismatch)
+	})
+
+	t.Run("wrong secret", func(t *testing.T) {
+		r, body := newSigned("{}")
+		assert.ErrorIs(t, v.Verify(ctx, r, body, []byte("other"), signer.keyID), ErrMismatch)
+	})
+
+	t.Run("key hash as secret", func(t *testing.T) {
+		// 数据库中的摘要不能直接用来伪造签名
+		r, body := newSigned("{}")
+		assert.ErrorIs(t, v.Verify(ctx, r, body, []byte(key.Hash), signer.keyID), ErrMismatch)
+	})
+
+	t.Run("stale timestamp", func(t *testing.T) {
+		signer.now = func() time.Time { return time.Now().Add(-10 * time.Minute) }
+		defer func() { signer.now = time.Now }()
+		r, body := newSigned("{}")
+		assert.ErrorIs(t, v.Verify(ctx, r, body, secret, signer.keyID), ErrStale)
+	})
+
+	t.Run("missing headers", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodGet, "/api/v1/api/data", nil)
+		assert.ErrorIs(t, v.Verify(ctx, r, nil, secret, signer.keyID), ErrMissing)
+	})
+}
+
+func TestCanonicalQuery(t *testing.T) {
+	q := url.Values{"b": {"2"}, "a": {"z", "y"}, "c d": {"e&f"}}
+	assert.Equal(t, "a=y&a=z&b=2&c+d=e%26f", canonicalQuery(q))
+}
@@ -0,0 +1,75 @@
+package token
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"time"
+
+	pkgredis "github.com/liuchen/gin-craft/pkg/redis"
+	"github.com/redis/go-redis/v9"
+)
+
+const denylistKeyPrefix = "auth:deny:"
+
+// Denylist 基于 Redis 的访问令牌吊销名单，所有实例共享。
+//   - 单个令牌：jti → 过期时间，条目随令牌自然过期一起消失
+//   - 整个用户：会话版本号自增，版本号更低的令牌全部失效
+type Denylist struct {
+	client *pkgredis.Client
+}
+
+// NewDenylist 创建吊销名单
+func NewDenylist(client *pkgredis.Client) *Denylist {
+	return &Denylist{client: client}
+}
+
+// Revoke 吊销单个令牌，直到其原本的过期时间
+func (d *Denylist) Revoke(ctx context.Context, jti string, expiresAt time.Time) error {
+	ttl := time.Until(expiresAt)
+	if ttl <= 0 {
+		return nil // 已自然过期，无需记录
+	}
+	return d.client.Set(ctx, d.jtiKey(jti), expiresAt.Unix(), ttl)
+}
+
+// RevokeUser 吊销用户当前所有访问令牌，返回新的会话版本号
+func (d *Denylist) RevokeUser(ctx context.Context, userID string) (int64, error) {
+	return d.client.Increment(ctx, d.versionKey(userID))
+}
+
+// UserVersion 获取用户当前会话版本号，签发令牌时写入 Claims.Version
+func (d *Denylist) UserVersion(ctx context.Context, userID string) (int64, error) {
+	v, err := d.client.Get(ctx, d.versionKey(userID))
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return 0, nil
+		}
+		return 0, err
+	}
+	return strconv.ParseInt(v, 10, 64)
+}
+
+// IsRevoked 判断令牌是否已被吊销
+func (d *Denylist) IsRevoked(ctx context.Context, claims *Claims) (bool, error) {
+	n, err := d.client.Exists(ctx, d.jtiKey(claims.ID))
+	if err != nil {
+		return false, err
+	}
+	if n > 0 {
+		return true, nil
+	}
+	current, err := d.UserVersion(ctx, claims.Subject)
+	if err != nil {
+		return false, err
+	}
+	return claims.Version < current, nil
+}
+
+func (d *Denylist) jtiKey(jti string) string {
+	return denylistKeyPrefix + "jti:" + jti
+}
+
+func (d *Denylist) versionKey(userID string) string {
+	return denylistKeyPrefix + "ver:" + userID
+}
@@ -0,0 +1,63 @@
+package token
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDenylistRevokeToken(t *testing.T) {
+	mr, client := newTestRedis(t)
+	denylist := NewDenylist(client)
+	m, err := NewManager(&Config{Secret: "test-secret", AccessTTL: 60})
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	_, claims, err := m.Issue(Identity{UserID: "42"})
+	require.NoError(t, err)
+
+	revoked, err := denylist.IsRevoked(ctx, claims)
+	require.NoError(t, err)
+	assert.False(t, revoked)
+
+	require.NoError(t, denylist.Revoke(ctx, claims.ID, claims.ExpiresAt.Time))
+	revoked, err = denylist.IsRevoked(ctx, claims)
+	require.NoError(t, err)
+	assert.True(t, revoked)
+
+	// 条目与令牌同时过期，不会无限堆积
+	mr.FastForward(2 * time.Minute)
+	assert.False(t, mr.Exists(denylistKeyPrefix+"jti:"+claims.ID))
+}
+
+func TestDenylistRevokeUser(t *testing.T) {
+	_, client := newTestRedis(t)
+	denylist := NewDenylist(client)
+	m, err := NewManager(&Config{Secret: "test-secret"})
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	version, err := denylist.UserVersion(ctx, "42")
+	require.NoError(t, err)
+	_, before, err := m.Issue(Identity{UserID: "42", Version: version})
+	require.NoError(t, err)
+
+	_, err = denylist.RevokeUser(ctx, "42")
+	require.NoError(t, err)
+
+	revoked, err := denylist.IsRevoked(ctx, before)
+	require.NoError(t, err)
+	assert.True(t, revoked)
+
+	// 吊销之后签发的令牌携带新版本号，不受影响
+	version, err = denylist.UserVersion(ctx, "42")
+	require.NoError(t, err)
+	_, after, err := m.Issue(Identity{UserID: "42", Version: version})
+	require.NoError(t, err)
+	revoked, err = denylist.IsRevoked(ctx, after)
+	require.NoError(t, err)
+	assert.False(t, revoked)
+}
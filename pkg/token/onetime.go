@@ -0,0 +1,124 @@
+package token
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	pkgredis "github.com/liuchen/gin-craft/pkg/redis"
+	"github.com/redis/go-redis/v9"
+)
+
+// ErrOneTimeInvalid 一次性令牌签名错误、已使用、已过期或已被新令牌取代
+var ErrOneTimeInvalid = errors.New("token: one-time token invalid")
+
+const oneTimeKeyPrefix = "auth:once:"
+
+// OneTimeStore 用途绑定的一次性令牌（重置密码、邮箱验证等）。
+// 令牌形如 <id>.<sig>：sig 为 HMAC-SHA256(secret, purpose.id)，伪造或跨用途的令牌无需查询 Redis 即被拒绝；
+// Redis 中以 id 的摘要保存令牌主体，消费时 GETDEL 保证只能使用一次。
+// 同一主体同一用途只保留最新签发的令牌。
+type OneTimeStore struct {
+	client *pkgredis.Client
+	secret []byte
+}
+
+// NewOneTimeStore 创建一次性令牌存储
+func NewOneTimeStore(client *pkgredis.Client, secret []byte) *OneTimeStore {
+	return &OneTimeStore{client: client, secret: secret}
+}
+
+// Issue 为主体签发一枚令牌，之前签发的同用途令牌随之失效
+func (s *OneTimeStore) Issue(ctx context.Context, purpose, subject string, ttl time.Duration) (string, error) {
+	b := make([]byte, 24)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("token: generate one-time token: %w", err)
+	}
+	id := base64.RawURLEncoding.EncodeToString(b)
+	digest := hashRefresh(id)
+
+	rdb := s.client.GetClient()
+	prev, err := rdb.GetSet(ctx, s.subjectKey(purpose, subject), digest).Result()
+	if err != nil && !errors.Is(err, redis.Nil) {
+		return "", err
+	}
+	if prev != "" {
+		if err := rdb.Del(ctx, s.tokenKey(purpose, prev)).Err(); err != nil {
+			return "", err
+		}
+	}
+	if err := rdb.Expire(ctx, s.subjectKey(purpose, subject), ttl).Err(); err != nil {
+		return "", err
+	}
+	if err := rdb.Set(ctx, s.tokenKey(purpose, digest), subject, ttl).Err(); err != nil {
+		return "", err
+	}
+	return id + "." + s.sign(purpose, id), nil
+}
+
+// Peek 校验令牌并返回主体但不消费，用于在消费前先校验请求中的其他参数
+func (s *OneTimeStore) Peek(ctx context.Context, purpose, raw string) (string, error) {
+	digest, ok := s.verify(purpose, raw)
+	if !ok {
+		return "", ErrOneTimeInvalid
+	}
+	subject, err := s.client.GetClient().Get(ctx, s.tokenKey(purpose, digest)).Result()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return "", ErrOneTimeInvalid
+		}
+		return "", err
+	}
+	return subject, nil
+}
+
+// Consume 校验并消费令牌，返回签发时的主体
+func (s *OneTimeStore) Consume(ctx context.Context, purpose, raw string) (string, error) {
+	digest, ok := s.verify(purpose, raw)
+	if !ok {
+		return "", ErrOneTimeInvalid
+	}
+
+	rdb := s.client.GetClient()
+	subject, err := rdb.GetDel(ctx, s.tokenKey(purpose, digest)).Result()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return "", ErrOneTimeInvalid
+		}
+		return "", err
+	}
+	// 仅当索引仍指向本令牌时清理，避免误删并发签发的新令牌
+	if cur, err := rdb.Get(ctx, s.subjectKey(purpose, subject)).Result(); err == nil && cur == digest {
+		_ = rdb.Del(ctx, s.subjectKey(purpose, subject)).Err()
+	}
+	return subject, nil
+}
+
+// verify 校验签名，通过时返回令牌 id 的摘要
+func (s *OneTimeStore) verify(purpose, raw string) (string, bool) {
+	id, sig, ok := strings.Cut(raw, ".")
+	if !ok || !hmac.Equal([]byte(sig), []byte(s.sign(purpose, id))) {
+		return "", false
+	}
+	return hashRefresh(id), true
+}
+
+func (s *OneTimeStore) sign(purpose, id string) string {
+	mac := hmac.New(sha256.New, s.secret)
+	mac.Write([]byte(purpose + "." + id))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+func (s *OneTimeStore) tokenKey(purpose, digest string) string {
+	return oneTimeKeyPrefix + purpose + ":token:" + digest
+}
+
+func (s *OneTimeStore) subjectKey(purpose, subject string) string {
+	return oneTimeKeyPrefix + purpose + ":subject:" + subject
+}
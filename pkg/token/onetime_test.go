@@ -0,0 +1,80 @@
+package token
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOneTimeConsume(t *testing.T) {
+	_, client := newTestRedis(t)
+	store := NewOneTimeStore(client, []byte("secret"))
+	ctx := context.Background()
+
+	raw, err := store.Issue(ctx, "reset", "42", time.Hour)
+	require.NoError(t, err)
+
+	// 用途不同，签名不匹配
+	_, err = store.Consume(ctx, "verify", raw)
+	assert.ErrorIs(t, err, ErrOneTimeInvalid)
+
+	// Peek 不消费令牌
+	subject, err := store.Peek(ctx, "reset", raw)
+	require.NoError(t, err)
+	assert.Equal(t, "42", subject)
+
+	subject, err = store.Consume(ctx, "reset", raw)
+	require.NoError(t, err)
+	assert.Equal(t, "42", subject)
+
+	// 只能使用一次
+	_, err = store.Peek(ctx, "reset", raw)
+	assert.ErrorIs(t, err, ErrOneTimeInvalid)
+	_, err = store.Consume(ctx, "reset", raw)
+	assert.ErrorIs(t, err, ErrOneTimeInvalid)
+}
+
+func TestOneTimeRejectsForgedAndExpired(t *testing.T) {
+	mr, client := newTestRedis(t)
+	ctx := context.Background()
+	store := NewOneTimeStore(client, []byte("secret"))
+
+	raw, err := store.Issue(ctx, "reset", "42", time.Minute)
+	require.NoError(t, err)
+
+	other := NewOneTimeStore(client, []byte("other"))
+	_, err = other.Consume(ctx, "reset", raw)
+	assert.ErrorIs(t, err, ErrOneTimeInvalid)
+
+	id, _, _ := strings.Cut(raw, ".")
+	for _, forged := range []string{"", id, id + ".", id + ".AAAA"} {
+		_, err = store.Consume(ctx, "reset", forged)
+		assert.ErrorIs(t, err, ErrOneTimeInvalid, forged)
+	}
+
+	mr.FastForward(2 * time.Minute)
+	_, err = store.Consume(ctx, "reset", raw)
+	assert.ErrorIs(t, err, ErrOneTimeInvalid)
+}
+
+func TestOneTimeReissueInvalidatesPrevious(t *testing.T) {
+	_, client := newTestRedis(t)
+	store := NewOneTimeStore(client, []byte("secret"))
+	ctx := context.Background()
+
+	first, err := store.Issue(ctx, "reset", "42", time.Hour)
+	require.NoError(t, err)
+	second, err := store.Issue(ctx, "reset", "42", time.Hour)
+	require.NoError(t, err)
+
+	_, err = store.Consume(ctx, "reset", first)
+	assert.ErrorIs(t, err, ErrOneTimeInvalid)
+
+	subject, err := store.Consume(ctx, "reset", second)
+	require.NoError(t, err)
+	assert.Equal(t, "42", subject)
+}
@@ -0,0 +1,211 @@
+package token
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	pkgredis "github.com/liuchen/gin-craft/pkg/redis"
+	"github.com/redis/go-redis/v9"
+)
+
+var (
+	// ErrRefreshInvalid 刷新令牌不存在、已过期或所属令牌族已被吊销
+	ErrRefreshInvalid = errors.New("token: refresh token invalid")
+	// ErrRefreshReused 已使用过的刷新令牌被再次提交，整个令牌族随之吊销
+	ErrRefreshReused = errors.New("token: refresh token reused")
+)
+
+const refreshKeyPrefix = "auth:refresh:"
+
+// issueScript 写入新令牌、续期令牌族并登记到用户名下。轮换时（ARGV[5] 为旧令牌剩余毫秒数）先在同一脚本内
+// 确认令牌族未被吊销并以 SETNX 标记旧令牌已使用：重放时删除令牌族返回 -1，令牌族已不存在时返回 0，
+// 不会重建刚被重放检测吊销的令牌族。
+// KEYS: token, family, user, used；ARGV: session JSON, ttl 毫秒, familyID, userID, 旧令牌剩余毫秒（签发新族时为 0）
+var issueScript = redis.NewScript(`
+if ARGV[5] ~= '0' then
+  if redis.call('EXISTS', KEYS[2]) == 0 then
+    return 0
+  end
+  if not redis.call('SET', KEYS[4], 1, 'NX', 'PX', ARGV[5]) then
+    redis.call('DEL', KEYS[2])
+    return -1
+  end
+end
+redis.call('SET', KEYS[1], ARGV[1], 'PX', ARGV[2])
+redis.call('SET', KEYS[2], ARGV[4], 'PX', ARGV[2])
+redis.call('SADD', KEYS[3], ARGV[3])
+redis.call('PEXPIRE', KEYS[3], ARGV[2])
+return 1
+`)
+
+// RefreshSession 刷新令牌对应的会话信息
+type RefreshSession struct {
+	FamilyID  string    `json:"family_id"`
+	UserID    string    `json:"user_id"`
+	Username  string    `json:"username"`
+	Role      string    `json:"role"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// RefreshStore 基于 Redis 的不透明刷新令牌存储。
+// 每次使用都会轮换出新令牌；同一令牌族内的旧令牌被重放时，整族吊销。
+// Redis 中仅保存令牌的 SHA-256 摘要，不落明文。
+type RefreshStore struct {
+	client *pkgredis.Client
+	ttl    time.Duration
+}
+
+// NewRefreshStore 创建刷新令牌存储，ttl 为单个刷新令牌的有效期
+func NewRefreshStore(client *pkgredis.Client, ttl time.Duration) *RefreshStore {
+	if ttl <= 0 {
+		ttl = 30 * 24 * time.Hour
+	}
+	return &RefreshStore{client: client, ttl: ttl}
+}
+
+// TTL 刷新令牌有效期
+func (s *RefreshStore) TTL() time.Duration {
+	return s.ttl
+}
+
+// Issue 为用户开启新的令牌族并签发第一枚刷新令牌
+func (s *RefreshStore) Issue(ctx context.Context, userID, username, role string) (string, *RefreshSession, error) {
+	return s.issue(ctx, &RefreshSession{
+		FamilyID: uuid.New().String(),
+		UserID:   userID,
+		Username: username,
+		Role:     role,
+	}, "", 0)
+}
+
+// Rotate 消费一枚刷新令牌并在同一令牌族内签发新令牌。
+// 令牌已被使用过时吊销整个令牌族并返回 ErrRefreshReused。
+// 令牌族存活检查、旧令牌标记与新令牌写入在同一 Lua 脚本中完成，并发的合法轮换不会重建已被吊销的令牌族。
+func (s *RefreshStore) Rotate(ctx context.Context, raw string) (string, *RefreshSession, error) {
+	digest := hashRefresh(raw)
+
+	var session RefreshSession
+	if err := s.client.GetJSON(ctx, s.tokenKey(digest), &session); err != nil {
+		if errors.Is(err, pkgredis.ErrRedisKeyNotFound) {
+			return "", nil, ErrRefreshInvalid
+		}
+		return "", nil, err
+	}
+	remaining := time.Until(session.ExpiresAt)
+	if remaining < time.Millisecond {
+		return "", nil, ErrRefreshInvalid
+	}
+
+	next, rotated, err := s.issue(ctx, &RefreshSession{
+		FamilyID: session.FamilyID,
+		UserID:   session.UserID,
+		Username: session.Username,
+		Role:     session.Role,
+	}, digest, remaining)
+	if errors.Is(err, ErrRefreshReused) {
+		return "", &session, err
+	}
+	return next, rotated, err
+}
+
+// Lookup 查询刷新令牌对应的会话（不消费令牌）
+func (s *RefreshStore) Lookup(ctx context.Context, raw string) (*RefreshSession, error) {
+	var session RefreshSession
+	if err := s.client.GetJSON(ctx, s.tokenKey(hashRefresh(raw)), &session); err != nil {
+		if errors.Is(err, pkgredis.ErrRedisKeyNotFound) {
+			return nil, ErrRefreshInvalid
+		}
+		return nil, err
+	}
+	return &session, nil
+}
+
+// RevokeFamily 吊销整个令牌族，族内所有刷新令牌立即失效
+func (s *RefreshStore) RevokeFamily(ctx context.Context, familyID string) error {
+	return s.client.Del(ctx, s.familyKey(familyID))
+}
+
+// RevokeUser 吊销用户名下所有令牌族
+func (s *RefreshStore) RevokeUser(ctx context.Context, userID string) error {
+	families, err := s.client.SMembers(ctx, s.userKey(userID))
+	if err != nil {
+		return err
+	}
+	keys := make([]string, 0, len(families)+1)
+	for _, f := range families {
+		keys = append(keys, s.familyKey(f))
+	}
+	keys = append(keys, s.userKey(userID))
+	return s.client.Del(ctx, keys...)
+}
+
+// issue 签发令牌族内的新令牌。usedDigest 为空时开启新令牌族；否则为被轮换的旧令牌，
+// 令牌族已不存在时返回 ErrRefreshInvalid（不重建），旧令牌已被使用过时吊销令牌族并返回 ErrRefreshReused
+func (s *RefreshStore) issue(ctx context.Context, session *RefreshSession, usedDigest string, usedTTL time.Duration) (string, *RefreshSession, error) {
+	rdb := s.client.GetClient()
+	if rdb == nil {
+		return "", nil, fmt.Errorf("redis not connected")
+	}
+	raw, err := newOpaqueToken()
+	if err != nil {
+		return "", nil, err
+	}
+	session.ExpiresAt = time.Now().Add(s.ttl)
+	data, err := json.Marshal(session)
+	if err != nil {
+		return "", nil, err
+	}
+
+	// 令牌族标记随每次轮换续期，吊销即删除该标记；用户名下的令牌族集合供 RevokeUser 一次性吊销
+	keys := []string{s.tokenKey(hashRefresh(raw)), s.familyKey(session.FamilyID), s.userKey(session.UserID), s.usedKey(usedDigest)}
+	res, err := issueScript.Run(ctx, rdb, keys,
+		data, s.ttl.Milliseconds(), session.FamilyID, session.UserID, usedTTL.Milliseconds()).Int()
+	if err != nil {
+		return "", nil, fmt.Errorf("token: issue refresh token: %w", err)
+	}
+	switch res {
+	case 0:
+		return "", nil, ErrRefreshInvalid
+	case -1:
+		return "", nil, ErrRefreshReused
+	}
+	return raw, session, nil
+}
+
+func (s *RefreshStore) tokenKey(digest string) string {
+	return refreshKeyPrefix + "token:" + digest
+}
+
+func (s *RefreshStore) usedKey(digest string) string {
+	return refreshKeyPrefix + "used:" + digest
+}
+
+func (s *RefreshStore) familyKey(familyID string) string {
+	return refreshKeyPrefix + "family:" + familyID
+}
+
+func (s *RefreshStore) userKey(userID string) string {
+	return refreshKeyPrefix + "user:" + userID
+}
+
+func hashRefresh(raw string) string {
+	sum := sha256.Sum256([]byte(raw))
+	return hex.EncodeToString(sum[:])
+}
+
+// newOpaqueToken 生成 256 bit 随机令牌（base64url，无填充）
+func newOpaqueToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("token: generate refresh token: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
@@ -0,0 +1,148 @@
+package token
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	pkgredis "github.com/liuchen/gin-craft/pkg/redis"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestRedis 启动内存版 Redis 并返回已连接的客户端
+func newTestRedis(t *testing.T) (*miniredis.Miniredis, *pkgredis.Client) {
+	t.Helper()
+	mr := miniredis.RunT(t)
+	port, err := strconv.Atoi(mr.Port())
+	require.NoError(t, err)
+
+	client := pkgredis.NewClient(&pkgredis.Config{Host: mr.Host(), Port: port})
+	require.NoError(t, client.Connect())
+	t.Cleanup(func() { _ = client.Close() })
+	return mr, client
+}
+
+func TestRefreshRotate(t *testing.T) {
+	_, client := newTestRedis(t)
+	store := NewRefreshStore(client, time.Hour)
+	ctx := context.Background()
+
+	first, session, err := store.Issue(ctx, "42", "john", "user")
+	require.NoError(t, err)
+	assert.NotEmpty(t, first)
+
+	second, rotated, err := store.Rotate(ctx, first)
+	require.NoError(t, err)
+	assert.NotEqual(t, first, second)
+	assert.Equal(t, session.FamilyID, rotated.FamilyID)
+	assert.Equal(t, "42", rotated.UserID)
+
+	third, _, err := store.Rotate(ctx, second)
+	require.NoError(t, err)
+	assert.NotEmpty(t, third)
+}
+
+func TestRefreshReuseRevokesFamily(t *testing.T) {
+	_, client := newTestRedis(t)
+	store := NewRefreshStore(client, time.Hour)
+	ctx := context.Background()
+
+	first, _, err := store.Issue(ctx, "42", "john", "user")
+	require.NoError(t, err)
+	second, _, err := store.Rotate(ctx, first)
+	require.NoError(t, err)
+
+	// 旧令牌被重放：判定为泄露，整族吊销
+	_, _, err = store.Rotate(ctx, first)
+	assert.ErrorIs(t, err, ErrRefreshReused)
+
+	// 合法持有者手里的新令牌也随之失效
+	_, _, err = store.Rotate(ctx, second)
+	assert.ErrorIs(t, err, ErrRefreshInvalid)
+}
+
+func TestRefreshInvalid(t *testing.T) {
+	mr, client := newTestRedis(t)
+	store := NewRefreshStore(client, time.Minute)
+	ctx := context.Background()
+
+	_, _, err := store.Rotate(ctx, "unknown")
+	assert.ErrorIs(t, err, ErrRefreshInvalid)
+
+	raw, _, err := store.Issue(ctx, "42", "john", "user")
+	require.NoError(t, err)
+	mr.FastForward(2 * time.Minute)
+	_, _, err = store.Rotate(ctx, raw)
+	assert.ErrorIs(t, err, ErrRefreshInvalid)
+
+	raw, session, err := store.Issue(ctx, "42", "john", "user")
+	require.NoError(t, err)
+	require.NoError(t, store.RevokeFamily(ctx, session.FamilyID))
+	_, _, err = store.Rotate(ctx, raw)
+	assert.ErrorIs(t, err, ErrRefreshInvalid)
+}
+
+func TestRefreshRevokeUser(t *testing.T) {
+	_, client := newTestRedis(t)
+	store := NewRefreshStore(client, time.Hour)
+	ctx := context.Background()
+
+	phone, _, err := store.Issue(ctx, "42", "john", "user")
+	require.NoError(t, err)
+	laptop, _, err := store.Issue(ctx, "42", "john", "user")
+	require.NoError(t, err)
+	other, _, err := store.Issue(ctx, "7", "jane", "user")
+	require.NoError(t, err)
+
+	require.NoError(t, store.RevokeUser(ctx, "42"))
+
+	_, _, err = store.Rotate(ctx, phone)
+	assert.ErrorIs(t, err, ErrRefreshInvalid)
+	_, _, err = store.Rotate(ctx, laptop)
+	assert.ErrorIs(t, err, ErrRefreshInvalid)
+	_, _, err = store.Rotate(ctx, other)
+	assert.NoError(t, err)
+}
+
+func TestRefreshReuseRaceKeepsFamilyRevoked(t *testing.T) {
+	mr, client := newTestRedis(t)
+	store := NewRefreshStore(client, time.Hour)
+	ctx := context.Background()
+
+	for i := 0; i < 20; i++ {
+		first, session, err := store.Issue(ctx, "42", "john", "user")
+		require.NoError(t, err)
+		second, _, err := store.Rotate(ctx, first)
+		require.NoError(t, err)
+
+		// 攻击者重放旧令牌的同时，合法持有者轮换新令牌：无论先后，令牌族最终都必须处于吊销状态
+		var wg sync.WaitGroup
+		wg.Add(2)
+		go func() { defer wg.Done(); _, _, _ = store.Rotate(ctx, first) }()
+		go func() { defer wg.Done(); _, _, _ = store.Rotate(ctx, second) }()
+		wg.Wait()
+
+		assert.False(t, mr.Exists(store.familyKey(session.FamilyID)), "family %d re-created after reuse", i)
+	}
+}
+
+func TestRefreshRotateRevokedFamily(t *testing.T) {
+	mr, client := newTestRedis(t)
+	store := NewRefreshStore(client, time.Hour)
+	ctx := context.Background()
+
+	raw, session, err := store.Issue(ctx, "42", "john", "user")
+	require.NoError(t, err)
+	require.NoError(t, store.RevokeFamily(ctx, session.FamilyID))
+
+	// 轮换不会重建已吊销的令牌族，也不会留下新令牌
+	keys := len(mr.Keys())
+	_, _, err = store.Rotate(ctx, raw)
+	assert.ErrorIs(t, err, ErrRefreshInvalid)
+	assert.False(t, mr.Exists(store.familyKey(session.FamilyID)))
+	assert.Len(t, mr.Keys(), keys)
+}
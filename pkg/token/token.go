@@ -0,0 +1,182 @@
+package token
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+// 支持的签名算法
+const (
+	AlgHS256 = "HS256"
+	AlgRS256 = "RS256"
+	AlgEdDSA = "EdDSA"
+)
+
+var (
+	// ErrTokenExpired 令牌已过期
+	ErrTokenExpired = errors.New("token: expired")
+	// ErrTokenInvalid 令牌无效（签名错误、格式错误、算法不匹配等）
+	ErrTokenInvalid = errors.New("token: invalid")
+)
+
+// Config JWT 配置
+type Config struct {
+	Algorithm      string // HS256 / RS256 / EdDSA
+	Secret         string // HS256 使用的密钥
+	PrivateKeyFile string // RS256 / EdDSA 私钥（PEM），仅做校验的实例可留空
+	PublicKeyFile  string // RS256 / EdDSA 公钥（PEM）
+	Issuer         string
+	AccessTTL      int // 访问令牌有效期(秒)
+}
+
+// Identity 签发令牌所需的身份信息
+type Identity struct {
+	UserID   string
+	Username string
+	Role     string
+	Version  int64 // 会话版本号，见 Denylist.RevokeUser
+}
+
+// Claims 访问令牌载荷：Subject 存放用户 ID，ID 存放 jti
+type Claims struct {
+	Username string `json:"username"`
+	Role     string `json:"role"`
+	Version  int64  `json:"ver,omitempty"`
+	jwt.RegisteredClaims
+}
+
+// Manager 负责签发与校验令牌
+type Manager struct {
+	config    *Config
+	method    jwt.SigningMethod
+	signKey   interface{}
+	verifyKey interface{}
+}
+
+// NewManager 创建令牌管理器，按算法加载对应的密钥
+func NewManager(config *Config) (*Manager, error) {
+	if config.Algorithm == "" {
+		config.Algorithm = AlgHS256
+	}
+	if config.AccessTTL <= 0 {
+		config.AccessTTL = 7200
+	}
+
+	m := &Manager{config: config}
+	var err error
+	switch config.Algorithm {
+	case AlgHS256:
+		if config.Secret == "" {
+			return nil, fmt.Errorf("token: secret is required for %s", AlgHS256)
+		}
+		m.method = jwt.SigningMethodHS256
+		m.signKey = []byte(config.Secret)
+		m.verifyKey = []byte(config.Secret)
+	case AlgRS256:
+		m.method = jwt.SigningMethodRS256
+		err = m.loadKeys(
+			func(b []byte) (interface{}, error) { return jwt.ParseRSAPrivateKeyFromPEM(b) },
+			func(b []byte) (interface{}, error) { return jwt.ParseRSAPublicKeyFromPEM(b) },
+		)
+	case AlgEdDSA:
+		m.method = jwt.SigningMethodEdDSA
+		err = m.loadKeys(
+			func(b []byte) (interface{}, error) { return jwt.ParseEdPrivateKeyFromPEM(b) },
+			func(b []byte) (interface{}, error) { return jwt.ParseEdPublicKeyFromPEM(b) },
+		)
+	default:
+		return nil, fmt.Errorf("token: unsupported algorithm %q", config.Algorithm)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// loadKeys 从 PEM 文件加载非对称密钥；公钥必填，私钥可选
+func (m *Manager) loadKeys(parsePriv, parsePub func([]byte) (interface{}, error)) error {
+	if m.config.PublicKeyFile == "" {
+		return fmt.Errorf("token: public_key_file is required for %s", m.config.Algorithm)
+	}
+	pub, err := os.ReadFile(m.config.PublicKeyFile)
+	if err != nil {
+		return fmt.Errorf("token: read public key: %w", err)
+	}
+	if m.verifyKey, err = parsePub(pub); err != nil {
+		return fmt.Errorf("token: parse public key: %w", err)
+	}
+
+	if m.config.PrivateKeyFile == "" {
+		return nil
+	}
+	priv, err := os.ReadFile(m.config.PrivateKeyFile)
+	if err != nil {
+		return fmt.Errorf("token: read private key: %w", err)
+	}
+	if m.signKey, err = parsePriv(priv); err != nil {
+		return fmt.Errorf("token: parse private key: %w", err)
+	}
+	return nil
+}
+
+// AccessTTL 访问令牌有效期
+func (m *Manager) AccessTTL() time.Duration {
+	return time.Duration(m.config.AccessTTL) * time.Second
+}
+
+// Issue 签发访问令牌，返回令牌字符串及其载荷
+func (m *Manager) Issue(id Identity) (string, *Claims, error) {
+	if m.signKey == nil {
+		return "", nil, fmt.Errorf("token: private key not configured, cannot sign")
+	}
+	now := time.Now()
+	claims := &Claims{
+		Username: id.Username,
+		Role:     id.Role,
+		Version:  id.Version,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        uuid.New().String(),
+			Issuer:    m.config.Issuer,
+			Subject:   id.UserID,
+			IssuedAt:  jwt.NewNumericDate(now),
+			NotBefore: jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(m.AccessTTL())),
+		},
+	}
+	signed, err := jwt.NewWithClaims(m.method, claims).SignedString(m.signKey)
+	if err != nil {
+		return "", nil, fmt.Errorf("token: sign: %w", err)
+	}
+	return signed, claims, nil
+}
+
+// Parse 校验令牌并返回载荷；过期返回 ErrTokenExpired，其余失败返回 ErrTokenInvalid
+func (m *Manager) Parse(tokenString string) (*Claims, error) {
+	opts := []jwt.ParserOption{
+		jwt.WithValidMethods([]string{m.method.Alg()}),
+		jwt.WithExpirationRequired(),
+	}
+	if m.config.Issuer != "" {
+		opts = append(opts, jwt.WithIssuer(m.config.Issuer))
+	}
+
+	claims := &Claims{}
+	_, err := jwt.ParseWithClaims(tokenString, claims, func(*jwt.Token) (interface{}, error) {
+		return m.verifyKey, nil
+	}, opts...)
+	if err != nil {
+		if errors.Is(err, jwt.ErrTokenExpired) {
+			return nil, ErrTokenExpired
+		}
+		return nil, fmt.Errorf("%w: %v", ErrTokenInvalid, err)
+	}
+	if claims.Subject == "" {
+		return nil, fmt.Errorf("%w: missing subject", ErrTokenInvalid)
+	}
+	return claims, nil
+}
@@ -0,0 +1,130 @@
+package token
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeKeyPair 将密钥对以 PEM 格式写入临时目录
+func writeKeyPair(t *testing.T, priv, pub interface{}) (string, string) {
+	t.Helper()
+	dir := t.TempDir()
+
+	privDER, err := x509.MarshalPKCS8PrivateKey(priv)
+	require.NoError(t, err)
+	pubDER, err := x509.MarshalPKIXPublicKey(pub)
+	require.NoError(t, err)
+
+	privFile := filepath.Join(dir, "private.pem")
+	pubFile := filepath.Join(dir, "public.pem")
+	require.NoError(t, os.WriteFile(privFile, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: privDER}), 0o600))
+	require.NoError(t, os.WriteFile(pubFile, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: pubDER}), 0o644))
+	return privFile, pubFile
+}
+
+func TestIssueAndParse(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	rsaPriv, rsaPub := writeKeyPair(t, rsaKey, &rsaKey.PublicKey)
+
+	edPub, edKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	edPrivFile, edPubFile := writeKeyPair(t, edKey, edPub)
+
+	tests := []struct {
+		name   string
+		config *Config
+	}{
+		{"HS256", &Config{Algorithm: AlgHS256, Secret: "test-secret", Issuer: "test"}},
+		{"RS256", &Config{Algorithm: AlgRS256, PrivateKeyFile: rsaPriv, PublicKeyFile: rsaPub, Issuer: "test"}},
+		{"EdDSA", &Config{Algorithm: AlgEdDSA, PrivateKeyFile: edPrivFile, PublicKeyFile: edPubFile, Issuer: "test"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, err := NewManager(tt.config)
+			require.NoError(t, err)
+
+			signed, issued, err := m.Issue(Identity{UserID: "42", Username: "john", Role: "user"})
+			require.NoError(t, err)
+			assert.NotEmpty(t, issued.ID)
+
+			claims, err := m.Parse(signed)
+			require.NoError(t, err)
+			assert.Equal(t, "42", claims.Subject)
+			assert.Equal(t, "john", claims.Username)
+			assert.Equal(t, "user", claims.Role)
+			assert.Equal(t, issued.ID, claims.ID)
+		})
+	}
+}
+
+func TestParseExpired(t *testing.T) {
+	m, err := NewManager(&Config{Secret: "test-secret"})
+	require.NoError(t, err)
+
+	claims := &Claims{RegisteredClaims: jwt.RegisteredClaims{
+		Subject:   "42",
+		ExpiresAt: jwt.NewNumericDate(time.Now().Add(-time.Minute)),
+	}}
+	signed, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte("test-secret"))
+	require.NoError(t, err)
+
+	_, err = m.Parse(signed)
+	assert.ErrorIs(t, err, ErrTokenExpired)
+}
+
+func TestParseInvalid(t *testing.T) {
+	m, err := NewManager(&Config{Secret: "test-secret", Issuer: "test"})
+	require.NoError(t, err)
+
+	other, err := NewManager(&Config{Secret: "other-secret", Issuer: "test"})
+	require.NoError(t, err)
+	forged, _, err := other.Issue(Identity{UserID: "42", Username: "john", Role: "user"})
+	require.NoError(t, err)
+
+	wrongIssuer, err := NewManager(&Config{Secret: "test-secret", Issuer: "someone-else"})
+	require.NoError(t, err)
+	foreign, _, err := wrongIssuer.Issue(Identity{UserID: "42", Username: "john", Role: "user"})
+	require.NoError(t, err)
+
+	none, err := jwt.NewWithClaims(jwt.SigningMethodNone, &Claims{RegisteredClaims: jwt.RegisteredClaims{
+		Subject:   "42",
+		ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+	}}).SignedString(jwt.UnsafeAllowNoneSignatureType)
+	require.NoError(t, err)
+
+	for name, raw := range map[string]string{
+		"garbage":      "not-a-token",
+		"wrong secret": forged,
+		"wrong issuer": foreign,
+		"alg none":     none,
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := m.Parse(raw)
+			assert.ErrorIs(t, err, ErrTokenInvalid)
+		})
+	}
+}
+
+func TestNewManagerConfigErrors(t *testing.T) {
+	_, err := NewManager(&Config{Algorithm: AlgHS256})
+	assert.Error(t, err)
+
+	_, err = NewManager(&Config{Algorithm: AlgRS256})
+	assert.Error(t, err)
+
+	_, err = NewManager(&Config{Algorithm: "PS512", Secret: "x"})
+	assert.Error(t, err)
+}
@@ -0,0 +1,76 @@
+package totp
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// RFC 6238 附录 B 的 SHA1 测试向量（密钥 "12345678901234567890"），取 6 位
+func TestCodeRFC6238(t *testing.T) {
+	secret := b32.EncodeToString([]byte("12345678901234567890"))
+	tests := map[int64]string{
+		59:          "287082",
+		1111111109:  "081804",
+		1111111111:  "050471",
+		1234567890:  "005924",
+		2000000000:  "279037",
+		20000000000: "353130",
+	}
+	for unix, expected := range tests {
+		code, err := Code(secret, Step(time.Unix(unix, 0)))
+		require.NoError(t, err)
+		assert.Equal(t, expected, code, unix)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	secret, err := GenerateSecret()
+	require.NoError(t, err)
+	now := time.Unix(1700000000, 0)
+
+	code, err := Code(secret, Step(now))
+	require.NoError(t, err)
+	step, ok := Validate(secret, code, now, 1)
+	assert.True(t, ok)
+	assert.Equal(t, Step(now), step)
+
+	// 前一个时间步在 skew 内仍然有效，两个时间步之前失效
+	prev, _ := Code(secret, Step(now)-1)
+	_, ok = Validate(secret, prev, now, 1)
+	assert.True(t, ok)
+	old, _ := Code(secret, Step(now)-2)
+	_, ok = Validate(secret, old, now, 1)
+	assert.False(t, ok)
+
+	_, ok = Validate(secret, "12345", now, 1)
+	assert.False(t, ok)
+}
+
+func TestURIAndRecoveryCodes(t *testing.T) {
+	uri := URI("GinCraft", "alice@example.com", "JBSWY3DPEHPK3PXP")
+	assert.True(t, strings.HasPrefix(uri, "otpauth://totp/GinCraft:alice@example.com?"))
+	assert.Contains(t, uri, "secret=JBSWY3DPEHPK3PXP")
+	assert.Contains(t, uri, "issuer=GinCraft")
+
+	codes, err := GenerateRecoveryCodes(10)
+	require.NoError(t, err)
+	assert.Len(t, codes, 10)
+	seen := map[string]bool{}
+	for _, c := range codes {
+		assert.Regexp(t, `^[a-z2-7]{5}-[a-z2-7]{5}-[a-z2-7]{5}$`, c)
+		assert.False(t, seen[c])
+		seen[c] = true
+		lookup, ok := RecoveryCodeLookup(strings.ToUpper(c))
+		assert.True(t, ok)
+		assert.Equal(t, c[:5], lookup)
+	}
+
+	for _, bad := range []string{"", "abcde-fghij", "abcde-fghij-klmn", "abcde-fghij-klmn1", "abcde-fghij-klmno-pqrst"} {
+		_, ok := RecoveryCodeLookup(bad)
+		assert.False(t, ok, bad)
+	}
+}